@@ -10,6 +10,7 @@ import (
 	"github.com/bricksocoolxd/bengi-investment-system/module/instrument/repository"
 	instrumentRoutes "github.com/bricksocoolxd/bengi-investment-system/module/instrument/routes"
 	"github.com/bricksocoolxd/bengi-investment-system/module/instrument/service"
+	marketRoutes "github.com/bricksocoolxd/bengi-investment-system/module/market/routes"
 	orderRoutes "github.com/bricksocoolxd/bengi-investment-system/module/order/routes"
 	portfolioRoutes "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/routes"
 	tradeRoutes "github.com/bricksocoolxd/bengi-investment-system/module/trade/routes"
@@ -63,19 +64,21 @@ func main() {
 		})
 	})
 
+	// WebSocket routes. The bus must be up before the trade module
+	// recovers the matching engine, since recovered fills are published.
+	ws.RegisterRoutes(app)
+
 	// Register modules
 	authRoutes.RegisterRoutes(app)
 	accountRoutes.RegisterRoutes(app)
 	accountRoutes.RegisterDemoRoutes(app) // Demo trading routes
 	instrumentRoutes.RegisterRoutes(app)
+	marketRoutes.RegisterRoutes(app)
 	portfolioRoutes.RegisterRoutes(app)
 	orderRoutes.RegisterRoutes(app)
 	tradeRoutes.RegisterRoutes(app)
 	watchlistRoutes.RegisterRoutes(app)
 
-	// WebSocket routes
-	ws.RegisterRoutes(app)
-
 	// Start server
 	log.Printf("🚀 Server starting on port %s", config.AppConfig.Port)
 	log.Fatal(app.Listen(":" + config.AppConfig.Port))
@@ -4,7 +4,10 @@ import (
 	"errors"
 
 	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/payment"
 	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/utils"
@@ -73,6 +76,28 @@ func (ctrl *AccountController) GetAccountByID(c *fiber.Ctx) error {
 	return common.Success(c, result, "")
 }
 
+// SetSelfTradeMode sets the account's default self-trade prevention mode
+// PUT /api/v1/accounts/:id/self-trade-mode
+func (ctrl *AccountController) SetSelfTradeMode(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	accountID := c.Params("id")
+	var req dto.SelfTradeModeRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.accountService.SetSelfTradeMode(c.Context(), accountID, userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			return common.NotFound(c, "Account not found")
+		}
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, result, "Self-trade prevention updated")
+}
+
 func (ctrl *AccountController) Deposit(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
@@ -90,10 +115,17 @@ func (ctrl *AccountController) Deposit(c *fiber.Ctx) error {
 			return common.NotFound(c, "Account not found")
 		case errors.Is(err, service.ErrAccountFrozen):
 			return common.BadRequest(c, "Account is frozen")
+		case errors.Is(err, service.ErrAccountClosed):
+			return common.BadRequest(c, "Account is closed")
+		case errors.Is(err, payment.ErrNoProvider):
+			return common.BadRequest(c, "Live deposits are not available")
 		default:
 			return common.InternalError(c, err.Error())
 		}
 	}
+	if result.Status != string(model.TransactionStatusCompleted) {
+		return common.Success(c, result, "Deposit pending confirmation")
+	}
 	return common.Success(c, result, "Deposit successful")
 }
 
@@ -114,15 +146,72 @@ func (ctrl *AccountController) Withdraw(c *fiber.Ctx) error {
 			return common.NotFound(c, "Account not found")
 		case errors.Is(err, service.ErrAccountFrozen):
 			return common.BadRequest(c, "Account is frozen")
+		case errors.Is(err, service.ErrAccountClosed):
+			return common.BadRequest(c, "Account is closed")
 		case errors.Is(err, service.ErrInsufficientBalance):
 			return common.BadRequest(c, "Insufficient balance")
+		case errors.Is(err, service.ErrUnsettledFunds):
+			return common.BadRequest(c, "Amount exceeds settled cash; sale proceeds are still settling")
+		case errors.Is(err, service.ErrReservedFunds):
+			return common.BadRequest(c, "Amount exceeds free cash; cancel open buy orders to release it")
 		default:
 			return common.InternalError(c, err.Error())
 		}
 	}
+	switch result.Status {
+	case string(model.TransactionStatusPendingReview):
+		return common.Success(c, result, "Withdrawal awaiting review")
+	case string(model.TransactionStatusPending):
+		return common.Success(c, result, "Withdrawal pending payout")
+	case string(model.TransactionStatusFailed):
+		return common.BadRequest(c, "Withdrawal failed: "+result.FailureReason)
+	}
 	return common.Success(c, result, "Withdrawal successful")
 }
 
+// Transfer moves cash to another of the user's accounts, converting
+// currency if needed
+// POST /api/v1/accounts/:id/transfer
+func (ctrl *AccountController) Transfer(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	accountID := c.Params("id")
+	var req dto.TransferRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.accountService.Transfer(c.Context(), accountID, userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound):
+			return common.NotFound(c, "Account not found")
+		case errors.Is(err, service.ErrSameAccount):
+			return common.BadRequest(c, "Cannot transfer to the same account")
+		case errors.Is(err, service.ErrTransferAcrossTypes):
+			return common.BadRequest(c, "Cannot transfer between demo and live accounts")
+		case errors.Is(err, service.ErrAccountFrozen):
+			return common.BadRequest(c, "Account is frozen")
+		case errors.Is(err, service.ErrAccountClosed):
+			return common.BadRequest(c, "Account is closed")
+		case errors.Is(err, service.ErrInsufficientBalance):
+			return common.BadRequest(c, "Insufficient balance")
+		case errors.Is(err, service.ErrUnsettledFunds):
+			return common.BadRequest(c, "Amount exceeds settled cash; sale proceeds are still settling")
+		case errors.Is(err, service.ErrReservedFunds):
+			return common.BadRequest(c, "Amount exceeds free cash; cancel open buy orders to release it")
+		case errors.Is(err, service.ErrInvaludAmount):
+			return common.BadRequest(c, "Amount is too small to transfer")
+		case errors.Is(err, fx.ErrRateUnavailable):
+			return common.BadRequest(c, "No exchange rate between these currencies")
+		default:
+			return common.InternalError(c, err.Error())
+		}
+	}
+	return common.Success(c, result, "Transfer successful")
+}
+
 func (ctrl *AccountController) GetTransactions(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
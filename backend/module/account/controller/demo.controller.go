@@ -73,7 +73,7 @@ func (c *DemoController) Deposit(ctx *fiber.Ctx) error {
 		if err.Error() == "account not found" {
 			return common.NotFound(ctx, err.Error())
 		}
-		if errors.Is(err, service.ErrNotDemoAccount) {
+		if errors.Is(err, service.ErrNotDemoAccount) || errors.Is(err, service.ErrAccountFrozen) || errors.Is(err, service.ErrAccountClosed) {
 			return common.BadRequest(ctx, err.Error())
 		}
 		return common.InternalError(ctx, err.Error())
@@ -100,7 +100,7 @@ func (c *DemoController) Reset(ctx *fiber.Ctx) error {
 		if err.Error() == "account not found" {
 			return common.NotFound(ctx, err.Error())
 		}
-		if errors.Is(err, service.ErrNotDemoAccount) {
+		if errors.Is(err, service.ErrNotDemoAccount) || errors.Is(err, service.ErrAccountFrozen) || errors.Is(err, service.ErrAccountClosed) {
 			return common.BadRequest(ctx, err.Error())
 		}
 		return common.InternalError(ctx, err.Error())
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type LedgerController struct {
+	ledgerService *service.LedgerService
+}
+
+func NewLedgerController(ledgerService *service.LedgerService) *LedgerController {
+	return &LedgerController{
+		ledgerService: ledgerService,
+	}
+}
+
+// GetJournal returns the ledger entries behind an account's balance
+// GET /api/v1/accounts/:id/journal
+func (ctrl *LedgerController) GetJournal(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	accountID := c.Params("id")
+	limit := c.QueryInt("limit", 20)
+	offset := c.QueryInt("offset", 0)
+	result, err := ctrl.ledgerService.GetJournal(c.Context(), accountID, userID, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			return common.NotFound(c, "Account not found")
+		}
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, result, "")
+}
+
+// Reconcile compares an account's balance with its ledger
+// GET /api/v1/accounts/:id/reconcile
+func (ctrl *LedgerController) Reconcile(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	result, err := ctrl.ledgerService.Reconcile(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			return common.NotFound(c, "Account not found")
+		}
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, result, "")
+}
+
+// ReconcileAll flags every account whose balance drifted from the ledger
+// GET /api/v1/ledger/reconcile
+func (ctrl *LedgerController) ReconcileAll(c *fiber.Ctx) error {
+	result, err := ctrl.ledgerService.ReconcileAll(c.Context())
+	if err != nil {
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, result, "")
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type LifecycleController struct {
+	lifecycleService *service.LifecycleService
+}
+
+func NewLifecycleController(lifecycleService *service.LifecycleService) *LifecycleController {
+	return &LifecycleController{
+		lifecycleService: lifecycleService,
+	}
+}
+
+// Freeze suspends the user's account until they unfreeze it
+// POST /api/v1/accounts/:id/freeze
+func (ctrl *LifecycleController) Freeze(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	var req dto.StatusChangeRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.lifecycleService.Freeze(c.Context(), c.Params("id"), userID, &req)
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "Account frozen")
+}
+
+// Unfreeze lifts a freeze the user made
+// POST /api/v1/accounts/:id/unfreeze
+func (ctrl *LifecycleController) Unfreeze(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	var req dto.StatusChangeRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.lifecycleService.Unfreeze(c.Context(), c.Params("id"), userID, &req)
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "Account unfrozen")
+}
+
+// Close closes the user's account, sweeping its cash if asked to
+// POST /api/v1/accounts/:id/close
+func (ctrl *LifecycleController) Close(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	var req dto.CloseAccountRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.lifecycleService.Close(c.Context(), c.Params("id"), userID, &req)
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "Account closed")
+}
+
+// GetStatusHistory lists the account's freezes, closes and reopens
+// GET /api/v1/accounts/:id/status-history
+func (ctrl *LifecycleController) GetStatusHistory(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	result, err := ctrl.lifecycleService.GetStatusHistory(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "")
+}
+
+// AdminFreeze suspends any account until an administrator unfreezes it
+// POST /api/v1/lifecycle/accounts/:id/freeze
+func (ctrl *LifecycleController) AdminFreeze(c *fiber.Ctx) error {
+	var req dto.StatusChangeRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.lifecycleService.AdminFreeze(c.Context(), c.Params("id"), middleware.GetUserID(c), &req)
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "Account frozen")
+}
+
+// AdminUnfreeze lifts any freeze
+// POST /api/v1/lifecycle/accounts/:id/unfreeze
+func (ctrl *LifecycleController) AdminUnfreeze(c *fiber.Ctx) error {
+	var req dto.StatusChangeRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.lifecycleService.AdminUnfreeze(c.Context(), c.Params("id"), middleware.GetUserID(c), &req)
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "Account unfrozen")
+}
+
+// AdminClose closes any account
+// POST /api/v1/lifecycle/accounts/:id/close
+func (ctrl *LifecycleController) AdminClose(c *fiber.Ctx) error {
+	var req dto.CloseAccountRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.lifecycleService.AdminClose(c.Context(), c.Params("id"), middleware.GetUserID(c), &req)
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "Account closed")
+}
+
+// AdminReopen makes a closed account active again
+// POST /api/v1/lifecycle/accounts/:id/reopen
+func (ctrl *LifecycleController) AdminReopen(c *fiber.Ctx) error {
+	var req dto.StatusChangeRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+	result, err := ctrl.lifecycleService.AdminReopen(c.Context(), c.Params("id"), middleware.GetUserID(c), &req)
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "Account reopened")
+}
+
+// AdminGetStatusHistory lists any account's status changes
+// GET /api/v1/lifecycle/accounts/:id/history
+func (ctrl *LifecycleController) AdminGetStatusHistory(c *fiber.Ctx) error {
+	result, err := ctrl.lifecycleService.AdminGetStatusHistory(c.Context(), c.Params("id"))
+	if err != nil {
+		return lifecycleError(c, err)
+	}
+	return common.Success(c, result, "")
+}
+
+func lifecycleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		return common.NotFound(c, "Account not found")
+	case errors.Is(err, service.ErrAccountStatus),
+		errors.Is(err, service.ErrFrozenByAdmin),
+		errors.Is(err, service.ErrOpenPositions),
+		errors.Is(err, service.ErrPendingTransactions),
+		errors.Is(err, service.ErrBalanceNotZero),
+		errors.Is(err, service.ErrSweepAccount):
+		return common.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrUnsettledFunds):
+		return common.BadRequest(c, "Sale proceeds are still settling; close once they settle")
+	case errors.Is(err, fx.ErrRateUnavailable):
+		return common.BadRequest(c, "No exchange rate to the sweep account's currency")
+	default:
+		return common.InternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/payment"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentController struct {
+	paymentService *service.PaymentService
+	webhookSecret  string
+}
+
+func NewPaymentController(paymentService *service.PaymentService, webhookSecret string) *PaymentController {
+	return &PaymentController{
+		paymentService: paymentService,
+		webhookSecret:  webhookSecret,
+	}
+}
+
+// Webhook receives signed deposit and payout results from the provider
+// POST /api/v1/payments/webhook
+func (ctrl *PaymentController) Webhook(c *fiber.Ctx) error {
+	if err := payment.Verify(ctrl.webhookSecret, c.Body(), c.Get(payment.SignatureHeader)); err != nil {
+		return common.Unauthorized(c, "Invalid signature")
+	}
+
+	var event payment.Event
+	if err := json.Unmarshal(c.Body(), &event); err != nil {
+		return common.BadRequest(c, "Invalid event")
+	}
+	if validationErrors := utils.ValidateStruct(&event); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.paymentService.HandleEvent(c.Context(), &event)
+	if err != nil {
+		return paymentError(c, err)
+	}
+	return common.Success(c, result, "Event processed")
+}
+
+// SimulateStub settles a stub provider reference without a signature,
+// standing in for the provider while developing
+// POST /api/v1/payments/stub/:reference/:outcome
+func (ctrl *PaymentController) SimulateStub(c *fiber.Ctx) error {
+	event := payment.Event{
+		Reference: c.Params("reference"),
+		Outcome:   payment.Outcome(c.Params("outcome")),
+		Reason:    c.Query("reason"),
+	}
+	if validationErrors := utils.ValidateStruct(&event); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.paymentService.HandleEvent(c.Context(), &event)
+	if err != nil {
+		return paymentError(c, err)
+	}
+	return common.Success(c, result, "Event processed")
+}
+
+// CancelWithdrawal calls off one of the user's withdrawals
+// POST /api/v1/accounts/:id/withdrawals/:txId/cancel
+func (ctrl *PaymentController) CancelWithdrawal(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+
+	result, err := ctrl.paymentService.CancelWithdrawal(c.Context(), c.Params("id"), c.Params("txId"), userID)
+	if err != nil {
+		return paymentError(c, err)
+	}
+	return common.Success(c, result, "Withdrawal cancelled")
+}
+
+// GetReviewQueue lists withdrawals waiting for approval (admin only)
+// GET /api/v1/withdrawals/review
+func (ctrl *PaymentController) GetReviewQueue(c *fiber.Ctx) error {
+	result, err := ctrl.paymentService.GetReviewQueue(c.Context())
+	if err != nil {
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, result, "")
+}
+
+// ApproveWithdrawal sends a reviewed withdrawal for payout (admin only)
+// POST /api/v1/withdrawals/:id/approve
+func (ctrl *PaymentController) ApproveWithdrawal(c *fiber.Ctx) error {
+	adminID := middleware.GetUserID(c)
+	var req dto.ReviewRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.paymentService.ApproveWithdrawal(c.Context(), c.Params("id"), adminID, &req)
+	if err != nil {
+		return paymentError(c, err)
+	}
+	return common.Success(c, result, "Withdrawal approved")
+}
+
+// RejectWithdrawal refuses a reviewed withdrawal (admin only)
+// POST /api/v1/withdrawals/:id/reject
+func (ctrl *PaymentController) RejectWithdrawal(c *fiber.Ctx) error {
+	adminID := middleware.GetUserID(c)
+	var req dto.ReviewRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.paymentService.RejectWithdrawal(c.Context(), c.Params("id"), adminID, &req)
+	if err != nil {
+		return paymentError(c, err)
+	}
+	return common.Success(c, result, "Withdrawal rejected")
+}
+
+func paymentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		return common.NotFound(c, "Account not found")
+	case errors.Is(err, service.ErrTransactionNotFound), errors.Is(err, payment.ErrUnknownReference):
+		return common.NotFound(c, "Transaction not found")
+	case errors.Is(err, service.ErrTransactionState):
+		return common.BadRequest(c, "Transaction cannot change from its current status")
+	case errors.Is(err, service.ErrAccountFrozen):
+		return common.BadRequest(c, "Account is frozen")
+	case errors.Is(err, service.ErrAccountClosed):
+		return common.BadRequest(c, "Account is closed")
+	default:
+		return common.InternalError(c, err.Error())
+	}
+}
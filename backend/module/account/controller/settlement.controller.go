@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SettlementController struct {
+	settlementService *service.SettlementService
+}
+
+func NewSettlementController(settlementService *service.SettlementService) *SettlementController {
+	return &SettlementController{
+		settlementService: settlementService,
+	}
+}
+
+// GetSettlements lists an account's fills that haven't settled yet
+// GET /api/v1/accounts/:id/settlements
+func (ctrl *SettlementController) GetSettlements(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	result, err := ctrl.settlementService.GetPendingSettlements(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			return common.NotFound(c, "Account not found")
+		}
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, result, "")
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type StatementController struct {
+	statementService *service.StatementService
+}
+
+func NewStatementController(statementService *service.StatementService) *StatementController {
+	return &StatementController{
+		statementService: statementService,
+	}
+}
+
+// GetStatements lists an account's statements
+// GET /api/v1/accounts/:id/statements
+func (ctrl *StatementController) GetStatements(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	return ctrl.list(c, userID)
+}
+
+// RequestStatement generates a statement for a month or range of days
+// POST /api/v1/accounts/:id/statements
+func (ctrl *StatementController) RequestStatement(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	return ctrl.generate(c, userID)
+}
+
+// Download sends a statement as PDF or CSV
+// GET /api/v1/accounts/:id/statements/:statementId/download?format=pdf|csv
+func (ctrl *StatementController) Download(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	return ctrl.download(c, userID)
+}
+
+// AdminGetStatements lists any account's statements
+// GET /api/v1/statements/accounts/:id
+func (ctrl *StatementController) AdminGetStatements(c *fiber.Ctx) error {
+	return ctrl.list(c, "")
+}
+
+// AdminRequestStatement generates a statement for any account
+// POST /api/v1/statements/accounts/:id
+func (ctrl *StatementController) AdminRequestStatement(c *fiber.Ctx) error {
+	return ctrl.generate(c, "")
+}
+
+// AdminDownload sends any account's statement as PDF or CSV
+// GET /api/v1/statements/accounts/:id/:statementId/download?format=pdf|csv
+func (ctrl *StatementController) AdminDownload(c *fiber.Ctx) error {
+	return ctrl.download(c, "")
+}
+
+func (ctrl *StatementController) list(c *fiber.Ctx, userID string) error {
+	result, err := ctrl.statementService.GetStatements(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		return statementError(c, err)
+	}
+	return common.Success(c, result, "")
+}
+
+func (ctrl *StatementController) generate(c *fiber.Ctx, userID string) error {
+	var req dto.StatementRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.statementService.RequestStatement(c.Context(), c.Params("id"), userID, &req)
+	if err != nil {
+		return statementError(c, err)
+	}
+	return common.Success(c, result, "Statement generated")
+}
+
+func (ctrl *StatementController) download(c *fiber.Ctx, userID string) error {
+	format := model.StatementFormat(c.Query("format", string(model.StatementFormatPDF)))
+	contentType := "application/pdf"
+	switch format {
+	case model.StatementFormatPDF:
+	case model.StatementFormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	default:
+		return common.BadRequest(c, "Format must be pdf or csv")
+	}
+
+	content, name, err := ctrl.statementService.Download(c.Context(), c.Params("id"), c.Params("statementId"), userID, format)
+	if err != nil {
+		return statementError(c, err)
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+name+`"`)
+	return c.Send(content)
+}
+
+func statementError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		return common.NotFound(c, "Account not found")
+	case errors.Is(err, service.ErrStatementNotFound):
+		return common.NotFound(c, "Statement not found")
+	case errors.Is(err, service.ErrStatementPeriod):
+		return common.BadRequest(c, err.Error())
+	default:
+		return common.InternalError(c, err.Error())
+	}
+}
@@ -15,6 +15,35 @@ type (
 		CreatedAt time.Time `json:"createdAt"`
 		UpdatedAt time.Time `json:"updatedAt"`
 		Status    string    `json:"status"`
+		// StatusReason explains a freeze or close; ClosedAt is set once closed
+		StatusReason string     `json:"statusReason,omitempty"`
+		ClosedAt     *time.Time `json:"closedAt,omitempty"`
+		// SelfTradeMode is applied to orders that don't set their own
+		SelfTradeMode string `json:"selfTradeMode,omitempty"`
+
+		// Balance split into cash that has settled and sale proceeds that
+		// haven't; only settled cash not held for open buy orders can be
+		// withdrawn or transferred
+		SettledCash   float64 `json:"settledCash"`
+		UnsettledCash float64 `json:"unsettledCash"`
+		ReservedCash  float64 `json:"reservedCash"`
+		BuyingPower   float64 `json:"buyingPower"`
+
+		// Good-faith violations in the last year, and the settled-cash-only
+		// restriction they put the account under
+		GoodFaithViolations int        `json:"goodFaithViolations"`
+		RestrictedUntil     *time.Time `json:"restrictedUntil,omitempty"`
+
+		// AccruedInterest is this month's interest so far, posted after the
+		// month ends; negative when margin interest is owed
+		AccruedInterest float64 `json:"accruedInterest"`
+		FeePlan         string  `json:"feePlan,omitempty"`
+	}
+
+	// SelfTradeModeRequest sets what happens when the account's orders
+	// would match each other. An empty mode restores the engine default.
+	SelfTradeModeRequest struct {
+		Mode string `json:"mode" validate:"omitempty,oneof=CANCEL_NEWEST CANCEL_OLDEST CANCEL_BOTH DECREMENT_AND_CANCEL"`
 	}
 
 	DepositRequest struct {
@@ -32,4 +61,16 @@ type (
 		Amount      float64 `json:"amount" validate:"required,gt=0"`
 		Description string  `json:"description"`
 	}
+
+	// TransferResponse shows both sides of a transfer. Amount is in the
+	// source currency and ConvertedAmount in the target's.
+	TransferResponse struct {
+		From            TransactionResponse `json:"from"`
+		To              TransactionResponse `json:"to"`
+		FromCurrency    string              `json:"fromCurrency"`
+		ToCurrency      string              `json:"toCurrency"`
+		Amount          float64             `json:"amount"`
+		ConvertedAmount float64             `json:"convertedAmount"`
+		Rate            float64             `json:"rate"`
+	}
 )
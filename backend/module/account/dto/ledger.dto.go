@@ -0,0 +1,42 @@
+package dto
+
+type (
+	JournalLineResponse struct {
+		Ledger    string  `json:"ledger"`
+		AccountID string  `json:"accountId,omitempty"`
+		Currency  string  `json:"currency,omitempty"`
+		Debit     float64 `json:"debit"`
+		Credit    float64 `json:"credit"`
+	}
+
+	JournalEntryResponse struct {
+		ID            string                `json:"id"`
+		Type          string                `json:"type"`
+		Lines         []JournalLineResponse `json:"lines"`
+		CashDelta     float64               `json:"cashDelta"` // Effect on this account's cash
+		ReferenceType string                `json:"referenceType,omitempty"`
+		ReferenceID   string                `json:"referenceId,omitempty"`
+		Description   string                `json:"description"`
+		CreatedAt     string                `json:"createdAt"`
+	}
+
+	// ReconciliationResponse compares an account's stored balance with
+	// the balance its journal entries add up to
+	ReconciliationResponse struct {
+		AccountID     string  `json:"accountId"`
+		Currency      string  `json:"currency"`
+		Balance       float64 `json:"balance"`
+		LedgerBalance float64 `json:"ledgerBalance"`
+		Drift         float64 `json:"drift"` // Balance - LedgerBalance
+		Drifted       bool    `json:"drifted"`
+		CheckedAt     string  `json:"checkedAt"`
+	}
+
+	// ReconciliationReport lists the accounts whose balance drifted from
+	// the ledger
+	ReconciliationReport struct {
+		AccountsChecked int                      `json:"accountsChecked"`
+		Drifted         []ReconciliationResponse `json:"drifted"`
+		CheckedAt       string                   `json:"checkedAt"`
+	}
+)
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// StatusChangeRequest gives the reason for a freeze, unfreeze or reopen
+type StatusChangeRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+// CloseAccountRequest closes an account. Cash left in it is swept to
+// SweepToAccountID, another active account of the same owner; without
+// one the balance must already be zero.
+type CloseAccountRequest struct {
+	Reason           string `json:"reason" validate:"required,min=3,max=500"`
+	SweepToAccountID string `json:"sweepToAccountId"`
+}
+
+// StatusChangeResponse is one entry of an account's status history
+type StatusChangeResponse struct {
+	ID              string    `json:"id"`
+	AccountID       string    `json:"accountId"`
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	Reason          string    `json:"reason"`
+	Actor           string    `json:"actor"`
+	ChangedBy       string    `json:"changedBy"`
+	CancelledOrders int       `json:"cancelledOrders,omitempty"`
+	SweptAmount     float64   `json:"sweptAmount,omitempty"`
+	SweepTo         string    `json:"sweepTo,omitempty"`
+	SweepTxID       string    `json:"sweepTxId,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
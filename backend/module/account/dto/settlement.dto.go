@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// SettlementResponse is a fill waiting to settle. Cash is its movement
+// in the account currency.
+type SettlementResponse struct {
+	ID            string    `json:"id"`
+	Symbol        string    `json:"symbol"`
+	Side          string    `json:"side"`
+	Total         float64   `json:"total"`
+	Currency      string    `json:"currency"`
+	Cash          float64   `json:"cash"`
+	ReferenceType string    `json:"referenceType,omitempty"`
+	ReferenceID   string    `json:"referenceId,omitempty"`
+	TradeDate     time.Time `json:"tradeDate"`
+	SettleDate    time.Time `json:"settleDate"`
+	Status        string    `json:"status"`
+}
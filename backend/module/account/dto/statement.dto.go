@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type (
+	// StatementRequest asks for a statement of a calendar month
+	// ("2006-01") or of the days From to To, inclusive ("2006-01-02")
+	StatementRequest struct {
+		Month string `json:"month" validate:"omitempty,datetime=2006-01"`
+		From  string `json:"from" validate:"required_without=Month,omitempty,datetime=2006-01-02"`
+		To    string `json:"to" validate:"required_without=Month,omitempty,datetime=2006-01-02"`
+	}
+
+	// StatementResponse describes a generated statement and where to
+	// download it. PeriodEnd is the last day it covers.
+	StatementResponse struct {
+		ID             string            `json:"id"`
+		AccountID      string            `json:"accountId"`
+		PeriodStart    string            `json:"periodStart"`
+		PeriodEnd      string            `json:"periodEnd"`
+		Monthly        bool              `json:"monthly"`
+		Currency       string            `json:"currency"`
+		OpeningBalance float64           `json:"openingBalance"`
+		ClosingBalance float64           `json:"closingBalance"`
+		PositionsValue float64           `json:"positionsValue"`
+		Fees           float64           `json:"fees"`
+		Dividends      float64           `json:"dividends"`
+		Downloads      map[string]string `json:"downloads"` // Format to URL
+		CreatedAt      time.Time         `json:"createdAt"`
+	}
+)
@@ -12,7 +12,16 @@ type (
 		ReferenceID   *string `json:"referenceId,omitempty"`
 		Status        string  `json:"status"`
 		Description   string  `json:"description"`
+		ProviderRef   string  `json:"providerRef,omitempty"`
+		FailureReason string  `json:"failureReason,omitempty"`
+		ReviewNote    string  `json:"reviewNote,omitempty"`
 		CreatedAt     string  `json:"createdAt"`
+		CompletedAt   *string `json:"completedAt,omitempty"`
+	}
+
+	// ReviewRequest approves or rejects a withdrawal held for review
+	ReviewRequest struct {
+		Note string `json:"note" validate:"max=500"`
 	}
 
 	TransactionFilter struct {
@@ -0,0 +1,125 @@
+// Package interest holds the tiered interest rates paid on cash balances
+// and charged on margin debit (a negative cash balance).
+package interest
+
+import (
+	"strings"
+	"sync"
+)
+
+// DaysPerYear is the day count daily accrual divides annual rates by
+const DaysPerYear = 365
+
+// Tier pays Rate (annual, 0.02 = 2%) on the part of a balance below UpTo.
+// The last tier leaves UpTo at 0 to cover the rest.
+type Tier struct {
+	UpTo float64
+	Rate float64
+}
+
+// Rates are what an account earns on cash, tier by tier, and pays on
+// margin debit
+type Rates struct {
+	Credit []Tier
+	Debit  float64
+}
+
+// Annual returns a year's interest on balance: positive when earned,
+// negative when charged
+func (r Rates) Annual(balance float64) float64 {
+	if balance < 0 {
+		return balance * r.Debit
+	}
+
+	total, floor := 0.0, 0.0
+	for _, tier := range r.Credit {
+		top := balance
+		if tier.UpTo > 0 && tier.UpTo < balance {
+			top = tier.UpTo
+		}
+		if top > floor {
+			total += (top - floor) * tier.Rate
+		}
+		if tier.UpTo <= 0 || tier.UpTo >= balance {
+			break
+		}
+		floor = tier.UpTo
+	}
+	return total
+}
+
+// Daily returns one day's interest on balance
+func (r Rates) Daily(balance float64) float64 {
+	return r.Annual(balance) / DaysPerYear
+}
+
+// Effective returns the blended annual rate on balance
+func (r Rates) Effective(balance float64) float64 {
+	if balance == 0 {
+		return 0
+	}
+	return r.Annual(balance) / balance
+}
+
+// Schedule looks up rates by currency and account type. Rates set for a
+// currency with no account type apply to every type without its own.
+type Schedule struct {
+	mu    sync.RWMutex
+	rates map[string]Rates
+}
+
+func NewSchedule() *Schedule {
+	return &Schedule{rates: make(map[string]Rates)}
+}
+
+var (
+	defaultSchedule *Schedule
+	defaultOnce     sync.Once
+)
+
+// Default returns the shared schedule, loaded with the built-in rates
+func Default() *Schedule {
+	defaultOnce.Do(func() {
+		defaultSchedule = NewSchedule()
+		registerBuiltins(defaultSchedule)
+	})
+	return defaultSchedule
+}
+
+// Set sets the rates for a currency and account type ("" for any type)
+func (s *Schedule) Set(currency, accountType string, rates Rates) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates[key(currency, accountType)] = rates
+}
+
+// Lookup returns the rates for a currency and account type. ok is false
+// when the currency pays no interest.
+func (s *Schedule) Lookup(currency, accountType string) (Rates, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if rates, ok := s.rates[key(currency, accountType)]; ok {
+		return rates, true
+	}
+	rates, ok := s.rates[key(currency, "")]
+	return rates, ok
+}
+
+func key(currency, accountType string) string {
+	return strings.ToUpper(currency) + "/" + strings.ToUpper(accountType)
+}
+
+// registerBuiltins loads the house rate card. Demo accounts hold paper
+// money, so they neither earn nor pay interest.
+func registerBuiltins(s *Schedule) {
+	builtins := map[string]Rates{
+		"USD": {Credit: []Tier{{UpTo: 10000, Rate: 0.005}, {UpTo: 100000, Rate: 0.02}, {Rate: 0.035}}, Debit: 0.085},
+		"EUR": {Credit: []Tier{{UpTo: 10000, Rate: 0.0025}, {UpTo: 100000, Rate: 0.015}, {Rate: 0.025}}, Debit: 0.07},
+		"RUB": {Credit: []Tier{{UpTo: 1000000, Rate: 0.02}, {UpTo: 10000000, Rate: 0.06}, {Rate: 0.1}}, Debit: 0.18},
+		"THB": {Credit: []Tier{{UpTo: 500000, Rate: 0.005}, {Rate: 0.01}}, Debit: 0.07},
+	}
+	for currency, rates := range builtins {
+		s.Set(currency, "", rates)
+		s.Set(currency, "DEMO", Rates{})
+	}
+}
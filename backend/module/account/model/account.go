@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,6 +17,9 @@ const (
 	AccountStatusActive AccountStatus = "ACTIVE" // Normal trading enabled
 	AccountStatusFrozen AccountStatus = "FROZEN" // Temporarily suspended
 	AccountStatusClosed AccountStatus = "CLOSED" // Permanently closed
+
+	// Being closed: refused like a closed account until its cash is swept
+	AccountStatusClosing AccountStatus = "CLOSING"
 )
 
 // AccountType distinguishes between demo and real trading accounts.
@@ -41,6 +45,95 @@ type Account struct {
 	TotalPnL       float64            `bson:"totalPnL" json:"totalPnL"` // Cumulative profit/loss
 	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+
+	// SelfTradeMode is the default self-trade prevention for the account's orders
+	SelfTradeMode string `bson:"selfTradeMode,omitempty" json:"selfTradeMode,omitempty"`
+
+	// FeePlan names the account's commission plan; empty uses the default
+	FeePlan string `bson:"feePlan,omitempty" json:"feePlan,omitempty"`
+
+	// Settlement: sale proceeds in Balance that haven't settled yet, and
+	// good-faith violations with the settled-cash-only restriction they earn
+	UnsettledCash       float64     `bson:"unsettledCash,omitempty" json:"unsettledCash"`
+	GoodFaithViolations []time.Time `bson:"goodFaithViolations,omitempty" json:"goodFaithViolations,omitempty"`
+	RestrictedUntil     *time.Time  `bson:"restrictedUntil,omitempty" json:"restrictedUntil,omitempty"`
+
+	// ReservedCash is the part of Balance held for resting LIMIT buys, so
+	// their fills are funded when the engine matches them
+	ReservedCash float64 `bson:"reservedCash,omitempty" json:"reservedCash"`
+
+	// AccruedInterest is interest accrued this month and not yet posted;
+	// negative when margin interest is owed
+	AccruedInterest float64 `bson:"accruedInterest,omitempty" json:"accruedInterest"`
+
+	// Lifecycle: why and by whom the status last changed, and when the
+	// account was closed. Each change is also kept as an AccountStatusChange.
+	StatusReason    string       `bson:"statusReason,omitempty" json:"statusReason,omitempty"`
+	StatusChangedBy AccountActor `bson:"statusChangedBy,omitempty" json:"statusChangedBy,omitempty"`
+	ClosedAt        *time.Time   `bson:"closedAt,omitempty" json:"closedAt,omitempty"`
+
+	// AppliedEntries are journal entries whose cash is already in Balance
+	// but still pending on the entry, so retrying them can't apply twice
+	AppliedEntries []AppliedEntry `bson:"appliedEntries,omitempty" json:"-"`
+}
+
+// AppliedEntry marks a journal entry's cash movement as applied to an
+// account's balance, with the delta so it can be undone
+type AppliedEntry struct {
+	EntryID primitive.ObjectID `bson:"entryId"`
+	Delta   float64            `bson:"delta"`
+}
+
+// Good-faith rules for cash accounts (see IsCashAccount): this many violations in a year
+// restrict the account to buying with settled cash for a while
+const (
+	GoodFaithViolationLimit  = 3
+	GoodFaithWindow          = 365 * 24 * time.Hour
+	GoodFaithRestrictionTime = 90 * 24 * time.Hour
+)
+
+// IsCashAccount reports whether the account trades only its own cash, so
+// good-faith rules apply. Demo accounts and leveraged margin accounts
+// aren't bound by them.
+func (a *Account) IsCashAccount() bool {
+	return a.Type != AccountTypeDemo && a.Leverage <= 1
+}
+
+// SettledCash is the part of Balance that has settled. Buys spend settled
+// cash first, so unsettled proceeds only count while Balance holds them.
+func (a *Account) SettledCash() float64 {
+	return math.Max(0, a.Balance-a.UnsettledCash)
+}
+
+// IsRestricted reports whether the account may only buy with settled cash
+func (a *Account) IsRestricted(now time.Time) bool {
+	return a.RestrictedUntil != nil && now.Before(*a.RestrictedUntil)
+}
+
+// FreeCash is the settled cash not held for resting buys; only it can
+// leave the account
+func (a *Account) FreeCash() float64 {
+	return math.Max(0, a.SettledCash()-a.ReservedCash)
+}
+
+// BuyingPower is the cash available for buys at now, less what resting
+// buys already hold
+func (a *Account) BuyingPower(now time.Time) float64 {
+	if a.IsRestricted(now) {
+		return a.FreeCash()
+	}
+	return math.Max(0, a.Balance-a.ReservedCash)
+}
+
+// RecentViolations counts good-faith violations within the window before now
+func (a *Account) RecentViolations(now time.Time) int {
+	count := 0
+	for _, at := range a.GoodFaithViolations {
+		if now.Sub(at) < GoodFaithWindow {
+			count++
+		}
+	}
+	return count
 }
 
 // NewDemoAccount creates a demo account with $50,000 virtual balance.
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InterestAccrualCollection is the MongoDB collection name for daily interest accruals.
+const InterestAccrualCollection = "interest_accruals"
+
+// InterestAccrual is one day's interest on an account's closing cash
+// balance. Accruals build up through the month and are posted together
+// as an INTEREST transaction.
+type InterestAccrual struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AccountID     primitive.ObjectID  `bson:"accountId" json:"accountId"`
+	Date          string              `bson:"date" json:"date"` // UTC day, "2006-01-02"
+	Currency      string              `bson:"currency" json:"currency"`
+	Balance       float64             `bson:"balance" json:"balance"`
+	Rate          float64             `bson:"rate" json:"rate"`     // Blended annual rate on Balance
+	Amount        float64             `bson:"amount" json:"amount"` // Negative for margin interest
+	Posted        bool                `bson:"posted" json:"posted"`
+	TransactionID *primitive.ObjectID `bson:"transactionId,omitempty" json:"transactionId,omitempty"` // Set when claimed for posting; Posted once booked
+	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+}
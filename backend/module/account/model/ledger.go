@@ -0,0 +1,119 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JournalCollection is the MongoDB collection name for ledger journal entries.
+const JournalCollection = "journal_entries"
+
+// LedgerTolerance absorbs float rounding when comparing ledger amounts.
+const LedgerTolerance = 1e-6
+
+// LedgerAccount is a book in the double-entry ledger. USER_CASH,
+// PENDING_SETTLEMENT and PENDING_WITHDRAWAL lines belong to a trading
+// account; the others are house books.
+type LedgerAccount string
+
+const (
+	LedgerUserCash          LedgerAccount = "USER_CASH"          // Customer cash, what Account.Balance mirrors
+	LedgerFeeRevenue        LedgerAccount = "FEE_REVENUE"        // Commissions earned
+	LedgerPendingSettlement LedgerAccount = "PENDING_SETTLEMENT" // Trade value awaiting settlement
+	LedgerClearing          LedgerAccount = "CLEARING"           // Money moving to/from outside the system
+	LedgerFXConversion      LedgerAccount = "FX_CONVERSION"      // House position taken converting currencies
+	LedgerPendingWithdrawal LedgerAccount = "PENDING_WITHDRAWAL" // Cash held for a withdrawal until it pays out
+	LedgerInterest          LedgerAccount = "INTEREST"           // Interest paid on cash less margin interest charged
+	LedgerRegulatoryFees    LedgerAccount = "REGULATORY_FEES"    // Pass-through fees owed to regulators
+)
+
+// JournalType describes the movement a journal entry records.
+type JournalType string
+
+const (
+	JournalTypeOpening    JournalType = "OPENING"
+	JournalTypeDeposit    JournalType = "DEPOSIT"
+	JournalTypeWithdraw   JournalType = "WITHDRAW"
+	JournalTypeTrade      JournalType = "TRADE"
+	JournalTypeSettlement JournalType = "SETTLEMENT"
+	JournalTypeAdjustment JournalType = "ADJUSTMENT"
+	JournalTypeTransfer   JournalType = "TRANSFER"
+	JournalTypeHold       JournalType = "HOLD"
+	JournalTypeRelease    JournalType = "RELEASE"
+	JournalTypeInterest   JournalType = "INTEREST"
+	JournalTypeFee        JournalType = "FEE"
+)
+
+// JournalLine debits or credits one ledger book in one currency.
+// Exactly one of Debit and Credit is set.
+type JournalLine struct {
+	Ledger    LedgerAccount       `bson:"ledger" json:"ledger"`
+	AccountID *primitive.ObjectID `bson:"accountId,omitempty" json:"accountId,omitempty"`
+	Currency  string              `bson:"currency,omitempty" json:"currency,omitempty"`
+	Debit     float64             `bson:"debit" json:"debit"`
+	Credit    float64             `bson:"credit" json:"credit"`
+}
+
+// JournalEntry is one balanced movement in the ledger: its debits and
+// credits add up to the same amount.
+type JournalEntry struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Type          JournalType         `bson:"type" json:"type"`
+	Lines         []JournalLine       `bson:"lines" json:"lines"`
+	ReferenceType string              `bson:"referenceType,omitempty" json:"referenceType,omitempty"`
+	ReferenceID   *primitive.ObjectID `bson:"referenceId,omitempty" json:"referenceId,omitempty"`
+	Description   string              `bson:"description" json:"description"`
+	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+
+	// Pending lists the accounts whose balance hasn't taken the entry's
+	// cash movement yet; the ledger finishes applying it if a post dies
+	Pending []primitive.ObjectID `bson:"pending,omitempty" json:"-"`
+}
+
+// Balanced reports whether the entry has lines, none of them negative
+// or two-sided, and its debits equal its credits in every currency.
+func (e *JournalEntry) Balanced() bool {
+	if len(e.Lines) == 0 {
+		return false
+	}
+	net := make(map[string]float64)
+	for _, line := range e.Lines {
+		if line.Debit < 0 || line.Credit < 0 || (line.Debit > 0) == (line.Credit > 0) {
+			return false
+		}
+		net[line.Currency] += line.Debit - line.Credit
+	}
+	for _, amount := range net {
+		if math.Abs(amount) > LedgerTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// CashDelta returns how much the entry moves a trading account's cash.
+// User cash is owed to the customer, so credits raise it.
+func (e *JournalEntry) CashDelta(accountID primitive.ObjectID) float64 {
+	delta := 0.0
+	for _, line := range e.Lines {
+		if line.Ledger == LedgerUserCash && line.AccountID != nil && *line.AccountID == accountID {
+			delta += line.Credit - line.Debit
+		}
+	}
+	return delta
+}
+
+// CashAccounts returns the trading accounts whose cash the entry moves
+func (e *JournalEntry) CashAccounts() []primitive.ObjectID {
+	var ids []primitive.ObjectID
+	seen := make(map[primitive.ObjectID]bool)
+	for _, line := range e.Lines {
+		if line.Ledger == LedgerUserCash && line.AccountID != nil && !seen[*line.AccountID] {
+			seen[*line.AccountID] = true
+			ids = append(ids, *line.AccountID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccountStatusChangeCollection is the MongoDB collection name for the
+// account status audit trail.
+const AccountStatusChangeCollection = "account_status_changes"
+
+// AccountActor is who changed an account's status.
+type AccountActor string
+
+const (
+	AccountActorUser  AccountActor = "USER"  // The account owner
+	AccountActorAdmin AccountActor = "ADMIN" // An administrator
+)
+
+// AccountStatusChange records one freeze, unfreeze, close or reopen.
+// Closes that swept the remaining cash elsewhere reference the transfer.
+type AccountStatusChange struct {
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AccountID       primitive.ObjectID  `bson:"accountId" json:"accountId"`
+	From            AccountStatus       `bson:"from" json:"from"`
+	To              AccountStatus       `bson:"to" json:"to"`
+	Reason          string              `bson:"reason" json:"reason"`
+	Actor           AccountActor        `bson:"actor" json:"actor"`
+	ChangedBy       primitive.ObjectID  `bson:"changedBy" json:"changedBy"`
+	CancelledOrders int                 `bson:"cancelledOrders,omitempty" json:"cancelledOrders,omitempty"`
+	SweptAmount     float64             `bson:"sweptAmount,omitempty" json:"sweptAmount,omitempty"`
+	SweepTo         *primitive.ObjectID `bson:"sweepTo,omitempty" json:"sweepTo,omitempty"`
+	SweepTxID       *primitive.ObjectID `bson:"sweepTxId,omitempty" json:"sweepTxId,omitempty"`
+	CreatedAt       time.Time           `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SettlementCollection is the MongoDB collection name for trade settlements.
+const SettlementCollection = "settlements"
+
+// SettlementStatus tracks a fill's settlement.
+type SettlementStatus string
+
+const (
+	SettlementStatusPending SettlementStatus = "PENDING" // Trade value still in pending settlement
+	SettlementStatusSettled SettlementStatus = "SETTLED" // Cleared with the counterparty
+)
+
+// Settlement is a fill's trade value waiting to clear on its settlement
+// date. Cash moves on the account when the fill is booked; settlement
+// clears pending settlement and turns sale proceeds into settled cash.
+type Settlement struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AccountID     primitive.ObjectID  `bson:"accountId" json:"accountId"`
+	ReferenceType string              `bson:"referenceType" json:"referenceType"` // TRADE, ORDER
+	ReferenceID   *primitive.ObjectID `bson:"referenceId,omitempty" json:"referenceId,omitempty"`
+	Symbol        string              `bson:"symbol" json:"symbol"`
+	Buy           bool                `bson:"buy" json:"buy"`
+	Total         float64             `bson:"total" json:"total"`       // Trade value in Currency
+	Currency      string              `bson:"currency" json:"currency"` // Instrument currency
+	Cash          float64             `bson:"cash" json:"cash"`         // Cash movement in the account currency
+	TradeDate     time.Time           `bson:"tradeDate" json:"tradeDate"`
+	SettleDate    time.Time           `bson:"settleDate" json:"settleDate"`
+	Status        SettlementStatus    `bson:"status" json:"status"`
+	SettledAt     *time.Time          `bson:"settledAt,omitempty" json:"settledAt,omitempty"`
+	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+
+	// A buy paid partly with unsettled cash is free to sell only once that
+	// cash settles, at FundsSettleAt; selling earlier breaks good faith
+	UnsettledFunded float64    `bson:"unsettledFunded,omitempty" json:"unsettledFunded,omitempty"`
+	FundsSettleAt   *time.Time `bson:"fundsSettleAt,omitempty" json:"fundsSettleAt,omitempty"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StatementCollection is the MongoDB collection name for generated account statements.
+const StatementCollection = "statements"
+
+// StatementFormat is a format a statement can be downloaded in.
+type StatementFormat string
+
+const (
+	StatementFormatPDF StatementFormat = "pdf"
+	StatementFormatCSV StatementFormat = "csv"
+)
+
+// Statement is an account statement for [PeriodStart, PeriodEnd),
+// rendered once and kept for download. Monthly statements are generated
+// by the statement job after their month ends.
+type Statement struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AccountID      primitive.ObjectID `bson:"accountId" json:"accountId"`
+	UserID         primitive.ObjectID `bson:"userId" json:"userId"`
+	PeriodStart    time.Time          `bson:"periodStart" json:"periodStart"`
+	PeriodEnd      time.Time          `bson:"periodEnd" json:"periodEnd"` // Exclusive
+	Monthly        bool               `bson:"monthly" json:"monthly"`
+	Currency       string             `bson:"currency" json:"currency"`
+	OpeningBalance float64            `bson:"openingBalance" json:"openingBalance"`
+	ClosingBalance float64            `bson:"closingBalance" json:"closingBalance"`
+	PositionsValue float64            `bson:"positionsValue" json:"positionsValue"`
+	Fees           float64            `bson:"fees" json:"fees"`
+	Dividends      float64            `bson:"dividends" json:"dividends"`
+	CSV            []byte             `bson:"csv" json:"-"`
+	PDF            []byte             `bson:"pdf" json:"-"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Content returns the rendered statement in a format
+func (s *Statement) Content(format StatementFormat) []byte {
+	if format == StatementFormatCSV {
+		return s.CSV
+	}
+	return s.PDF
+}
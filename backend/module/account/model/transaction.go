@@ -18,13 +18,16 @@ const (
 	TransactionTypeFee      TransactionType = "FEE"
 	TransactionTypeDividend TransactionType = "DIVIDEND"
 	TransactionTypeTransfer TransactionType = "TRANSFER"
+	TransactionTypeInterest TransactionType = "INTEREST" // Monthly interest earned or margin interest charged
 )
 
 const (
-	TransactionStatusPending   TransactionStatus = "PENDING"
-	TransactionStatusCompleted TransactionStatus = "COMPLETED"
-	TransactionStatusFailed    TransactionStatus = "FAILED"
-	TransactionStatusCancelled TransactionStatus = "CANCELLED"
+	TransactionStatusPending       TransactionStatus = "PENDING"        // Waiting on the payment provider
+	TransactionStatusPendingReview TransactionStatus = "PENDING_REVIEW" // Withdrawal held for admin review
+	TransactionStatusCompleted     TransactionStatus = "COMPLETED"
+	TransactionStatusFailed        TransactionStatus = "FAILED"
+	TransactionStatusCancelled     TransactionStatus = "CANCELLED"
+	TransactionStatusRejected      TransactionStatus = "REJECTED" // Withdrawal refused on review
 )
 
 type Transaction struct {
@@ -39,4 +42,21 @@ type Transaction struct {
 	ReferenceID   *primitive.ObjectID `bson:"referenceId,omitempty" json:"referenceId,omitempty"`
 	Description   string              `bson:"description" json:"description"`
 	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+
+	// Deposit and withdrawal workflow
+	ProviderRef   string              `bson:"providerRef,omitempty" json:"providerRef,omitempty"` // Payment provider's reference
+	FailureReason string              `bson:"failureReason,omitempty" json:"failureReason,omitempty"`
+	ReviewedBy    *primitive.ObjectID `bson:"reviewedBy,omitempty" json:"reviewedBy,omitempty"`
+	ReviewNote    string              `bson:"reviewNote,omitempty" json:"reviewNote,omitempty"`
+	CompletedAt   *time.Time          `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	UpdatedAt     time.Time           `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
+
+// IsTerminal reports whether the transaction can no longer change state
+func (t *Transaction) IsTerminal() bool {
+	switch t.Status {
+	case TransactionStatusPending, TransactionStatusPendingReview:
+		return false
+	}
+	return true
 }
@@ -0,0 +1,139 @@
+// Package payment connects deposits and withdrawals to an external
+// payment provider. Providers accept requests and report their outcome
+// later through a signed webhook.
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrUnknownReference = errors.New("unknown payment reference")
+	ErrNoProvider       = errors.New("no payment provider is configured")
+)
+
+// SignatureHeader carries the webhook body's HMAC-SHA256, hex encoded
+const SignatureHeader = "X-Payment-Signature"
+
+// Outcome is how a provider reports a payment ended.
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "SUCCEEDED"
+	OutcomeFailed    Outcome = "FAILED"
+)
+
+// Event is a provider's webhook notification about a payment.
+// ClientReference echoes the request's TransactionID, so an event that
+// arrives before its reference was recorded can still be matched.
+type Event struct {
+	Reference       string  `json:"reference" validate:"required"`
+	ClientReference string  `json:"clientReference,omitempty"`
+	Outcome         Outcome `json:"outcome" validate:"required,oneof=SUCCEEDED FAILED"`
+	Reason          string  `json:"reason"`
+}
+
+// Request asks a provider to move money for a transaction. Providers
+// send TransactionID as the client reference of the payment.
+type Request struct {
+	TransactionID primitive.ObjectID
+	AccountID     primitive.ObjectID
+	Currency      string
+	Amount        float64
+}
+
+// Provider moves money in (deposits) and out (payouts) of the system.
+// Both return the provider's reference for the payment, which its
+// webhook events quote.
+type Provider interface {
+	Name() string
+	CreateDeposit(ctx context.Context, req *Request) (string, error)
+	CreatePayout(ctx context.Context, req *Request) (string, error)
+	CancelPayout(ctx context.Context, reference string) error
+}
+
+// Sign returns the signature of a webhook body under secret
+func Sign(secret string, body []byte) string {
+	return hex.EncodeToString(mac(secret, body))
+}
+
+// Verify checks a webhook body's signature. Without a secret every
+// signature is refused, since anyone could produce one.
+func Verify(secret string, body []byte, signature string) error {
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, mac(secret, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func mac(secret string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// NoProvider stands in when no provider is configured: live deposits
+// and withdrawals are refused
+type NoProvider struct{}
+
+func (NoProvider) Name() string {
+	return "none"
+}
+
+func (NoProvider) CreateDeposit(ctx context.Context, req *Request) (string, error) {
+	return "", ErrNoProvider
+}
+
+func (NoProvider) CreatePayout(ctx context.Context, req *Request) (string, error) {
+	return "", ErrNoProvider
+}
+
+func (NoProvider) CancelPayout(ctx context.Context, reference string) error {
+	return ErrNoProvider
+}
+
+const (
+	stubDepositPrefix = "stub_dep_"
+	stubPayoutPrefix  = "stub_out_"
+)
+
+// StubProvider is a local provider for development. It accepts every
+// request and leaves the outcome to be reported through its webhook,
+// e.g. with the stub simulation endpoint.
+type StubProvider struct{}
+
+func NewStubProvider() *StubProvider {
+	return &StubProvider{}
+}
+
+func (p *StubProvider) Name() string {
+	return "stub"
+}
+
+func (p *StubProvider) CreateDeposit(ctx context.Context, req *Request) (string, error) {
+	return stubDepositPrefix + req.TransactionID.Hex(), nil
+}
+
+func (p *StubProvider) CreatePayout(ctx context.Context, req *Request) (string, error) {
+	return stubPayoutPrefix + req.TransactionID.Hex(), nil
+}
+
+// CancelPayout accepts any stub payout, since stub payouts never leave
+func (p *StubProvider) CancelPayout(ctx context.Context, reference string) error {
+	if !strings.HasPrefix(reference, stubPayoutPrefix) {
+		return ErrUnknownReference
+	}
+	return nil
+}
@@ -13,14 +13,16 @@ import (
 )
 
 type AccountRepository struct {
-	accountCollection     *mongo.Collection
-	transactionCollection *mongo.Collection
+	accountCollection      *mongo.Collection
+	transactionCollection  *mongo.Collection
+	statusChangeCollection *mongo.Collection
 }
 
 func NewAccountRepository() *AccountRepository {
 	return &AccountRepository{
-		accountCollection:     database.GetCollection(model.AccountCollection),
-		transactionCollection: database.GetCollection(model.TransactionCollection),
+		accountCollection:      database.GetCollection(model.AccountCollection),
+		transactionCollection:  database.GetCollection(model.TransactionCollection),
+		statusChangeCollection: database.GetCollection(model.AccountStatusChangeCollection),
 	}
 }
 
@@ -51,6 +53,20 @@ func (r *AccountRepository) FindByID(ctx context.Context, id string) (*model.Acc
 	return &account, nil
 }
 
+// FindAll returns every account
+func (r *AccountRepository) FindAll(ctx context.Context) ([]model.Account, error) {
+	cursor, err := r.accountCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var accounts []model.Account
+	if err := cursor.All(ctx, &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
 func (r *AccountRepository) FindByUserID(ctx context.Context, userID string) ([]model.Account, error) {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -86,6 +102,148 @@ func (r *AccountRepository) UpdateBalanceDelta(ctx context.Context, accountID pr
 	})
 	return err
 }
+
+// ApplyEntry adds a journal entry's cash movement to an account's
+// balance, once: the account remembers the entry until ForgetEntry, so a
+// retried apply is a no-op
+func (r *AccountRepository) ApplyEntry(ctx context.Context, accountID, entryID primitive.ObjectID, delta float64) error {
+	_, err := r.accountCollection.UpdateOne(ctx,
+		bson.M{"_id": accountID, "appliedEntries.entryId": bson.M{"$ne": entryID}},
+		applyEntry(entryID, delta),
+	)
+	return err
+}
+
+// DebitIfCovered is ApplyEntry for a debit of amount, taken only if the
+// cash not held for resting orders covers it. It reports whether the
+// debit happened.
+func (r *AccountRepository) DebitIfCovered(ctx context.Context, accountID, entryID primitive.ObjectID, amount float64) (bool, error) {
+	result, err := r.accountCollection.UpdateOne(ctx,
+		bson.M{
+			"_id":                    accountID,
+			"appliedEntries.entryId": bson.M{"$ne": entryID},
+			"$expr":                  bson.M{"$gte": bson.A{freeCash(false), amount}},
+		},
+		applyEntry(entryID, -amount),
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// UndoEntry takes back an entry applied to an account's balance, if it was
+func (r *AccountRepository) UndoEntry(ctx context.Context, accountID, entryID primitive.ObjectID, delta float64) error {
+	_, err := r.accountCollection.UpdateOne(ctx,
+		bson.M{"_id": accountID, "appliedEntries.entryId": entryID},
+		bson.M{
+			"$inc":  bson.M{"balance": -delta},
+			"$pull": bson.M{"appliedEntries": bson.M{"entryId": entryID}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	return err
+}
+
+// ForgetEntry drops an account's mark for an entry once the entry itself
+// records that it was applied
+func (r *AccountRepository) ForgetEntry(ctx context.Context, accountID, entryID primitive.ObjectID) error {
+	_, err := r.accountCollection.UpdateByID(ctx, accountID, bson.M{
+		"$pull": bson.M{"appliedEntries": bson.M{"entryId": entryID}},
+	})
+	return err
+}
+
+// FindWithAppliedEntries returns accounts still marking applied entries
+func (r *AccountRepository) FindWithAppliedEntries(ctx context.Context) ([]model.Account, error) {
+	cursor, err := r.accountCollection.Find(ctx, bson.M{"appliedEntries.0": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var accounts []model.Account
+	if err := cursor.All(ctx, &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func applyEntry(entryID primitive.ObjectID, delta float64) bson.M {
+	return bson.M{
+		"$inc":  bson.M{"balance": delta},
+		"$push": bson.M{"appliedEntries": model.AppliedEntry{EntryID: entryID, Delta: delta}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+}
+
+// ReserveCash atomically holds amount of the account's cash for a resting
+// buy, only if the cash not already held covers it. settledOnly limits it
+// to settled cash, for accounts under a good-faith restriction.
+func (r *AccountRepository) ReserveCash(ctx context.Context, accountID primitive.ObjectID, amount float64, settledOnly bool) (bool, error) {
+	result, err := r.accountCollection.UpdateOne(ctx,
+		bson.M{"_id": accountID, "$expr": bson.M{"$gte": bson.A{freeCash(settledOnly), amount}}},
+		bson.M{
+			"$inc": bson.M{"reservedCash": amount},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// AddReservedCash moves the cash held for an account's resting buys by delta
+func (r *AccountRepository) AddReservedCash(ctx context.Context, accountID primitive.ObjectID, delta float64) error {
+	_, err := r.accountCollection.UpdateByID(ctx, accountID, bson.M{
+		"$inc": bson.M{"reservedCash": delta},
+		"$set": bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+// freeCash is the aggregation expression for the balance less the cash
+// held for resting buys, and less unsettled proceeds when settledOnly
+func freeCash(settledOnly bool) bson.M {
+	held := bson.A{bson.M{"$ifNull": bson.A{"$reservedCash", 0}}}
+	if settledOnly {
+		held = append(held, bson.M{"$ifNull": bson.A{"$unsettledCash", 0}})
+	}
+	return bson.M{"$subtract": bson.A{"$balance", bson.M{"$add": held}}}
+}
+
+// AddUnsettledCash moves an account's unsettled sale proceeds by delta
+func (r *AccountRepository) AddUnsettledCash(ctx context.Context, accountID primitive.ObjectID, delta float64) error {
+	_, err := r.accountCollection.UpdateByID(ctx, accountID, bson.M{
+		"$inc": bson.M{"unsettledCash": delta},
+		"$set": bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+// AddAccruedInterest moves an account's pending accrued interest by delta
+func (r *AccountRepository) AddAccruedInterest(ctx context.Context, accountID primitive.ObjectID, delta float64) error {
+	_, err := r.accountCollection.UpdateByID(ctx, accountID, bson.M{
+		"$inc": bson.M{"accruedInterest": delta},
+		"$set": bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+// RecordGoodFaithViolation notes a violation at the given time and, when
+// restrictedUntil is set, restricts the account to settled cash until then
+func (r *AccountRepository) RecordGoodFaithViolation(ctx context.Context, accountID primitive.ObjectID, at time.Time, restrictedUntil *time.Time) error {
+	set := bson.M{"updatedAt": time.Now()}
+	if restrictedUntil != nil {
+		set["restrictedUntil"] = *restrictedUntil
+	}
+	_, err := r.accountCollection.UpdateByID(ctx, accountID, bson.M{
+		"$push": bson.M{"goodFaithViolations": at},
+		"$set":  set,
+	})
+	return err
+}
+
 func (r *AccountRepository) UpdateStatus(ctx context.Context, accountID primitive.ObjectID, status model.AccountStatus) error {
 	_, err := r.accountCollection.UpdateByID(ctx, accountID, bson.M{
 		"$set": bson.M{
@@ -96,6 +254,73 @@ func (r *AccountRepository) UpdateStatus(ctx context.Context, accountID primitiv
 	return err
 }
 
+// TransitionStatus atomically moves an account to a status only if it is
+// still in one of from, recording why and who. It reports whether it did.
+func (r *AccountRepository) TransitionStatus(ctx context.Context, accountID primitive.ObjectID, from []model.AccountStatus, to model.AccountStatus, reason string, actor model.AccountActor) (bool, error) {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"status":          to,
+			"statusReason":    reason,
+			"statusChangedBy": actor,
+			"updatedAt":       now,
+		},
+	}
+	if to == model.AccountStatusClosed {
+		update["$set"].(bson.M)["closedAt"] = now
+	} else {
+		update["$unset"] = bson.M{"closedAt": ""}
+	}
+
+	result, err := r.accountCollection.UpdateOne(ctx,
+		bson.M{"_id": accountID, "status": bson.M{"$in": from}},
+		update,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *AccountRepository) CreateStatusChange(ctx context.Context, change *model.AccountStatusChange) error {
+	change.CreatedAt = time.Now()
+	result, err := r.statusChangeCollection.InsertOne(ctx, change)
+	if err != nil {
+		return err
+	}
+	change.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindStatusChanges returns an account's status history, newest first
+func (r *AccountRepository) FindStatusChanges(ctx context.Context, accountID primitive.ObjectID) ([]model.AccountStatusChange, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.statusChangeCollection.Find(ctx, bson.M{"accountId": accountID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var changes []model.AccountStatusChange
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// HasPendingTransactions reports whether any of an account's deposits or
+// withdrawals are still waiting on review or the payment provider, or a
+// transfer is still posting
+func (r *AccountRepository) HasPendingTransactions(ctx context.Context, accountID primitive.ObjectID) (bool, error) {
+	count, err := r.transactionCollection.CountDocuments(ctx, bson.M{
+		"accountId": accountID,
+		"status": bson.M{"$in": []model.TransactionStatus{
+			model.TransactionStatusPending,
+			model.TransactionStatusPendingReview,
+		}},
+	})
+	return count > 0, err
+}
+
 func (r *AccountRepository) ExistsByUserAndCurrency(ctx context.Context, userID primitive.ObjectID, currency string) (bool, error) {
 	count, err := r.accountCollection.CountDocuments(ctx, bson.M{
 		"userId":   userID,
@@ -135,6 +360,25 @@ func (r *AccountRepository) GetTransactionsByAccountID(ctx context.Context, acco
 	return transactions, nil
 }
 
+// FindTransactionsBetween returns an account's transactions created in
+// [from, to), oldest first
+func (r *AccountRepository) FindTransactionsBetween(ctx context.Context, accountID primitive.ObjectID, from, to time.Time) ([]model.Transaction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.transactionCollection.Find(ctx, bson.M{
+		"accountId": accountID,
+		"createdAt": bson.M{"$gte": from, "$lt": to},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var transactions []model.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
 // UpdateField updates a single field on an account
 func (r *AccountRepository) UpdateField(ctx context.Context, accountID primitive.ObjectID, field string, value interface{}) error {
 	_, err := r.accountCollection.UpdateByID(ctx, accountID, bson.M{
@@ -163,3 +407,106 @@ func (r *AccountRepository) FindDemoByUserID(ctx context.Context, userID string)
 	}
 	return &account, nil
 }
+
+func (r *AccountRepository) FindTransactionByID(ctx context.Context, id string) (*model.Transaction, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var tx model.Transaction
+	if err := r.transactionCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// FindTransactionByProviderRef finds the transaction a payment provider
+// reference belongs to
+func (r *AccountRepository) FindTransactionByProviderRef(ctx context.Context, reference string) (*model.Transaction, error) {
+	var tx model.Transaction
+	if err := r.transactionCollection.FindOne(ctx, bson.M{"providerRef": reference}).Decode(&tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// SetProviderRef records the payment provider's reference for a
+// transaction, whatever its status by now
+func (r *AccountRepository) SetProviderRef(ctx context.Context, id primitive.ObjectID, reference string) error {
+	_, err := r.transactionCollection.UpdateByID(ctx, id, bson.M{
+		"$set": bson.M{"providerRef": reference, "updatedAt": time.Now()},
+	})
+	return err
+}
+
+// TransitionTransaction atomically applies set to a transaction only if
+// its status is still one of from. It reports whether it did, so
+// concurrent transitions can't both act on the same transaction.
+func (r *AccountRepository) TransitionTransaction(ctx context.Context, id primitive.ObjectID, from []model.TransactionStatus, set bson.M) (bool, error) {
+	set["updatedAt"] = time.Now()
+	result, err := r.transactionCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": bson.M{"$in": from}},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// FindTransactionsByStatus returns transactions of a type in a status,
+// oldest first
+func (r *AccountRepository) FindTransactionsByStatus(ctx context.Context, txType model.TransactionType, status model.TransactionStatus, limit int) ([]model.Transaction, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.transactionCollection.Find(ctx, bson.M{"type": txType, "status": status}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var transactions []model.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// TradingVolume totals an account's traded value, in its currency, since
+// a time
+func (r *AccountRepository) TradingVolume(ctx context.Context, accountID primitive.ObjectID, since time.Time) (float64, error) {
+	return r.SumTransactionsSince(ctx, accountID, model.TransactionTypeTrade, []model.TransactionStatus{model.TransactionStatusCompleted}, since)
+}
+
+// SumTransactionsSince totals an account's transactions of a type in the
+// given statuses created since a time
+func (r *AccountRepository) SumTransactionsSince(ctx context.Context, accountID primitive.ObjectID, txType model.TransactionType, statuses []model.TransactionStatus, since time.Time) (float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"accountId": accountID,
+			"type":      txType,
+			"status":    bson.M{"$in": statuses},
+			"createdAt": bson.M{"$gte": since},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$amount"}}}},
+	}
+
+	cursor, err := r.transactionCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Total, nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UnpostedInterest totals an account's accruals waiting to be posted
+type UnpostedInterest struct {
+	AccountID primitive.ObjectID `bson:"_id"`
+	Amount    float64            `bson:"amount"`
+	Days      int                `bson:"days"`
+}
+
+// InterestClaim totals the accruals a posting transaction claimed, up to
+// the last day it covers
+type InterestClaim struct {
+	TransactionID primitive.ObjectID `bson:"_id"`
+	AccountID     primitive.ObjectID `bson:"accountId"`
+	Amount        float64            `bson:"amount"`
+	LastDate      string             `bson:"lastDate"`
+}
+
+// unclaimed matches accruals no posting transaction has claimed yet
+var unclaimed = bson.M{"posted": false, "transactionId": bson.M{"$exists": false}}
+
+type InterestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewInterestRepository() *InterestRepository {
+	return &InterestRepository{
+		collection: database.GetCollection(model.InterestAccrualCollection),
+	}
+}
+
+// Accrue records an account's accrual for its day unless one exists. It
+// reports whether this call recorded it.
+func (r *InterestRepository) Accrue(ctx context.Context, accrual *model.InterestAccrual) (bool, error) {
+	accrual.CreatedAt = time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"accountId": accrual.AccountID, "date": accrual.Date},
+		bson.M{"$setOnInsert": accrual},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount == 1, nil
+}
+
+// LastAccrualDate returns the latest day any account accrued, or "" if
+// none has
+func (r *InterestRepository) LastAccrualDate(ctx context.Context) (string, error) {
+	var accrual model.InterestAccrual
+	opts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{}, opts).Decode(&accrual)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return accrual.Date, nil
+}
+
+// SumUnposted totals each account's unposted accruals dated before the
+// given day
+func (r *InterestRepository) SumUnposted(ctx context.Context, before string) ([]UnpostedInterest, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$and": bson.A{unclaimed, bson.M{"date": bson.M{"$lt": before}}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$accountId",
+			"amount": bson.M{"$sum": "$amount"},
+			"days":   bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var totals []UnpostedInterest
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// SumUnpostedFor totals one account's unposted accruals dated before the
+// given day
+func (r *InterestRepository) SumUnpostedFor(ctx context.Context, accountID primitive.ObjectID, before string) (float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$and": bson.A{unclaimed, bson.M{"accountId": accountID, "date": bson.M{"$lt": before}}}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "amount": bson.M{"$sum": "$amount"}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	var totals []UnpostedInterest
+	if err := cursor.All(ctx, &totals); err != nil {
+		return 0, err
+	}
+	if len(totals) == 0 {
+		return 0, nil
+	}
+	return totals[0].Amount, nil
+}
+
+// Claim marks an account's unclaimed accruals dated before the given day
+// as being posted by a transaction, returning how many it claimed
+func (r *InterestRepository) Claim(ctx context.Context, accountID primitive.ObjectID, before string, transactionID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"$and": bson.A{unclaimed, bson.M{"accountId": accountID, "date": bson.M{"$lt": before}}}},
+		bson.M{"$set": bson.M{"transactionId": transactionID}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// Unclaim hands a transaction's claimed accruals back, unposted
+func (r *InterestRepository) Unclaim(ctx context.Context, transactionID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"transactionId": transactionID, "posted": false},
+		bson.M{"$unset": bson.M{"transactionId": ""}},
+	)
+	return err
+}
+
+// MarkPosted marks the accruals a transaction claimed as posted,
+// returning how many it marked
+func (r *InterestRepository) MarkPosted(ctx context.Context, transactionID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"transactionId": transactionID, "posted": false},
+		bson.M{"$set": bson.M{"posted": true}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// FindClaims totals claimed accruals not yet marked posted, by the
+// transaction that claimed them. Given a transaction, only its claim.
+func (r *InterestRepository) FindClaims(ctx context.Context, transactionID *primitive.ObjectID) ([]InterestClaim, error) {
+	match := bson.M{"posted": false, "transactionId": bson.M{"$exists": true}}
+	if transactionID != nil {
+		match["transactionId"] = *transactionID
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$transactionId",
+			"accountId": bson.M{"$first": "$accountId"},
+			"amount":    bson.M{"$sum": "$amount"},
+			"lastDate":  bson.M{"$max": "$date"},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var claims []InterestClaim
+	if err := cursor.All(ctx, &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
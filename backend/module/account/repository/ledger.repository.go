@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LedgerRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLedgerRepository() *LedgerRepository {
+	return &LedgerRepository{
+		collection: database.GetCollection(model.JournalCollection),
+	}
+}
+
+func (r *LedgerRepository) Create(ctx context.Context, entry *model.JournalEntry) error {
+	entry.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return err
+	}
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID returns a journal entry
+func (r *LedgerRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.JournalEntry, error) {
+	var entry model.JournalEntry
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ExistsForReference reports whether an entry of a type references any of ids
+func (r *LedgerRepository) ExistsForReference(ctx context.Context, journalType model.JournalType, ids []primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"type":        journalType,
+		"referenceId": bson.M{"$in": ids},
+	})
+	return count > 0, err
+}
+
+// ClearPending records that an entry has been applied to an account's balance
+func (r *LedgerRepository) ClearPending(ctx context.Context, entryID, accountID primitive.ObjectID) error {
+	_, err := r.collection.UpdateByID(ctx, entryID, bson.M{
+		"$pull": bson.M{"pending": accountID},
+	})
+	return err
+}
+
+// FindPending returns entries posted before a time that some account's
+// balance hasn't taken yet, oldest first
+func (r *LedgerRepository) FindPending(ctx context.Context, before time.Time, limit int) ([]model.JournalEntry, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"pending.0": bson.M{"$exists": true},
+		"createdAt": bson.M{"$lt": before},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var entries []model.JournalEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindByAccountID returns journal entries touching an account, newest first
+func (r *LedgerRepository) FindByAccountID(ctx context.Context, accountID primitive.ObjectID, limit, offset int) ([]model.JournalEntry, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"lines.accountId": accountID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var entries []model.JournalEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Balance sums credits minus debits on one of an account's ledger books
+func (r *LedgerRepository) Balance(ctx context.Context, ledger model.LedgerAccount, accountID primitive.ObjectID) (float64, error) {
+	return r.balance(ctx, ledger, accountID, bson.M{"lines.accountId": accountID})
+}
+
+// BalanceAt is Balance as it stood at a time, from the entries posted
+// before it
+func (r *LedgerRepository) BalanceAt(ctx context.Context, ledger model.LedgerAccount, accountID primitive.ObjectID, at time.Time) (float64, error) {
+	return r.balance(ctx, ledger, accountID, bson.M{"lines.accountId": accountID, "createdAt": bson.M{"$lt": at}})
+}
+
+func (r *LedgerRepository) balance(ctx context.Context, ledger model.LedgerAccount, accountID primitive.ObjectID, match bson.M) (float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$match", Value: bson.M{"lines.ledger": ledger, "lines.accountId": accountID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"balance": bson.M{"$sum": bson.M{"$subtract": bson.A{"$lines.credit", "$lines.debit"}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Balance float64 `bson:"balance"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Balance, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SettlementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSettlementRepository() *SettlementRepository {
+	return &SettlementRepository{
+		collection: database.GetCollection(model.SettlementCollection),
+	}
+}
+
+func (r *SettlementRepository) Create(ctx context.Context, settlement *model.Settlement) error {
+	settlement.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, settlement)
+	if err != nil {
+		return err
+	}
+	settlement.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindDue returns pending settlements whose settlement date has come
+func (r *SettlementRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]model.Settlement, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "settleDate", Value: 1}}).
+		SetLimit(int64(limit))
+
+	return r.find(ctx, bson.M{
+		"status":     model.SettlementStatusPending,
+		"settleDate": bson.M{"$lte": now},
+	}, opts)
+}
+
+// FindPendingByAccountID returns an account's unsettled fills, soonest first
+func (r *SettlementRepository) FindPendingByAccountID(ctx context.Context, accountID primitive.ObjectID) ([]model.Settlement, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "settleDate", Value: 1}})
+	return r.find(ctx, bson.M{
+		"accountId": accountID,
+		"status":    model.SettlementStatusPending,
+	}, opts)
+}
+
+// FindUnpaidBuys returns an account's buys of a symbol paid with cash that
+// has not settled by now
+func (r *SettlementRepository) FindUnpaidBuys(ctx context.Context, accountID primitive.ObjectID, symbol string, now time.Time) ([]model.Settlement, error) {
+	return r.find(ctx, bson.M{
+		"accountId":     accountID,
+		"symbol":        symbol,
+		"buy":           true,
+		"fundsSettleAt": bson.M{"$gt": now},
+	})
+}
+
+// MarkSettled moves a settlement from PENDING to SETTLED. It reports
+// whether this call made the change.
+func (r *SettlementRepository) MarkSettled(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": model.SettlementStatusPending},
+		bson.M{"$set": bson.M{"status": model.SettlementStatusSettled, "settledAt": at}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// ClearFunding marks a buy's unsettled funding as used up by a violation,
+// so later sales of the same shares aren't counted again
+func (r *SettlementRepository) ClearFunding(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{"$unset": bson.M{"fundsSettleAt": ""}})
+	return err
+}
+
+func (r *SettlementRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]model.Settlement, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var settlements []model.Settlement
+	if err := cursor.All(ctx, &settlements); err != nil {
+		return nil, err
+	}
+	return settlements, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type StatementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewStatementRepository() *StatementRepository {
+	return &StatementRepository{
+		collection: database.GetCollection(model.StatementCollection),
+	}
+}
+
+func (r *StatementRepository) Create(ctx context.Context, statement *model.Statement) error {
+	statement.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, statement)
+	if err != nil {
+		return err
+	}
+	statement.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByAccountID lists an account's statements, latest period first,
+// without their rendered content
+func (r *StatementRepository) FindByAccountID(ctx context.Context, accountID primitive.ObjectID) ([]model.Statement, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "periodEnd", Value: -1}, {Key: "createdAt", Value: -1}}).
+		SetProjection(bson.M{"csv": 0, "pdf": 0})
+	cursor, err := r.collection.Find(ctx, bson.M{"accountId": accountID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var statements []model.Statement
+	if err := cursor.All(ctx, &statements); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+// FindByID returns a statement with its rendered content
+func (r *StatementRepository) FindByID(ctx context.Context, id string) (*model.Statement, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var statement model.Statement
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&statement); err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// FindFinal returns a statement for exactly this period generated after
+// the period ended, so its figures can no longer change
+func (r *StatementRepository) FindFinal(ctx context.Context, accountID primitive.ObjectID, start, end time.Time) (*model.Statement, error) {
+	var statement model.Statement
+	err := r.collection.FindOne(ctx, bson.M{
+		"accountId":   accountID,
+		"periodStart": start,
+		"periodEnd":   end,
+		"createdAt":   bson.M{"$gte": end},
+	}, options.FindOne().SetProjection(bson.M{"csv": 0, "pdf": 0})).Decode(&statement)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
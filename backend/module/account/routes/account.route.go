@@ -1,9 +1,18 @@
 package routes
 
 import (
+	"context"
+	"log"
+
 	"github.com/bricksocoolxd/bengi-investment-system/module/account/controller"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/interest"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/payment"
 	"github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
 	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	authModel "github.com/bricksocoolxd/bengi-investment-system/module/auth/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	tradeRepo "github.com/bricksocoolxd/bengi-investment-system/module/trade/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/config"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
 	"github.com/gofiber/fiber/v2"
 )
@@ -11,8 +20,45 @@ import (
 func RegisterRoutes(app *fiber.App) {
 	// Wire up dependencies
 	repo := repository.NewAccountRepository()
-	accountService := service.NewAccountService(repo)
+	ledgerService := service.NewLedgerService(repository.NewLedgerRepository(), repo)
+	provider := paymentProvider()
+	paymentService := service.NewPaymentService(repo, ledgerService, provider, service.WithdrawalLimits{
+		Single: config.AppConfig.WithdrawalReviewAmount,
+		Daily:  config.AppConfig.WithdrawalReviewDaily,
+	})
+	accountService := service.NewAccountService(repo, ledgerService, fx.Default(), paymentService)
+
+	// Posts and transfers cut short are finished from the journal
+	go ledgerService.Start(context.Background(), service.LedgerRecoveryInterval)
+	go accountService.Start(context.Background(), service.LedgerRecoveryInterval)
 	ctrl := controller.NewAccountController(accountService)
+	ledgerCtrl := controller.NewLedgerController(ledgerService)
+	paymentCtrl := controller.NewPaymentController(paymentService, config.AppConfig.PaymentWebhookSecret)
+
+	// Fills settle on their settlement date, clearing unsettled proceeds
+	settlementService := service.NewSettlementService(repository.NewSettlementRepository(), repo, ledgerService)
+	settlementCtrl := controller.NewSettlementController(settlementService)
+	go settlementService.Start(context.Background(), service.SettlementInterval)
+
+	// Interest accrues daily on cash and margin debit and posts monthly
+	interestService := service.NewInterestService(repository.NewInterestRepository(), repo, ledgerService, interest.Default())
+	go interestService.Start(context.Background(), service.InterestInterval)
+
+	// Statements: requested on demand, and for every account each month
+	statementService := service.NewStatementService(
+		repository.NewStatementRepository(),
+		repo,
+		repository.NewLedgerRepository(),
+		tradeRepo.NewTradeRepository(),
+		tradeRepo.NewTradePrintRepository(),
+		fx.Default(),
+	)
+	statementCtrl := controller.NewStatementController(statementService)
+	go statementService.Start(context.Background(), service.StatementInterval)
+
+	// Freezes, closes and reopens, with their audit trail
+	lifecycleService := service.NewLifecycleService(repo, accountService, interestService)
+	lifecycleCtrl := controller.NewLifecycleController(lifecycleService)
 
 	// All routes are protected
 	accounts := app.Group("/api/v1/accounts", middleware.AuthRequired())
@@ -20,7 +66,86 @@ func RegisterRoutes(app *fiber.App) {
 	accounts.Post("/", ctrl.CreateAccount)
 	accounts.Get("/", ctrl.GetAccounts)
 	accounts.Get("/:id", ctrl.GetAccountByID)
+	accounts.Put("/:id/self-trade-mode", ctrl.SetSelfTradeMode)
 	accounts.Post("/:id/deposit", ctrl.Deposit)
 	accounts.Post("/:id/withdraw", ctrl.Withdraw)
+	accounts.Post("/:id/transfer", ctrl.Transfer)
 	accounts.Get("/:id/transactions", ctrl.GetTransactions)
+	accounts.Post("/:id/withdrawals/:txId/cancel", paymentCtrl.CancelWithdrawal)
+	accounts.Get("/:id/journal", ledgerCtrl.GetJournal)
+	accounts.Get("/:id/reconcile", ledgerCtrl.Reconcile)
+	accounts.Get("/:id/settlements", settlementCtrl.GetSettlements)
+	accounts.Get("/:id/statements", statementCtrl.GetStatements)
+	accounts.Post("/:id/statements", statementCtrl.RequestStatement)
+	accounts.Get("/:id/statements/:statementId/download", statementCtrl.Download)
+	accounts.Post("/:id/freeze", lifecycleCtrl.Freeze)
+	accounts.Post("/:id/unfreeze", lifecycleCtrl.Unfreeze)
+	accounts.Post("/:id/close", lifecycleCtrl.Close)
+	accounts.Get("/:id/status-history", lifecycleCtrl.GetStatusHistory)
+
+	// Ledger-wide reconciliation (admin only)
+	ledger := app.Group("/api/v1/ledger",
+		middleware.AuthRequired(),
+		middleware.RoleRequired(authModel.RoleAdmin),
+	)
+
+	ledger.Get("/reconcile", ledgerCtrl.ReconcileAll)
+
+	// Withdrawal review queue (admin only)
+	withdrawals := app.Group("/api/v1/withdrawals",
+		middleware.AuthRequired(),
+		middleware.RoleRequired(authModel.RoleAdmin),
+	)
+
+	withdrawals.Get("/review", paymentCtrl.GetReviewQueue)
+	withdrawals.Post("/:id/approve", paymentCtrl.ApproveWithdrawal)
+	withdrawals.Post("/:id/reject", paymentCtrl.RejectWithdrawal)
+
+	// Any account's statements, for auditors (admin only)
+	statements := app.Group("/api/v1/statements",
+		middleware.AuthRequired(),
+		middleware.RoleRequired(authModel.RoleAdmin),
+	)
+
+	statements.Get("/accounts/:id", statementCtrl.AdminGetStatements)
+	statements.Post("/accounts/:id", statementCtrl.AdminRequestStatement)
+	statements.Get("/accounts/:id/:statementId/download", statementCtrl.AdminDownload)
+
+	// Any account's lifecycle (admin only)
+	lifecycle := app.Group("/api/v1/lifecycle",
+		middleware.AuthRequired(),
+		middleware.RoleRequired(authModel.RoleAdmin),
+	)
+
+	lifecycle.Post("/accounts/:id/freeze", lifecycleCtrl.AdminFreeze)
+	lifecycle.Post("/accounts/:id/unfreeze", lifecycleCtrl.AdminUnfreeze)
+	lifecycle.Post("/accounts/:id/close", lifecycleCtrl.AdminClose)
+	lifecycle.Post("/accounts/:id/reopen", lifecycleCtrl.AdminReopen)
+	lifecycle.Get("/accounts/:id/history", lifecycleCtrl.AdminGetStatusHistory)
+
+	// Provider callbacks are authenticated by their signature
+	payments := app.Group("/api/v1/payments")
+	payments.Post("/webhook", paymentCtrl.Webhook)
+	if provider.Name() == "stub" {
+		payments.Post("/stub/:reference/:outcome",
+			middleware.AuthRequired(),
+			middleware.RoleRequired(authModel.RoleAdmin),
+			paymentCtrl.SimulateStub,
+		)
+	}
+}
+
+// paymentProvider picks the provider named in the config. Without one,
+// live deposits and withdrawals are refused; the stub, which settles
+// payments on request, is never used in production.
+func paymentProvider() payment.Provider {
+	switch name := config.AppConfig.PaymentProvider; {
+	case name == "stub" && !config.AppConfig.IsProduction():
+		return payment.NewStubProvider()
+	case name == "":
+		log.Println("⚠️ No payment provider configured, live deposits and withdrawals are disabled")
+	default:
+		log.Printf("⚠️ Payment provider %q is not available, live deposits and withdrawals are disabled", name)
+	}
+	return payment.NoProvider{}
 }
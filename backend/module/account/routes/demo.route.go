@@ -10,7 +10,7 @@ import (
 
 func RegisterDemoRoutes(app *fiber.App) {
 	repo := repository.NewAccountRepository()
-	demoService := service.NewDemoService(repo)
+	demoService := service.NewDemoService(repo, service.NewLedgerService(repository.NewLedgerRepository(), repo))
 	ctrl := controller.NewDemoController(demoService)
 
 	// Demo routes - all require authentication
@@ -3,10 +3,15 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
+	"math"
+	"time"
 
 	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
 	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
 	"github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -15,20 +20,51 @@ var (
 	ErrAccountAlreadyExists = errors.New("account with this currency already exists")
 	ErrInsufficientBalance  = errors.New("insufficient balance")
 	ErrAccountFrozen        = errors.New("account is frozen")
+	ErrAccountClosed        = errors.New("account is closed")
 	ErrSameAccount          = errors.New("cannot transfer to same account")
 	ErrInvaludAmount        = errors.New("amount must be greater than 0")
+	ErrUnsettledFunds       = errors.New("amount exceeds settled cash")
+	ErrReservedFunds        = errors.New("amount exceeds cash not held for open buy orders")
+	ErrTransferAcrossTypes  = errors.New("cannot transfer between demo and live accounts")
 )
 
 type AccountService struct {
 	repository *repository.AccountRepository
+	ledger     *LedgerService
+	rates      fx.Provider
+	payments   *PaymentService
 }
 
-func NewAccountService(repository *repository.AccountRepository) *AccountService {
+func NewAccountService(repository *repository.AccountRepository, ledger *LedgerService, rates fx.Provider, payments *PaymentService) *AccountService {
 	return &AccountService{
 		repository: repository,
+		ledger:     ledger,
+		rates:      rates,
+		payments:   payments,
 	}
 }
 
+// CheckActive refuses money movement and trading on accounts that are
+// frozen, closed or being closed
+func CheckActive(account *model.Account) error {
+	switch account.Status {
+	case model.AccountStatusFrozen:
+		return ErrAccountFrozen
+	case model.AccountStatusClosed, model.AccountStatusClosing:
+		return ErrAccountClosed
+	}
+	return nil
+}
+
+// CheckSameType refuses moving cash between a demo and a live account, so
+// virtual funds can never reach an account that pays out real money
+func CheckSameType(from, to *model.Account) error {
+	if (from.Type == model.AccountTypeDemo) != (to.Type == model.AccountTypeDemo) {
+		return ErrTransferAcrossTypes
+	}
+	return nil
+}
+
 func (s *AccountService) CreateAccount(ctx context.Context, userID string, req *dto.CreateAccountRequest) (*dto.AccountResponse, error) {
 	userObjectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -82,7 +118,8 @@ func (s *AccountService) GetAccountByID(ctx context.Context, accountID, userID s
 	return s.toAccountResponse(account), nil
 }
 
-func (s *AccountService) Deposit(ctx context.Context, accountID, userID string, req *dto.DepositRequest) (*dto.TransactionResponse, error) {
+// SetSelfTradeMode sets the account's default self-trade prevention mode
+func (s *AccountService) SetSelfTradeMode(ctx context.Context, accountID, userID string, req *dto.SelfTradeModeRequest) (*dto.AccountResponse, error) {
 	account, err := s.repository.FindByID(ctx, accountID)
 	if err != nil {
 		return nil, ErrAccountNotFound
@@ -92,17 +129,36 @@ func (s *AccountService) Deposit(ctx context.Context, accountID, userID string,
 		return nil, ErrAccountNotFound
 	}
 
-	if account.Status == model.AccountStatusFrozen {
-		return nil, ErrAccountFrozen
+	if err := s.repository.UpdateField(ctx, account.ID, "selfTradeMode", req.Mode); err != nil {
+		return nil, err
 	}
+	account.SelfTradeMode = req.Mode
 
-	balanceBefore := account.Balance
-	balanceAfter := account.Balance + req.Amount
+	return s.toAccountResponse(account), nil
+}
 
-	if err := s.repository.UpdateBalance(ctx, account.ID, balanceAfter); err != nil {
+func (s *AccountService) Deposit(ctx context.Context, accountID, userID string, req *dto.DepositRequest) (*dto.TransactionResponse, error) {
+	account, err := s.repository.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+
+	if account.UserID.Hex() != userID {
+		return nil, ErrAccountNotFound
+	}
+
+	if err := CheckActive(account); err != nil {
 		return nil, err
 	}
 
+	// Real money waits for the payment provider; demo cash is credited at once
+	if account.Type != model.AccountTypeDemo {
+		return s.payments.RequestDeposit(ctx, account, req)
+	}
+
+	balanceBefore := account.Balance
+	balanceAfter := account.Balance + req.Amount
+
 	tx := &model.Transaction{
 		AccountID:     account.ID,
 		Type:          model.TransactionTypeDeposit,
@@ -117,11 +173,15 @@ func (s *AccountService) Deposit(ctx context.Context, accountID, userID string,
 		tx.Description = "Deposit"
 	}
 
+	if err := s.ledger.Post(ctx, DepositJournal(account.ID, account.Currency, req.Amount, tx.Description)); err != nil {
+		return nil, err
+	}
+
 	if err := s.repository.CreateTransaction(ctx, tx); err != nil {
 		return nil, err
 	}
 
-	return s.toTransactionResponse(tx), nil
+	return toTransactionResponse(tx), nil
 }
 
 func (s *AccountService) Withdraw(ctx context.Context, accountID, userID string, req *dto.WithdrawRequest) (*dto.TransactionResponse, error) {
@@ -134,8 +194,8 @@ func (s *AccountService) Withdraw(ctx context.Context, accountID, userID string,
 		return nil, ErrAccountNotFound
 	}
 
-	if account.Status == model.AccountStatusFrozen {
-		return nil, ErrAccountFrozen
+	if err := CheckActive(account); err != nil {
+		return nil, err
 	}
 
 	if req.Amount <= 0 {
@@ -145,14 +205,20 @@ func (s *AccountService) Withdraw(ctx context.Context, accountID, userID string,
 	if req.Amount > account.Balance {
 		return nil, ErrInsufficientBalance
 	}
+	if req.Amount > account.SettledCash() {
+		return nil, ErrUnsettledFunds
+	}
+	if req.Amount > account.FreeCash() {
+		return nil, ErrReservedFunds
+	}
+
+	if account.Type != model.AccountTypeDemo {
+		return s.payments.RequestWithdrawal(ctx, account, req)
+	}
 
 	balanceBefore := account.Balance
 	balanceAfter := account.Balance - req.Amount
 
-	if err := s.repository.UpdateBalance(ctx, account.ID, balanceAfter); err != nil {
-		return nil, err
-	}
-
 	tx := &model.Transaction{
 		AccountID:     account.ID,
 		Type:          model.TransactionTypeWithdraw,
@@ -167,11 +233,201 @@ func (s *AccountService) Withdraw(ctx context.Context, accountID, userID string,
 		tx.Description = "Withdraw"
 	}
 
+	if err := s.ledger.Post(ctx, WithdrawJournal(account.ID, account.Currency, req.Amount, tx.Description)); err != nil {
+		return nil, err
+	}
+
 	if err := s.repository.CreateTransaction(ctx, tx); err != nil {
 		return nil, err
 	}
 
-	return s.toTransactionResponse(tx), nil
+	return toTransactionResponse(tx), nil
+}
+
+// Transfer moves cash between two of a user's accounts, converting at
+// the current FX rate when their currencies differ. Both sides are
+// recorded in a single journal entry and as linked transactions, which
+// complete only once the entry is posted.
+func (s *AccountService) Transfer(ctx context.Context, accountID, userID string, req *dto.TransferRequest) (*dto.TransferResponse, error) {
+	if accountID == req.ToAccountID {
+		return nil, ErrSameAccount
+	}
+
+	from, err := s.repository.FindByID(ctx, accountID)
+	if err != nil || from.UserID.Hex() != userID {
+		return nil, ErrAccountNotFound
+	}
+	to, err := s.repository.FindByID(ctx, req.ToAccountID)
+	if err != nil || to.UserID.Hex() != userID {
+		return nil, ErrAccountNotFound
+	}
+
+	if err := CheckActive(from); err != nil {
+		return nil, err
+	}
+	if err := CheckActive(to); err != nil {
+		return nil, err
+	}
+
+	if req.Amount <= 0 {
+		return nil, ErrInvaludAmount
+	}
+
+	if req.Amount > from.Balance {
+		return nil, ErrInsufficientBalance
+	}
+	if req.Amount > from.SettledCash() {
+		return nil, ErrUnsettledFunds
+	}
+	if req.Amount > from.FreeCash() {
+		return nil, ErrReservedFunds
+	}
+
+	return s.transfer(ctx, from, to, req.Amount, req.Description)
+}
+
+// transfer books a transfer between two accounts of the same type,
+// without checking their status or cash
+func (s *AccountService) transfer(ctx context.Context, from, to *model.Account, amount float64, description string) (*dto.TransferResponse, error) {
+	if err := CheckSameType(from, to); err != nil {
+		return nil, err
+	}
+
+	converted, rate, err := fx.Convert(s.rates, amount, from.Currency, to.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if converted <= 0 {
+		return nil, ErrInvaludAmount
+	}
+
+	// Each side references the other
+	outID, inID := primitive.NewObjectID(), primitive.NewObjectID()
+	out := &model.Transaction{
+		ID:            outID,
+		AccountID:     from.ID,
+		Type:          model.TransactionTypeTransfer,
+		Amount:        amount,
+		BalanceBefore: from.Balance,
+		BalanceAfter:  from.Balance - amount,
+		ReferenceType: "TRANSACTION",
+		ReferenceID:   &inID,
+		Description:   description,
+	}
+	in := &model.Transaction{
+		ID:            inID,
+		AccountID:     to.ID,
+		Type:          model.TransactionTypeTransfer,
+		Amount:        converted,
+		BalanceBefore: to.Balance,
+		BalanceAfter:  to.Balance + converted,
+		ReferenceType: "TRANSACTION",
+		ReferenceID:   &outID,
+		Description:   description,
+	}
+
+	if out.Description == "" {
+		out.Description = "Transfer to " + to.ID.Hex()
+		in.Description = "Transfer from " + from.ID.Hex()
+	}
+
+	// Both rows go in pending before the entry is posted, and settle with
+	// it; ResolveTransfers settles any a crash leaves pending
+	out.Status, in.Status = model.TransactionStatusPending, model.TransactionStatusPending
+	if err := s.repository.CreateTransaction(ctx, out); err != nil {
+		return nil, err
+	}
+	if err := s.repository.CreateTransaction(ctx, in); err != nil {
+		s.finishTransfer(ctx, model.TransactionStatusFailed, err.Error(), out)
+		return nil, err
+	}
+
+	entry := TransferJournal(from.ID, from.Currency, amount, to.ID, to.Currency, converted, out.Description)
+	entry.ReferenceType = "TRANSACTION"
+	entry.ReferenceID = &outID
+	if err := s.ledger.PostFunded(ctx, entry); err != nil {
+		s.finishTransfer(ctx, model.TransactionStatusFailed, err.Error(), out, in)
+		return nil, err
+	}
+	s.finishTransfer(ctx, model.TransactionStatusCompleted, "", out, in)
+
+	return &dto.TransferResponse{
+		From:            *toTransactionResponse(out),
+		To:              *toTransactionResponse(in),
+		FromCurrency:    from.Currency,
+		ToCurrency:      to.Currency,
+		Amount:          amount,
+		ConvertedAmount: converted,
+		Rate:            rate,
+	}, nil
+}
+
+// finishTransfer moves pending transfer rows to a final status
+func (s *AccountService) finishTransfer(ctx context.Context, status model.TransactionStatus, reason string, txs ...*model.Transaction) {
+	set := bson.M{"status": status}
+	if status == model.TransactionStatusCompleted {
+		set["completedAt"] = time.Now()
+	} else {
+		set["failureReason"] = reason
+	}
+	for _, tx := range txs {
+		tx.Status = status
+		if _, err := s.repository.TransitionTransaction(ctx, tx.ID, []model.TransactionStatus{model.TransactionStatusPending}, set); err != nil {
+			log.Printf("⚠️ Failed to move transfer %s to %s: %v", tx.ID.Hex(), status, err)
+		}
+	}
+}
+
+// Start settles transfers left pending at startup and then every interval
+func (s *AccountService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	now := time.Now()
+	for {
+		if err := s.ResolveTransfers(ctx, now); err != nil {
+			log.Printf("[Transfer] Failed to resolve: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+	}
+}
+
+// ResolveTransfers settles transfer rows a crash left pending: completed
+// if their journal entry was posted, failed if it never was. Rows younger
+// than ledgerRecoveryAge may still be posting and are left alone.
+func (s *AccountService) ResolveTransfers(ctx context.Context, now time.Time) error {
+	pending, err := s.repository.FindTransactionsByStatus(ctx, model.TransactionTypeTransfer, model.TransactionStatusPending, ledgerRecoveryBatch)
+	if err != nil {
+		return err
+	}
+
+	before := now.Add(-ledgerRecoveryAge)
+	for i := range pending {
+		tx := &pending[i]
+		if tx.CreatedAt.After(before) {
+			break
+		}
+
+		ids := []primitive.ObjectID{tx.ID}
+		if tx.ReferenceID != nil {
+			ids = append(ids, *tx.ReferenceID)
+		}
+		posted, err := s.ledger.Posted(ctx, model.JournalTypeTransfer, ids)
+		if err != nil {
+			return err
+		}
+		if posted {
+			s.finishTransfer(ctx, model.TransactionStatusCompleted, "", tx)
+		} else {
+			s.finishTransfer(ctx, model.TransactionStatusFailed, "transfer was not posted", tx)
+		}
+	}
+	return nil
 }
 
 func (s *AccountService) GetTransactions(ctx context.Context, accountID, userID string, limit, offset int) ([]dto.TransactionResponse, error) {
@@ -197,7 +453,7 @@ func (s *AccountService) GetTransactions(ctx context.Context, accountID, userID
 
 	var responses []dto.TransactionResponse
 	for _, tx := range transactions {
-		responses = append(responses, *s.toTransactionResponse(&tx))
+		responses = append(responses, *toTransactionResponse(&tx))
 	}
 
 	return responses, nil
@@ -205,26 +461,55 @@ func (s *AccountService) GetTransactions(ctx context.Context, accountID, userID
 
 // Helper: Convert Account to AccountResponse
 func (s *AccountService) toAccountResponse(acc *model.Account) *dto.AccountResponse {
-	return &dto.AccountResponse{
-		ID:       acc.ID.Hex(),
-		UserID:   acc.UserID.Hex(),
-		Currency: acc.Currency,
-		Balance:  acc.Balance,
-		Status:   string(acc.Status),
-	}
+	now := time.Now()
+	response := &dto.AccountResponse{
+		ID:            acc.ID.Hex(),
+		UserID:        acc.UserID.Hex(),
+		Currency:      acc.Currency,
+		Balance:       acc.Balance,
+		Status:        string(acc.Status),
+		StatusReason:  acc.StatusReason,
+		ClosedAt:      acc.ClosedAt,
+		SelfTradeMode: acc.SelfTradeMode,
+		SettledCash:   acc.SettledCash(),
+		UnsettledCash: acc.Balance - acc.SettledCash(),
+		ReservedCash:  acc.ReservedCash,
+		BuyingPower:   acc.BuyingPower(now),
+
+		GoodFaithViolations: acc.RecentViolations(now),
+		AccruedInterest:     math.Round(acc.AccruedInterest*100) / 100,
+		FeePlan:             acc.FeePlan,
+	}
+	if acc.IsRestricted(now) {
+		response.RestrictedUntil = acc.RestrictedUntil
+	}
+	return response
 }
 
 // Helper: Convert Transaction to TransactionResponse
-func (s *AccountService) toTransactionResponse(tx *model.Transaction) *dto.TransactionResponse {
-	return &dto.TransactionResponse{
+func toTransactionResponse(tx *model.Transaction) *dto.TransactionResponse {
+	response := &dto.TransactionResponse{
 		ID:            tx.ID.Hex(),
 		AccountID:     tx.AccountID.Hex(),
 		Type:          string(tx.Type),
 		Amount:        tx.Amount,
 		BalanceBefore: tx.BalanceBefore,
 		BalanceAfter:  tx.BalanceAfter,
+		ReferenceType: tx.ReferenceType,
 		Status:        string(tx.Status),
 		Description:   tx.Description,
+		ProviderRef:   tx.ProviderRef,
+		FailureReason: tx.FailureReason,
+		ReviewNote:    tx.ReviewNote,
 		CreatedAt:     tx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
+	if tx.ReferenceID != nil {
+		referenceID := tx.ReferenceID.Hex()
+		response.ReferenceID = &referenceID
+	}
+	if tx.CompletedAt != nil {
+		completedAt := tx.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.CompletedAt = &completedAt
+	}
+	return response
 }
@@ -18,11 +18,13 @@ var (
 
 type DemoService struct {
 	repository *repository.AccountRepository
+	ledger     *LedgerService
 }
 
-func NewDemoService(repository *repository.AccountRepository) *DemoService {
+func NewDemoService(repository *repository.AccountRepository, ledger *LedgerService) *DemoService {
 	return &DemoService{
 		repository: repository,
+		ledger:     ledger,
 	}
 }
 
@@ -52,7 +54,6 @@ func (s *DemoService) CreateDemoAccount(ctx context.Context, userID string, req
 	account := &model.Account{
 		UserID:         userObjectID,
 		Currency:       currency,
-		Balance:        initialBalance,
 		Status:         model.AccountStatusActive,
 		Type:           model.AccountTypeDemo,
 		Leverage:       leverage,
@@ -67,6 +68,12 @@ func (s *DemoService) CreateDemoAccount(ctx context.Context, userID string, req
 		return nil, err
 	}
 
+	// The virtual funds arrive through the ledger like any other cash
+	if err := s.ledger.Post(ctx, OpeningJournal(account.ID, account.Currency, initialBalance)); err != nil {
+		return nil, err
+	}
+	account.Balance = initialBalance
+
 	return &dto.CreateDemoAccountResponse{
 		AccountID:      account.ID.Hex(),
 		Currency:       account.Currency,
@@ -93,12 +100,15 @@ func (s *DemoService) DemoDeposit(ctx context.Context, accountID, userID string,
 	if account.Type != model.AccountTypeDemo {
 		return nil, ErrNotDemoAccount
 	}
+	if err := CheckActive(account); err != nil {
+		return nil, err
+	}
 
 	// Add funds
 	newBalance := account.Balance + req.Amount
 	newTotalDeposits := account.TotalDeposits + req.Amount
 
-	if err := s.repository.UpdateBalance(ctx, account.ID, newBalance); err != nil {
+	if err := s.ledger.Post(ctx, DepositJournal(account.ID, account.Currency, req.Amount, "Demo deposit")); err != nil {
 		return nil, err
 	}
 
@@ -131,6 +141,9 @@ func (s *DemoService) DemoReset(ctx context.Context, accountID, userID string, r
 	if account.Type != model.AccountTypeDemo {
 		return nil, ErrNotDemoAccount
 	}
+	if err := CheckActive(account); err != nil {
+		return nil, err
+	}
 
 	// Reset balance
 	newBalance := account.InitialBalance
@@ -138,8 +151,10 @@ func (s *DemoService) DemoReset(ctx context.Context, accountID, userID string, r
 		newBalance = req.InitialBalance
 	}
 
-	if err := s.repository.UpdateBalance(ctx, account.ID, newBalance); err != nil {
-		return nil, err
+	if delta := newBalance - account.Balance; delta != 0 {
+		if err := s.ledger.Post(ctx, AdjustmentJournal(account.ID, account.Currency, delta, "Demo reset")); err != nil {
+			return nil, err
+		}
 	}
 
 	// Reset other fields
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/interest"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// InterestInterval is how often the interest job checks for days to
+	// accrue and months to post
+	InterestInterval = time.Hour
+
+	// interestCatchUpDays caps how many missed days one run accrues; the
+	// next run carries on from there
+	interestCatchUpDays = 62
+)
+
+// InterestService accrues interest on every account's cash each day and
+// posts the month's accruals as one INTEREST transaction after it ends.
+// Accruing and posting are idempotent, so the job can run as often as
+// it likes.
+type InterestService struct {
+	repository        *repository.InterestRepository
+	accountRepository *repository.AccountRepository
+	ledger            *LedgerService
+	schedule          *interest.Schedule
+	mu                sync.Mutex // Serializes runs
+}
+
+func NewInterestService(repository *repository.InterestRepository, accountRepository *repository.AccountRepository, ledger *LedgerService, schedule *interest.Schedule) *InterestService {
+	return &InterestService{
+		repository:        repository,
+		accountRepository: accountRepository,
+		ledger:            ledger,
+		schedule:          schedule,
+	}
+}
+
+// Start runs the job at startup and then every interval until ctx is
+// cancelled
+func (s *InterestService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	now := time.Now()
+	for {
+		s.Run(ctx, now)
+
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+	}
+}
+
+// Run accrues every day from the last one accrued up to the day before
+// now, so days the job was down are caught up, settles posts an earlier
+// run left claimed, and posts every month before now's
+func (s *InterestService) Run(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	today := startOfDayUTC(now)
+	day, err := s.firstDayToAccrue(ctx, today)
+	if err != nil {
+		log.Printf("[Interest] Failed to find the last accrual: %v", err)
+	}
+	for n := 0; err == nil && day.Before(today) && n < interestCatchUpDays; n++ {
+		if _, err = s.AccrueDay(ctx, day); err != nil {
+			log.Printf("[Interest] Failed to accrue %s: %v", day.Format("2006-01-02"), err)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	if err := s.recoverClaims(ctx); err != nil {
+		log.Printf("[Interest] Failed to recover claimed accruals: %v", err)
+	}
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if _, err := s.PostAccrued(ctx, monthStart); err != nil {
+		log.Printf("[Interest] Failed to post: %v", err)
+	}
+}
+
+// firstDayToAccrue is the last day accrued, which may have been cut
+// short, or yesterday when nothing has accrued yet
+func (s *InterestService) firstDayToAccrue(ctx context.Context, today time.Time) (time.Time, error) {
+	last, err := s.repository.LastAccrualDate(ctx)
+	if err != nil || last == "" {
+		return today.AddDate(0, 0, -1), err
+	}
+	return time.Parse("2006-01-02", last)
+}
+
+// AccrueDay accrues a day's interest on each account's cash as the ledger
+// had it at the end of that day. Days already accrued are skipped.
+// Returns how many accounts accrued.
+func (s *InterestService) AccrueDay(ctx context.Context, day time.Time) (int, error) {
+	accounts, err := s.accountRepository.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	day = startOfDayUTC(day)
+	end := day.AddDate(0, 0, 1)
+	date := day.Format("2006-01-02")
+	accrued := 0
+	for i := range accounts {
+		account := &accounts[i]
+		if account.Status == model.AccountStatusClosed || !account.CreatedAt.Before(end) {
+			continue
+		}
+		rates, ok := s.schedule.Lookup(account.Currency, string(account.Type))
+		if !ok {
+			continue
+		}
+		balance, err := s.ledger.CashBalanceAt(ctx, account.ID, end)
+		if err != nil {
+			return accrued, err
+		}
+		amount := rates.Daily(balance)
+		if amount == 0 {
+			continue
+		}
+
+		recorded, err := s.repository.Accrue(ctx, &model.InterestAccrual{
+			AccountID: account.ID,
+			Date:      date,
+			Currency:  account.Currency,
+			Balance:   balance,
+			Rate:      rates.Effective(balance),
+			Amount:    amount,
+		})
+		if err != nil {
+			return accrued, err
+		}
+		if !recorded {
+			continue
+		}
+		if err := s.accountRepository.AddAccruedInterest(ctx, account.ID, amount); err != nil {
+			return accrued, err
+		}
+		accrued++
+	}
+	return accrued, nil
+}
+
+// PostAccrued posts each account's accruals dated before the given day,
+// rounded to cents, as an INTEREST transaction. Returns how many
+// accounts it posted.
+func (s *InterestService) PostAccrued(ctx context.Context, before time.Time) (int, error) {
+	cutoff := before.UTC().Format("2006-01-02")
+	totals, err := s.repository.SumUnposted(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	period := before.UTC().AddDate(0, 0, -1).Format("January 2006")
+	posted := 0
+	for _, total := range totals {
+		account, err := s.accountRepository.FindByID(ctx, total.AccountID.Hex())
+		if err != nil {
+			log.Printf("[Interest] Account %s not found: %v", total.AccountID.Hex(), err)
+			continue
+		}
+
+		ok, err := s.post(ctx, account, total.Amount, cutoff, period)
+		if err != nil {
+			return posted, err
+		}
+		if ok {
+			posted++
+		}
+	}
+	return posted, nil
+}
+
+// PostAccount posts everything an account has accrued up to now right
+// away, rather than after the month ends, as when it is about to close.
+// It reports whether anything was posted.
+func (s *InterestService) PostAccount(ctx context.Context, account *model.Account, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := startOfDayUTC(now).AddDate(0, 0, 1).Format("2006-01-02")
+	total, err := s.repository.SumUnpostedFor(ctx, account.ID, cutoff)
+	if err != nil {
+		return false, err
+	}
+	if total == 0 {
+		return false, nil
+	}
+	return s.post(ctx, account, total, cutoff, now.UTC().Format("January 2006"))
+}
+
+// post claims an account's accruals before cutoff and books their total,
+// rounded to cents, as an INTEREST transaction. The accruals are marked
+// posted only once it is booked, and handed back if it can't be; a total
+// that rounds to nothing stays unclaimed and carries into the next post.
+func (s *InterestService) post(ctx context.Context, account *model.Account, total float64, cutoff, period string) (bool, error) {
+	if math.Round(total*100) == 0 {
+		return false, nil
+	}
+
+	// Claim the accruals first so a rerun can't post them twice
+	txID := primitive.NewObjectID()
+	claimed, err := s.repository.Claim(ctx, account.ID, cutoff, txID)
+	if err != nil {
+		return false, err
+	}
+	if claimed == 0 {
+		return false, nil
+	}
+	claims, err := s.repository.FindClaims(ctx, &txID)
+	if err != nil || len(claims) == 0 {
+		s.unclaim(ctx, txID)
+		return false, err
+	}
+	claim := &claims[0]
+
+	amount := math.Round(claim.Amount*100) / 100
+	if amount == 0 {
+		s.unclaim(ctx, txID)
+		return false, nil
+	}
+	description := interestDescription(amount, period)
+	entry := InterestJournal(account.ID, account.Currency, amount, description)
+	entry.ReferenceType = "TRANSACTION"
+	entry.ReferenceID = &txID
+	if err := s.ledger.Post(ctx, entry); err != nil {
+		s.unclaim(ctx, txID)
+		return false, err
+	}
+
+	if err := s.finish(ctx, account, claim, description); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// finish records the INTEREST transaction for a claim whose journal entry
+// is posted, then marks its accruals posted and takes them off the
+// account's accrued interest. Each step is skipped if already done.
+func (s *InterestService) finish(ctx context.Context, account *model.Account, claim *repository.InterestClaim, description string) error {
+	amount := math.Round(claim.Amount*100) / 100
+	if _, err := s.accountRepository.FindTransactionByID(ctx, claim.TransactionID.Hex()); errors.Is(err, mongo.ErrNoDocuments) {
+		err = s.accountRepository.CreateTransaction(ctx, &model.Transaction{
+			ID:            claim.TransactionID,
+			AccountID:     account.ID,
+			Type:          model.TransactionTypeInterest,
+			Amount:        math.Abs(amount),
+			BalanceBefore: account.Balance,
+			BalanceAfter:  account.Balance + amount,
+			Status:        model.TransactionStatusCompleted,
+			Description:   description,
+		})
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	marked, err := s.repository.MarkPosted(ctx, claim.TransactionID)
+	if err != nil || marked == 0 {
+		return err
+	}
+	return s.accountRepository.AddAccruedInterest(ctx, account.ID, -claim.Amount)
+}
+
+// recoverClaims settles claims a failed or interrupted post left behind:
+// finished if their journal entry was posted, handed back if it wasn't
+func (s *InterestService) recoverClaims(ctx context.Context) error {
+	claims, err := s.repository.FindClaims(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for i := range claims {
+		claim := &claims[i]
+		posted, err := s.ledger.Posted(ctx, model.JournalTypeInterest, []primitive.ObjectID{claim.TransactionID})
+		if err != nil {
+			return err
+		}
+		if !posted {
+			s.unclaim(ctx, claim.TransactionID)
+			continue
+		}
+
+		account, err := s.accountRepository.FindByID(ctx, claim.AccountID.Hex())
+		if err != nil {
+			return err
+		}
+		period := claim.LastDate
+		if day, err := time.Parse("2006-01-02", claim.LastDate); err == nil {
+			period = day.Format("January 2006")
+		}
+		amount := math.Round(claim.Amount*100) / 100
+		if err := s.finish(ctx, account, claim, interestDescription(amount, period)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *InterestService) unclaim(ctx context.Context, txID primitive.ObjectID) {
+	if err := s.repository.Unclaim(ctx, txID); err != nil {
+		log.Printf("[Interest] Failed to hand back accruals claimed by %s: %v", txID.Hex(), err)
+	}
+}
+
+func interestDescription(amount float64, period string) string {
+	if amount < 0 {
+		return "Margin interest, " + period
+	}
+	return "Interest earned, " + period
+}
+
+func startOfDayUTC(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,536 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrUnbalancedJournal = errors.New("journal entry debits and credits do not balance")
+)
+
+const (
+	// LedgerRecoveryInterval is how often the ledger finishes posts that
+	// were cut short before every balance took them
+	LedgerRecoveryInterval = time.Minute
+
+	// ledgerRecoveryAge is how long a post has to finish on its own
+	// before recovery takes it over
+	ledgerRecoveryAge = 5 * time.Minute
+
+	// ledgerRecoveryBatch caps how many unfinished entries one run applies
+	ledgerRecoveryBatch = 500
+)
+
+// LedgerService posts journal entries and keeps Account.Balance in step
+// with them. The balance is a cached view of the USER_CASH book; drift
+// between the two is reported by Reconcile.
+type LedgerService struct {
+	repository        *repository.LedgerRepository
+	accountRepository *repository.AccountRepository
+	mu                sync.Mutex // Serializes recovery runs
+}
+
+func NewLedgerService(repository *repository.LedgerRepository, accountRepository *repository.AccountRepository) *LedgerService {
+	return &LedgerService{
+		repository:        repository,
+		accountRepository: accountRepository,
+	}
+}
+
+// Post records a balanced journal entry, then applies its cash movement
+// to the balances of the accounts it touches. The entry is the record:
+// once it is written the post stands, and each balance takes it exactly
+// once, here or in Recover if the post is cut short.
+func (s *LedgerService) Post(ctx context.Context, entry *model.JournalEntry) error {
+	if !entry.Balanced() {
+		return ErrUnbalancedJournal
+	}
+	entry.Pending = cashMovers(entry)
+	if err := s.repository.Create(ctx, entry); err != nil {
+		return err
+	}
+	s.apply(ctx, entry)
+	return nil
+}
+
+// PostFunded posts an entry only if every account it takes cash from can
+// cover it. The debits are taken first, guarded by the balance and keyed
+// by the entry's ID, and given back if the entry isn't recorded, so
+// concurrent spending can't overdraw an account.
+func (s *LedgerService) PostFunded(ctx context.Context, entry *model.JournalEntry) error {
+	if !entry.Balanced() {
+		return ErrUnbalancedJournal
+	}
+	entry.ID = primitive.NewObjectID()
+	entry.Pending = cashMovers(entry)
+
+	var debited []primitive.ObjectID
+	refund := func() {
+		for _, accountID := range debited {
+			_ = s.accountRepository.UndoEntry(ctx, accountID, entry.ID, entry.CashDelta(accountID))
+		}
+	}
+
+	for _, accountID := range entry.Pending {
+		delta := entry.CashDelta(accountID)
+		if delta >= 0 {
+			continue
+		}
+		ok, err := s.accountRepository.DebitIfCovered(ctx, accountID, entry.ID, -delta)
+		if err != nil || !ok {
+			refund()
+			if err != nil {
+				return err
+			}
+			return ErrInsufficientBalance
+		}
+		debited = append(debited, accountID)
+	}
+
+	if err := s.repository.Create(ctx, entry); err != nil {
+		refund()
+		return err
+	}
+	s.apply(ctx, entry)
+	return nil
+}
+
+// apply moves each balance the entry is pending on, then clears it from
+// the entry. Balances remember the entry, so one already moved isn't
+// moved again; whatever fails is left for Recover.
+func (s *LedgerService) apply(ctx context.Context, entry *model.JournalEntry) {
+	for _, accountID := range entry.Pending {
+		err := s.accountRepository.ApplyEntry(ctx, accountID, entry.ID, entry.CashDelta(accountID))
+		if err == nil {
+			err = s.repository.ClearPending(ctx, entry.ID, accountID)
+		}
+		if err != nil {
+			log.Printf("⚠️ Journal entry %s not applied to %s, left for recovery: %v", entry.ID.Hex(), accountID.Hex(), err)
+		}
+	}
+}
+
+// cashMovers returns the accounts whose balance an entry moves
+func cashMovers(entry *model.JournalEntry) []primitive.ObjectID {
+	var ids []primitive.ObjectID
+	for _, accountID := range entry.CashAccounts() {
+		if entry.CashDelta(accountID) != 0 {
+			ids = append(ids, accountID)
+		}
+	}
+	return ids
+}
+
+// Start finishes interrupted posts at startup and then every interval
+func (s *LedgerService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	now := time.Now()
+	for {
+		if err := s.Recover(ctx, now); err != nil {
+			log.Printf("[Ledger] Failed to recover: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+	}
+}
+
+// Recover applies entries some balance never took, then settles the
+// marks balances keep: dropped once the entry records it was applied,
+// and given back when the entry was never recorded. Posts younger than
+// ledgerRecoveryAge may still be running and are left alone.
+func (s *LedgerService) Recover(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := now.Add(-ledgerRecoveryAge)
+	entries, err := s.repository.FindPending(ctx, before, ledgerRecoveryBatch)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		s.apply(ctx, &entries[i])
+	}
+
+	accounts, err := s.accountRepository.FindWithAppliedEntries(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range accounts {
+		account := &accounts[i]
+		for _, applied := range account.AppliedEntries {
+			if applied.EntryID.Timestamp().After(before) {
+				continue
+			}
+			entry, err := s.repository.FindByID(ctx, applied.EntryID)
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments):
+				err = s.accountRepository.UndoEntry(ctx, account.ID, applied.EntryID, applied.Delta)
+			case err == nil && !slices.Contains(entry.Pending, account.ID):
+				err = s.accountRepository.ForgetEntry(ctx, account.ID, applied.EntryID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CashBalanceAt returns an account's cash as the ledger had it at a time
+func (s *LedgerService) CashBalanceAt(ctx context.Context, accountID primitive.ObjectID, at time.Time) (float64, error) {
+	return s.repository.BalanceAt(ctx, model.LedgerUserCash, accountID, at)
+}
+
+// Posted reports whether an entry of a type was posted for any of the
+// referenced records
+func (s *LedgerService) Posted(ctx context.Context, journalType model.JournalType, referenceIDs []primitive.ObjectID) (bool, error) {
+	return s.repository.ExistsForReference(ctx, journalType, referenceIDs)
+}
+
+// GetJournal returns the journal entries touching a user's account
+func (s *LedgerService) GetJournal(ctx context.Context, accountID, userID string, limit, offset int) ([]dto.JournalEntryResponse, error) {
+	account, err := s.accountRepository.FindByID(ctx, accountID)
+	if err != nil || account.UserID.Hex() != userID {
+		return nil, ErrAccountNotFound
+	}
+
+	if limit <= 0 {
+		limit = 20
+	} else if limit > 100 {
+		limit = 100
+	}
+
+	entries, err := s.repository.FindByAccountID(ctx, account.ID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.JournalEntryResponse, 0, len(entries))
+	for i := range entries {
+		responses = append(responses, *s.toJournalEntryResponse(&entries[i], account.ID))
+	}
+	return responses, nil
+}
+
+// Reconcile compares a user's account balance with its ledger
+func (s *LedgerService) Reconcile(ctx context.Context, accountID, userID string) (*dto.ReconciliationResponse, error) {
+	account, err := s.accountRepository.FindByID(ctx, accountID)
+	if err != nil || account.UserID.Hex() != userID {
+		return nil, ErrAccountNotFound
+	}
+	return s.reconcile(ctx, account, time.Now())
+}
+
+// ReconcileAll checks every account and reports those that drifted
+func (s *LedgerService) ReconcileAll(ctx context.Context) (*dto.ReconciliationReport, error) {
+	accounts, err := s.accountRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &dto.ReconciliationReport{
+		AccountsChecked: len(accounts),
+		Drifted:         []dto.ReconciliationResponse{},
+		CheckedAt:       now.Format(time.RFC3339),
+	}
+	for i := range accounts {
+		result, err := s.reconcile(ctx, &accounts[i], now)
+		if err != nil {
+			return nil, err
+		}
+		if result.Drifted {
+			report.Drifted = append(report.Drifted, *result)
+		}
+	}
+	return report, nil
+}
+
+func (s *LedgerService) reconcile(ctx context.Context, account *model.Account, now time.Time) (*dto.ReconciliationResponse, error) {
+	ledgerBalance, err := s.repository.Balance(ctx, model.LedgerUserCash, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := account.Balance - ledgerBalance
+	return &dto.ReconciliationResponse{
+		AccountID:     account.ID.Hex(),
+		Currency:      account.Currency,
+		Balance:       account.Balance,
+		LedgerBalance: ledgerBalance,
+		Drift:         drift,
+		Drifted:       math.Abs(drift) > model.LedgerTolerance,
+		CheckedAt:     now.Format(time.RFC3339),
+	}, nil
+}
+
+// OpeningJournal funds a new account with its starting balance
+func OpeningJournal(accountID primitive.ObjectID, currency string, amount float64) *model.JournalEntry {
+	return journal(model.JournalTypeOpening, "Opening balance",
+		debit(model.LedgerClearing, nil, currency, amount),
+		credit(model.LedgerUserCash, &accountID, currency, amount),
+	)
+}
+
+// DepositJournal moves cash from outside the system into an account
+func DepositJournal(accountID primitive.ObjectID, currency string, amount float64, description string) *model.JournalEntry {
+	return journal(model.JournalTypeDeposit, description,
+		debit(model.LedgerClearing, nil, currency, amount),
+		credit(model.LedgerUserCash, &accountID, currency, amount),
+	)
+}
+
+// WithdrawJournal moves cash out of an account and out of the system
+func WithdrawJournal(accountID primitive.ObjectID, currency string, amount float64, description string) *model.JournalEntry {
+	return journal(model.JournalTypeWithdraw, description,
+		debit(model.LedgerUserCash, &accountID, currency, amount),
+		credit(model.LedgerClearing, nil, currency, amount),
+	)
+}
+
+// WithdrawalHoldJournal sets a requested withdrawal's cash aside, out of
+// the account's balance, until it pays out or is released
+func WithdrawalHoldJournal(accountID primitive.ObjectID, currency string, amount float64, description string) *model.JournalEntry {
+	return journal(model.JournalTypeHold, description,
+		debit(model.LedgerUserCash, &accountID, currency, amount),
+		credit(model.LedgerPendingWithdrawal, &accountID, currency, amount),
+	)
+}
+
+// WithdrawalReleaseJournal returns held cash to the account when a
+// withdrawal is cancelled, rejected or fails
+func WithdrawalReleaseJournal(accountID primitive.ObjectID, currency string, amount float64, description string) *model.JournalEntry {
+	return journal(model.JournalTypeRelease, description,
+		debit(model.LedgerPendingWithdrawal, &accountID, currency, amount),
+		credit(model.LedgerUserCash, &accountID, currency, amount),
+	)
+}
+
+// WithdrawalPayoutJournal pays held cash out of the system
+func WithdrawalPayoutJournal(accountID primitive.ObjectID, currency string, amount float64, description string) *model.JournalEntry {
+	return journal(model.JournalTypeWithdraw, description,
+		debit(model.LedgerPendingWithdrawal, &accountID, currency, amount),
+		credit(model.LedgerClearing, nil, currency, amount),
+	)
+}
+
+// AdjustmentJournal moves an account's cash by delta against clearing
+func AdjustmentJournal(accountID primitive.ObjectID, currency string, delta float64, description string) *model.JournalEntry {
+	if delta < 0 {
+		return journal(model.JournalTypeAdjustment, description,
+			debit(model.LedgerUserCash, &accountID, currency, -delta),
+			credit(model.LedgerClearing, nil, currency, -delta),
+		)
+	}
+	return journal(model.JournalTypeAdjustment, description,
+		debit(model.LedgerClearing, nil, currency, delta),
+		credit(model.LedgerUserCash, &accountID, currency, delta),
+	)
+}
+
+// InterestJournal posts interest to an account: paid from the interest
+// book when amount is positive, charged to it when negative
+func InterestJournal(accountID primitive.ObjectID, currency string, amount float64, description string) *model.JournalEntry {
+	if amount < 0 {
+		return journal(model.JournalTypeInterest, description,
+			debit(model.LedgerUserCash, &accountID, currency, -amount),
+			credit(model.LedgerInterest, nil, currency, -amount),
+		)
+	}
+	return journal(model.JournalTypeInterest, description,
+		debit(model.LedgerInterest, nil, currency, amount),
+		credit(model.LedgerUserCash, &accountID, currency, amount),
+	)
+}
+
+// TransferJournal moves cash between two accounts. Across currencies the
+// FX conversion book buys amount of the source currency and pays out
+// converted of the target currency, keeping each currency balanced.
+func TransferJournal(fromID primitive.ObjectID, fromCurrency string, amount float64, toID primitive.ObjectID, toCurrency string, converted float64, description string) *model.JournalEntry {
+	if fromCurrency == toCurrency {
+		return journal(model.JournalTypeTransfer, description,
+			debit(model.LedgerUserCash, &fromID, fromCurrency, amount),
+			credit(model.LedgerUserCash, &toID, toCurrency, converted),
+		)
+	}
+	return journal(model.JournalTypeTransfer, description,
+		debit(model.LedgerUserCash, &fromID, fromCurrency, amount),
+		credit(model.LedgerFXConversion, nil, fromCurrency, amount),
+		debit(model.LedgerFXConversion, nil, toCurrency, converted),
+		credit(model.LedgerUserCash, &toID, toCurrency, converted),
+	)
+}
+
+// TradeJournal books a fill: a buy pays total plus commission out of
+// cash, a sell receives total less commission. The trade value waits in
+// pending settlement and the commission goes to fee revenue.
+func TradeJournal(accountID primitive.ObjectID, buy bool, total, commission float64, currency, description string) *model.JournalEntry {
+	if buy {
+		return journal(model.JournalTypeTrade, description,
+			debit(model.LedgerUserCash, &accountID, currency, total+commission),
+			credit(model.LedgerPendingSettlement, &accountID, currency, total),
+			credit(model.LedgerFeeRevenue, nil, currency, commission),
+		)
+	}
+	return journal(model.JournalTypeTrade, description,
+		debit(model.LedgerPendingSettlement, &accountID, currency, total),
+		credit(model.LedgerUserCash, &accountID, currency, total-commission),
+		credit(model.LedgerFeeRevenue, nil, currency, commission),
+	)
+}
+
+// FXTradeJournal books a fill priced in another currency than the
+// account's. total and commission are in the instrument currency and
+// cash is what they came to in the account currency; the FX conversion
+// book sits between the two, keeping the spread.
+func FXTradeJournal(accountID primitive.ObjectID, buy bool, total, commission float64, instrumentCurrency string, cash float64, accountCurrency, description string) *model.JournalEntry {
+	if buy {
+		return journal(model.JournalTypeTrade, description,
+			debit(model.LedgerUserCash, &accountID, accountCurrency, cash),
+			credit(model.LedgerFXConversion, nil, accountCurrency, cash),
+			debit(model.LedgerFXConversion, nil, instrumentCurrency, total+commission),
+			credit(model.LedgerPendingSettlement, &accountID, instrumentCurrency, total),
+			credit(model.LedgerFeeRevenue, nil, instrumentCurrency, commission),
+		)
+	}
+	return journal(model.JournalTypeTrade, description,
+		debit(model.LedgerPendingSettlement, &accountID, instrumentCurrency, total),
+		credit(model.LedgerFeeRevenue, nil, instrumentCurrency, commission),
+		credit(model.LedgerFXConversion, nil, instrumentCurrency, total-commission),
+		debit(model.LedgerFXConversion, nil, accountCurrency, cash),
+		credit(model.LedgerUserCash, &accountID, accountCurrency, cash),
+	)
+}
+
+// FeeJournal charges a fill's fees to an account: commission to fee
+// revenue and regulatory fees to the book that pays them on
+func FeeJournal(accountID primitive.ObjectID, currency string, commission, regulatory float64, description string) *model.JournalEntry {
+	return journal(model.JournalTypeFee, description,
+		debit(model.LedgerUserCash, &accountID, currency, commission+regulatory),
+		credit(model.LedgerFeeRevenue, nil, currency, commission),
+		credit(model.LedgerRegulatoryFees, nil, currency, regulatory),
+	)
+}
+
+// SettlementJournal clears a fill's trade value out of pending
+// settlement: paid to the counterparty for a buy, received for a sell
+func SettlementJournal(accountID primitive.ObjectID, buy bool, total float64, currency, description string) *model.JournalEntry {
+	if buy {
+		return journal(model.JournalTypeSettlement, description,
+			debit(model.LedgerPendingSettlement, &accountID, currency, total),
+			credit(model.LedgerClearing, nil, currency, total),
+		)
+	}
+	return journal(model.JournalTypeSettlement, description,
+		debit(model.LedgerClearing, nil, currency, total),
+		credit(model.LedgerPendingSettlement, &accountID, currency, total),
+	)
+}
+
+// ReversalJournal undoes a posted entry by swapping its debits and credits
+func ReversalJournal(entry *model.JournalEntry) *model.JournalEntry {
+	reversal := &model.JournalEntry{
+		Type:          model.JournalTypeAdjustment,
+		ReferenceType: entry.ReferenceType,
+		ReferenceID:   entry.ReferenceID,
+		Description:   "Reversal: " + entry.Description,
+	}
+	for _, line := range entry.Lines {
+		line.Debit, line.Credit = line.Credit, line.Debit
+		reversal.Lines = append(reversal.Lines, line)
+	}
+	return reversal
+}
+
+// FillJournals books a fill and its settlement for an account. total and
+// commission are in the instrument currency; rate converts them into the
+// account currency, spread included. It returns the entries and the
+// fill's cash movement in the account currency.
+func FillJournals(accountID primitive.ObjectID, buy bool, total, commission float64, instrumentCurrency, accountCurrency string, rate float64, description string) ([]*model.JournalEntry, float64) {
+	net := total - commission
+	if buy {
+		net = -(total + commission)
+	}
+
+	var trade *model.JournalEntry
+	if instrumentCurrency == "" || instrumentCurrency == accountCurrency {
+		instrumentCurrency = accountCurrency
+		trade = TradeJournal(accountID, buy, total, commission, accountCurrency, description)
+	} else {
+		net = math.Round(net*rate*100) / 100
+		trade = FXTradeJournal(accountID, buy, total, commission, instrumentCurrency, math.Abs(net), accountCurrency, description)
+	}
+
+	return []*model.JournalEntry{
+		trade,
+		SettlementJournal(accountID, buy, total, instrumentCurrency, description),
+	}, net
+}
+
+// journal builds an entry, leaving out zero lines such as a free trade's
+// commission
+func journal(journalType model.JournalType, description string, lines ...model.JournalLine) *model.JournalEntry {
+	entry := &model.JournalEntry{Type: journalType, Description: description}
+	for _, line := range lines {
+		if line.Debit != 0 || line.Credit != 0 {
+			entry.Lines = append(entry.Lines, line)
+		}
+	}
+	return entry
+}
+
+func debit(ledger model.LedgerAccount, accountID *primitive.ObjectID, currency string, amount float64) model.JournalLine {
+	return model.JournalLine{Ledger: ledger, AccountID: accountID, Currency: currency, Debit: amount}
+}
+
+func credit(ledger model.LedgerAccount, accountID *primitive.ObjectID, currency string, amount float64) model.JournalLine {
+	return model.JournalLine{Ledger: ledger, AccountID: accountID, Currency: currency, Credit: amount}
+}
+
+// Helper: Convert JournalEntry to JournalEntryResponse
+func (s *LedgerService) toJournalEntryResponse(entry *model.JournalEntry, accountID primitive.ObjectID) *dto.JournalEntryResponse {
+	lines := make([]dto.JournalLineResponse, len(entry.Lines))
+	for i, line := range entry.Lines {
+		lines[i] = dto.JournalLineResponse{
+			Ledger:   string(line.Ledger),
+			Currency: line.Currency,
+			Debit:    line.Debit,
+			Credit:   line.Credit,
+		}
+		if line.AccountID != nil {
+			lines[i].AccountID = line.AccountID.Hex()
+		}
+	}
+
+	response := &dto.JournalEntryResponse{
+		ID:            entry.ID.Hex(),
+		Type:          string(entry.Type),
+		Lines:         lines,
+		CashDelta:     entry.CashDelta(accountID),
+		ReferenceType: entry.ReferenceType,
+		Description:   entry.Description,
+		CreatedAt:     entry.CreatedAt.Format(time.RFC3339),
+	}
+	if entry.ReferenceID != nil {
+		response.ReferenceID = entry.ReferenceID.Hex()
+	}
+	return response
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	orderModel "github.com/bricksocoolxd/bengi-investment-system/module/order/model"
+	orderRepo "github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
+	portfolioRepo "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrAccountStatus       = errors.New("account cannot change from its current status")
+	ErrFrozenByAdmin       = errors.New("account was frozen by an administrator")
+	ErrOpenPositions       = errors.New("account still has open positions")
+	ErrPendingTransactions = errors.New("account has deposits or withdrawals in progress")
+	ErrBalanceNotZero      = errors.New("account balance must be zero to close, or swept to another account")
+	ErrSweepAccount        = errors.New("sweep account must be another active account of the same owner and type")
+)
+
+// LifecycleService freezes, unfreezes, closes and reopens accounts. Every
+// change is recorded with its reason and who made it. Frozen and closed
+// accounts can't move money or trade (see CheckActive), so both cancel
+// the account's working orders.
+//
+// Owners can freeze their own accounts, lift freezes they made, and close
+// them; administrators can do all of that to any account, and reopen
+// closed ones.
+type LifecycleService struct {
+	repository    *repository.AccountRepository
+	accounts      *AccountService
+	interest      *InterestService
+	orderRepo     *orderRepo.OrderRepository
+	portfolioRepo *portfolioRepo.PortfolioRepository
+	engine        *matcher.Engine
+}
+
+func NewLifecycleService(repository *repository.AccountRepository, accounts *AccountService, interest *InterestService) *LifecycleService {
+	return &LifecycleService{
+		repository:    repository,
+		accounts:      accounts,
+		interest:      interest,
+		orderRepo:     orderRepo.NewOrderRepository(),
+		portfolioRepo: portfolioRepo.NewPortfolioRepository(),
+		engine:        matcher.Default(),
+	}
+}
+
+// Freeze suspends the owner's account
+func (s *LifecycleService) Freeze(ctx context.Context, accountID, userID string, req *dto.StatusChangeRequest) (*dto.StatusChangeResponse, error) {
+	return s.freeze(ctx, accountID, userID, model.AccountActorUser, req)
+}
+
+// AdminFreeze suspends any account. An owner's own freeze can be taken
+// over, so that only an administrator can lift it.
+func (s *LifecycleService) AdminFreeze(ctx context.Context, accountID, adminID string, req *dto.StatusChangeRequest) (*dto.StatusChangeResponse, error) {
+	return s.freeze(ctx, accountID, adminID, model.AccountActorAdmin, req)
+}
+
+// Unfreeze lifts a freeze the owner made
+func (s *LifecycleService) Unfreeze(ctx context.Context, accountID, userID string, req *dto.StatusChangeRequest) (*dto.StatusChangeResponse, error) {
+	return s.unfreeze(ctx, accountID, userID, model.AccountActorUser, req)
+}
+
+// AdminUnfreeze lifts any freeze
+func (s *LifecycleService) AdminUnfreeze(ctx context.Context, accountID, adminID string, req *dto.StatusChangeRequest) (*dto.StatusChangeResponse, error) {
+	return s.unfreeze(ctx, accountID, adminID, model.AccountActorAdmin, req)
+}
+
+// Close closes the owner's account
+func (s *LifecycleService) Close(ctx context.Context, accountID, userID string, req *dto.CloseAccountRequest) (*dto.StatusChangeResponse, error) {
+	return s.close(ctx, accountID, userID, model.AccountActorUser, req)
+}
+
+// AdminClose closes any account
+func (s *LifecycleService) AdminClose(ctx context.Context, accountID, adminID string, req *dto.CloseAccountRequest) (*dto.StatusChangeResponse, error) {
+	return s.close(ctx, accountID, adminID, model.AccountActorAdmin, req)
+}
+
+// AdminReopen makes a closed account active again, or one left closing
+// by a close that couldn't finish
+func (s *LifecycleService) AdminReopen(ctx context.Context, accountID, adminID string, req *dto.StatusChangeRequest) (*dto.StatusChangeResponse, error) {
+	account, err := s.account(ctx, accountID, adminID, model.AccountActorAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != model.AccountStatusClosed && account.Status != model.AccountStatusClosing {
+		return nil, ErrAccountStatus
+	}
+
+	change := s.newChange(account, model.AccountStatusActive, adminID, model.AccountActorAdmin, req.Reason)
+	if err := s.apply(ctx, change); err != nil {
+		return nil, err
+	}
+	if err := s.repository.CreateStatusChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return toStatusChangeResponse(change), nil
+}
+
+// GetStatusHistory lists the owner's account status changes, newest first
+func (s *LifecycleService) GetStatusHistory(ctx context.Context, accountID, userID string) ([]dto.StatusChangeResponse, error) {
+	return s.history(ctx, accountID, userID, model.AccountActorUser)
+}
+
+// AdminGetStatusHistory lists any account's status changes, newest first
+func (s *LifecycleService) AdminGetStatusHistory(ctx context.Context, accountID string) ([]dto.StatusChangeResponse, error) {
+	return s.history(ctx, accountID, "", model.AccountActorAdmin)
+}
+
+func (s *LifecycleService) freeze(ctx context.Context, accountID, actorID string, actor model.AccountActor, req *dto.StatusChangeRequest) (*dto.StatusChangeResponse, error) {
+	account, err := s.account(ctx, accountID, actorID, actor)
+	if err != nil {
+		return nil, err
+	}
+	escalating := account.Status == model.AccountStatusFrozen &&
+		account.StatusChangedBy == model.AccountActorUser && actor == model.AccountActorAdmin
+	if account.Status != model.AccountStatusActive && !escalating {
+		return nil, ErrAccountStatus
+	}
+
+	change := s.newChange(account, model.AccountStatusFrozen, actorID, actor, req.Reason)
+	if err := s.apply(ctx, change); err != nil {
+		return nil, err
+	}
+
+	// Orders already working must not fill while frozen
+	if change.CancelledOrders, err = s.cancelOrders(ctx, account); err != nil {
+		return nil, err
+	}
+	if err := s.repository.CreateStatusChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return toStatusChangeResponse(change), nil
+}
+
+func (s *LifecycleService) unfreeze(ctx context.Context, accountID, actorID string, actor model.AccountActor, req *dto.StatusChangeRequest) (*dto.StatusChangeResponse, error) {
+	account, err := s.account(ctx, accountID, actorID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != model.AccountStatusFrozen {
+		return nil, ErrAccountStatus
+	}
+	if actor == model.AccountActorUser && account.StatusChangedBy == model.AccountActorAdmin {
+		return nil, ErrFrozenByAdmin
+	}
+
+	change := s.newChange(account, model.AccountStatusActive, actorID, actor, req.Reason)
+	if err := s.apply(ctx, change); err != nil {
+		return nil, err
+	}
+	if err := s.repository.CreateStatusChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return toStatusChangeResponse(change), nil
+}
+
+// close moves the account to CLOSING, so nothing else can move its money
+// or trade, then checks it can close, cancels its working orders, posts
+// its interest and sweeps its cash if asked to. It is CLOSED only once
+// all of that succeeded; otherwise it goes back to the status it had,
+// keeping any interest posted, which was its own either way. Positions
+// must already be flat.
+func (s *LifecycleService) close(ctx context.Context, accountID, actorID string, actor model.AccountActor, req *dto.CloseAccountRequest) (*dto.StatusChangeResponse, error) {
+	account, err := s.account(ctx, accountID, actorID, actor)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case account.Status == model.AccountStatusClosed || account.Status == model.AccountStatusClosing:
+		return nil, ErrAccountStatus
+	case account.Status == model.AccountStatusFrozen && actor == model.AccountActorUser &&
+		account.StatusChangedBy == model.AccountActorAdmin:
+		return nil, ErrFrozenByAdmin
+	}
+
+	change := s.newChange(account, model.AccountStatusClosed, actorID, actor, req.Reason)
+	closing := s.newChange(account, model.AccountStatusClosing, actorID, actor, req.Reason)
+	if err := s.apply(ctx, closing); err != nil {
+		return nil, err
+	}
+	account.Status = model.AccountStatusClosing
+
+	if err := s.prepareClose(ctx, account, change, req); err != nil {
+		if _, revertErr := s.repository.TransitionStatus(ctx, account.ID,
+			[]model.AccountStatus{model.AccountStatusClosing}, change.From, account.StatusReason, account.StatusChangedBy); revertErr != nil {
+			log.Printf("⚠️ Account %s left closing after a failed close: %v", account.ID.Hex(), revertErr)
+		}
+		return nil, err
+	}
+
+	closed := *change
+	closed.From = model.AccountStatusClosing
+	if err := s.apply(ctx, &closed); err != nil {
+		return nil, err
+	}
+	if err := s.repository.CreateStatusChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return toStatusChangeResponse(change), nil
+}
+
+// prepareClose does the work of closing an account already marked
+// CLOSING, recording what it did on change
+func (s *LifecycleService) prepareClose(ctx context.Context, account *model.Account, change *model.AccountStatusChange, req *dto.CloseAccountRequest) error {
+	pending, err := s.repository.HasPendingTransactions(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return ErrPendingTransactions
+	}
+	if err := s.checkFlat(ctx, account); err != nil {
+		return err
+	}
+	if account.UnsettledCash > 0 {
+		return ErrUnsettledFunds
+	}
+
+	var sweepTo *model.Account
+	if req.SweepToAccountID != "" {
+		sweepTo, err = s.repository.FindByID(ctx, req.SweepToAccountID)
+		if err != nil || sweepTo.UserID != account.UserID {
+			return ErrAccountNotFound
+		}
+		if sweepTo.ID == account.ID || CheckActive(sweepTo) != nil || CheckSameType(account, sweepTo) != nil {
+			return ErrSweepAccount
+		}
+	} else if !isZero(account.Balance + account.AccruedInterest) {
+		return ErrBalanceNotZero
+	}
+
+	if change.CancelledOrders, err = s.cancelOrders(ctx, account); err != nil {
+		return err
+	}
+
+	// Interest accrued this month posts now rather than after the account
+	// has closed
+	if posted, err := s.interest.PostAccount(ctx, account, time.Now()); err != nil {
+		return err
+	} else if posted {
+		if account, err = s.repository.FindByID(ctx, account.ID.Hex()); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case account.Balance < 0 || (sweepTo == nil && !isZero(account.Balance)):
+		return ErrBalanceNotZero
+	case sweepTo != nil && !isZero(account.Balance):
+		transfer, err := s.accounts.transfer(ctx, account, sweepTo, account.Balance, "Closing sweep")
+		if err != nil {
+			return err
+		}
+		sweepTxID, _ := primitive.ObjectIDFromHex(transfer.From.ID)
+		change.SweptAmount = transfer.Amount
+		change.SweepTo = &sweepTo.ID
+		change.SweepTxID = &sweepTxID
+	}
+	return nil
+}
+
+// checkFlat refuses to close an account whose portfolios hold positions
+func (s *LifecycleService) checkFlat(ctx context.Context, account *model.Account) error {
+	portfolios, err := s.portfolioRepo.FindPortfoliosByAccountID(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	if len(portfolios) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, len(portfolios))
+	for i := range portfolios {
+		ids[i] = portfolios[i].ID
+	}
+	open, err := s.portfolioRepo.CountOpenPositions(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return ErrOpenPositions
+	}
+	return nil
+}
+
+// cancelOrders pulls the account's working orders from the book and
+// cancels them, returning how many it cancelled
+func (s *LifecycleService) cancelOrders(ctx context.Context, account *model.Account) (int, error) {
+	orders, err := s.orderRepo.FindWorkingByAccountID(ctx, account.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range orders {
+		order := &orders[i]
+		s.engine.CancelOrder(order.Symbol, order.ID.Hex())
+		if err := s.orderRepo.UpdateStatus(ctx, order.ID, orderModel.OrderStatusCancelled); err != nil {
+			return i, err
+		}
+		if _, err := ReleaseOrderCash(ctx, s.orderRepo, s.repository, order, -1); err != nil {
+			return i, err
+		}
+		ws.PublishOrderUpdate(order.UserID.Hex(), &ws.OrderPayload{
+			OrderID:   order.ID.Hex(),
+			Symbol:    order.Symbol,
+			Side:      string(order.Side),
+			Status:    string(orderModel.OrderStatusCancelled),
+			FilledQty: order.FilledQty,
+			AvgPrice:  order.AvgFillPrice,
+		})
+	}
+	return len(orders), nil
+}
+
+// apply moves the account to the change's status, provided nothing else
+// changed it since it was loaded
+func (s *LifecycleService) apply(ctx context.Context, change *model.AccountStatusChange) error {
+	ok, err := s.repository.TransitionStatus(ctx, change.AccountID, []model.AccountStatus{change.From}, change.To, change.Reason, change.Actor)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAccountStatus
+	}
+	return nil
+}
+
+func (s *LifecycleService) newChange(account *model.Account, to model.AccountStatus, actorID string, actor model.AccountActor, reason string) *model.AccountStatusChange {
+	changedBy, _ := primitive.ObjectIDFromHex(actorID)
+	return &model.AccountStatusChange{
+		AccountID: account.ID,
+		From:      account.Status,
+		To:        to,
+		Reason:    reason,
+		Actor:     actor,
+		ChangedBy: changedBy,
+	}
+}
+
+func (s *LifecycleService) history(ctx context.Context, accountID, actorID string, actor model.AccountActor) ([]dto.StatusChangeResponse, error) {
+	account, err := s.account(ctx, accountID, actorID, actor)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.repository.FindStatusChanges(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.StatusChangeResponse, 0, len(changes))
+	for i := range changes {
+		responses = append(responses, *toStatusChangeResponse(&changes[i]))
+	}
+	return responses, nil
+}
+
+// account loads an account; owners may only act on their own
+func (s *LifecycleService) account(ctx context.Context, accountID, actorID string, actor model.AccountActor) (*model.Account, error) {
+	account, err := s.repository.FindByID(ctx, accountID)
+	if err != nil || account == nil {
+		return nil, ErrAccountNotFound
+	}
+	if actor == model.AccountActorUser && account.UserID.Hex() != actorID {
+		return nil, ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// isZero reports whether an amount rounds to zero cents
+func isZero(amount float64) bool {
+	return math.Abs(amount) < 0.005
+}
+
+// Helper: Convert AccountStatusChange to StatusChangeResponse
+func toStatusChangeResponse(change *model.AccountStatusChange) *dto.StatusChangeResponse {
+	response := &dto.StatusChangeResponse{
+		ID:              change.ID.Hex(),
+		AccountID:       change.AccountID.Hex(),
+		From:            string(change.From),
+		To:              string(change.To),
+		Reason:          change.Reason,
+		Actor:           string(change.Actor),
+		ChangedBy:       change.ChangedBy.Hex(),
+		CancelledOrders: change.CancelledOrders,
+		SweptAmount:     change.SweptAmount,
+		CreatedAt:       change.CreatedAt,
+	}
+	if change.SweepTo != nil {
+		response.SweepTo = change.SweepTo.Hex()
+	}
+	if change.SweepTxID != nil {
+		response.SweepTxID = change.SweepTxID.Hex()
+	}
+	return response
+}
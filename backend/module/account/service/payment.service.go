@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/payment"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxReviewQueue caps how many withdrawals the review queue returns
+const maxReviewQueue = 200
+
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrTransactionState    = errors.New("transaction cannot change from its current status")
+)
+
+// WithdrawalLimits are the amounts, in the account's currency, above
+// which a withdrawal waits for admin review. A zero limit is not applied.
+type WithdrawalLimits struct {
+	Single float64 // One withdrawal
+	Daily  float64 // All of an account's withdrawals in a UTC day
+}
+
+// NeedsReview reports whether a withdrawal of amount must be approved,
+// given what the account has already withdrawn today
+func (l WithdrawalLimits) NeedsReview(amount, withdrawnToday float64) bool {
+	if l.Single > 0 && amount > l.Single {
+		return true
+	}
+	return l.Daily > 0 && withdrawnToday+amount > l.Daily
+}
+
+// withdrawalsCounted are the statuses that use up the daily limit
+var withdrawalsCounted = []model.TransactionStatus{
+	model.TransactionStatusPending,
+	model.TransactionStatusPendingReview,
+	model.TransactionStatusCompleted,
+}
+
+// PaymentService runs live deposits and withdrawals through the payment
+// provider. Deposits credit the balance only once the provider confirms
+// them. Withdrawals hold their cash straight away, wait for review above
+// the limits, and pay out or release the hold when they end.
+type PaymentService struct {
+	repository *repository.AccountRepository
+	ledger     *LedgerService
+	provider   payment.Provider
+	limits     WithdrawalLimits
+}
+
+func NewPaymentService(repository *repository.AccountRepository, ledger *LedgerService, provider payment.Provider, limits WithdrawalLimits) *PaymentService {
+	return &PaymentService{
+		repository: repository,
+		ledger:     ledger,
+		provider:   provider,
+		limits:     limits,
+	}
+}
+
+// RequestDeposit asks the provider to collect a deposit and records it
+// PENDING until the provider's webhook confirms it
+func (s *PaymentService) RequestDeposit(ctx context.Context, account *model.Account, req *dto.DepositRequest) (*dto.TransactionResponse, error) {
+	tx := &model.Transaction{
+		ID:          primitive.NewObjectID(),
+		AccountID:   account.ID,
+		Type:        model.TransactionTypeDeposit,
+		Amount:      req.Amount,
+		Status:      model.TransactionStatusPending,
+		Description: req.Description,
+	}
+	if tx.Description == "" {
+		tx.Description = "Deposit"
+	}
+
+	// Recorded before the provider hears of it, so its webhook always
+	// finds the transaction
+	if err := s.repository.CreateTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	reference, err := s.provider.CreateDeposit(ctx, &payment.Request{
+		TransactionID: tx.ID,
+		AccountID:     account.ID,
+		Currency:      account.Currency,
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		s.end(ctx, tx, account.Currency, model.TransactionStatusFailed, bson.M{"failureReason": err.Error()})
+		return nil, err
+	}
+	s.recordReference(ctx, tx, reference)
+	return s.reload(ctx, tx)
+}
+
+// RequestWithdrawal holds the withdrawal's cash and either sends the
+// payout or, above the limits, queues it for review
+func (s *PaymentService) RequestWithdrawal(ctx context.Context, account *model.Account, req *dto.WithdrawRequest) (*dto.TransactionResponse, error) {
+	if req.Amount > account.Balance {
+		return nil, ErrInsufficientBalance
+	}
+	if req.Amount > account.SettledCash() {
+		return nil, ErrUnsettledFunds
+	}
+	if req.Amount > account.FreeCash() {
+		return nil, ErrReservedFunds
+	}
+
+	startOfDay := time.Now().UTC().Truncate(24 * time.Hour)
+	withdrawnToday, err := s.repository.SumTransactionsSince(ctx, account.ID, model.TransactionTypeWithdraw, withdrawalsCounted, startOfDay)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &model.Transaction{
+		ID:            primitive.NewObjectID(),
+		AccountID:     account.ID,
+		Type:          model.TransactionTypeWithdraw,
+		Amount:        req.Amount,
+		BalanceBefore: account.Balance,
+		BalanceAfter:  account.Balance - req.Amount,
+		Status:        model.TransactionStatusPending,
+		Description:   req.Description,
+	}
+	if tx.Description == "" {
+		tx.Description = "Withdraw"
+	}
+	if s.limits.NeedsReview(req.Amount, withdrawnToday) {
+		tx.Status = model.TransactionStatusPendingReview
+	}
+
+	hold := WithdrawalHoldJournal(account.ID, account.Currency, req.Amount, tx.Description)
+	hold.ReferenceType = "TRANSACTION"
+	hold.ReferenceID = &tx.ID
+	if err := s.ledger.PostFunded(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateTransaction(ctx, tx); err != nil {
+		s.release(ctx, tx, account.Currency)
+		return nil, err
+	}
+
+	if tx.Status == model.TransactionStatusPending {
+		s.submitPayout(ctx, tx, account.Currency)
+	}
+	return toTransactionResponse(tx), nil
+}
+
+// submitPayout sends a PENDING withdrawal to the provider. If the
+// provider refuses it the withdrawal fails and its hold is released.
+func (s *PaymentService) submitPayout(ctx context.Context, tx *model.Transaction, currency string) {
+	reference, err := s.provider.CreatePayout(ctx, &payment.Request{
+		TransactionID: tx.ID,
+		AccountID:     tx.AccountID,
+		Currency:      currency,
+		Amount:        tx.Amount,
+	})
+	if err != nil {
+		s.end(ctx, tx, currency, model.TransactionStatusFailed, bson.M{"failureReason": err.Error()})
+		return
+	}
+
+	s.recordReference(ctx, tx, reference)
+}
+
+// recordReference stores the provider's reference for a transaction. Its
+// webhook may have beaten us to it, matched by the client reference, in
+// which case the transaction already has it.
+func (s *PaymentService) recordReference(ctx context.Context, tx *model.Transaction, reference string) {
+	if err := s.repository.SetProviderRef(ctx, tx.ID, reference); err != nil {
+		log.Printf("⚠️ Payment %s sent as %s but not recorded: %v", tx.ID.Hex(), reference, err)
+	}
+	tx.ProviderRef = reference
+}
+
+// findEventTransaction returns the transaction an event is about: by the
+// provider's reference, else by the client reference for events that
+// arrive before the provider's reference was recorded
+func (s *PaymentService) findEventTransaction(ctx context.Context, event *payment.Event) (*model.Transaction, error) {
+	if tx, err := s.repository.FindTransactionByProviderRef(ctx, event.Reference); err == nil {
+		return tx, nil
+	}
+	if event.ClientReference == "" {
+		return nil, payment.ErrUnknownReference
+	}
+
+	tx, err := s.repository.FindTransactionByID(ctx, event.ClientReference)
+	if err != nil || (tx.ProviderRef != "" && tx.ProviderRef != event.Reference) {
+		return nil, payment.ErrUnknownReference
+	}
+	if tx.ProviderRef == "" {
+		s.recordReference(ctx, tx, event.Reference)
+	}
+	return tx, nil
+}
+
+// HandleEvent applies a provider's webhook event to its transaction.
+// Events for transactions that already ended are ignored, so providers
+// can safely redeliver.
+func (s *PaymentService) HandleEvent(ctx context.Context, event *payment.Event) (*dto.TransactionResponse, error) {
+	tx, err := s.findEventTransaction(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	if tx.IsTerminal() {
+		return toTransactionResponse(tx), nil
+	}
+
+	account, err := s.repository.FindByID(ctx, tx.AccountID.Hex())
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+
+	switch {
+	case event.Outcome == payment.OutcomeFailed:
+		s.end(ctx, tx, account.Currency, model.TransactionStatusFailed, bson.M{"failureReason": event.Reason})
+
+	case tx.Type == model.TransactionTypeDeposit:
+		set := bson.M{
+			"balanceBefore": account.Balance,
+			"balanceAfter":  account.Balance + tx.Amount,
+		}
+		if s.complete(ctx, tx, set) {
+			entry := DepositJournal(account.ID, account.Currency, tx.Amount, tx.Description)
+			entry.ReferenceType = "TRANSACTION"
+			entry.ReferenceID = &tx.ID
+			if err := s.ledger.Post(ctx, entry); err != nil {
+				return nil, err
+			}
+		}
+
+	case tx.Type == model.TransactionTypeWithdraw:
+		if s.complete(ctx, tx, bson.M{}) {
+			entry := WithdrawalPayoutJournal(account.ID, account.Currency, tx.Amount, tx.Description)
+			entry.ReferenceType = "TRANSACTION"
+			entry.ReferenceID = &tx.ID
+			if err := s.ledger.Post(ctx, entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.reload(ctx, tx)
+}
+
+// GetReviewQueue returns the withdrawals waiting for admin review
+func (s *PaymentService) GetReviewQueue(ctx context.Context) ([]dto.TransactionResponse, error) {
+	transactions, err := s.repository.FindTransactionsByStatus(ctx, model.TransactionTypeWithdraw, model.TransactionStatusPendingReview, maxReviewQueue)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TransactionResponse, 0, len(transactions))
+	for i := range transactions {
+		responses = append(responses, *toTransactionResponse(&transactions[i]))
+	}
+	return responses, nil
+}
+
+// ApproveWithdrawal releases a reviewed withdrawal to the provider
+func (s *PaymentService) ApproveWithdrawal(ctx context.Context, txID, adminID string, req *dto.ReviewRequest) (*dto.TransactionResponse, error) {
+	tx, account, err := s.findReviewable(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckActive(account); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repository.TransitionTransaction(ctx, tx.ID,
+		[]model.TransactionStatus{model.TransactionStatusPendingReview},
+		bson.M{"status": model.TransactionStatusPending, "reviewedBy": objectIDPtr(adminID), "reviewNote": req.Note},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTransactionState
+	}
+
+	tx.Status = model.TransactionStatusPending
+	s.submitPayout(ctx, tx, account.Currency)
+	return s.reload(ctx, tx)
+}
+
+// RejectWithdrawal refuses a reviewed withdrawal and releases its hold
+func (s *PaymentService) RejectWithdrawal(ctx context.Context, txID, adminID string, req *dto.ReviewRequest) (*dto.TransactionResponse, error) {
+	tx, account, err := s.findReviewable(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.end(ctx, tx, account.Currency, model.TransactionStatusRejected, bson.M{"reviewedBy": objectIDPtr(adminID), "reviewNote": req.Note}) {
+		return nil, ErrTransactionState
+	}
+	return s.reload(ctx, tx)
+}
+
+// CancelWithdrawal lets a user call off their withdrawal until it has
+// paid out, releasing its hold
+func (s *PaymentService) CancelWithdrawal(ctx context.Context, accountID, txID, userID string) (*dto.TransactionResponse, error) {
+	account, err := s.repository.FindByID(ctx, accountID)
+	if err != nil || account.UserID.Hex() != userID {
+		return nil, ErrAccountNotFound
+	}
+
+	tx, err := s.repository.FindTransactionByID(ctx, txID)
+	if err != nil || tx.AccountID != account.ID || tx.Type != model.TransactionTypeWithdraw {
+		return nil, ErrTransactionNotFound
+	}
+	if tx.IsTerminal() {
+		return nil, ErrTransactionState
+	}
+
+	if tx.ProviderRef != "" {
+		if err := s.provider.CancelPayout(ctx, tx.ProviderRef); err != nil {
+			return nil, ErrTransactionState
+		}
+	}
+
+	if !s.end(ctx, tx, account.Currency, model.TransactionStatusCancelled, bson.M{}) {
+		return nil, ErrTransactionState
+	}
+	return s.reload(ctx, tx)
+}
+
+func (s *PaymentService) findReviewable(ctx context.Context, txID string) (*model.Transaction, *model.Account, error) {
+	tx, err := s.repository.FindTransactionByID(ctx, txID)
+	if err != nil || tx.Type != model.TransactionTypeWithdraw {
+		return nil, nil, ErrTransactionNotFound
+	}
+	if tx.Status != model.TransactionStatusPendingReview {
+		return nil, nil, ErrTransactionState
+	}
+
+	account, err := s.repository.FindByID(ctx, tx.AccountID.Hex())
+	if err != nil {
+		return nil, nil, ErrAccountNotFound
+	}
+	return tx, account, nil
+}
+
+// complete marks a PENDING transaction COMPLETED. It reports whether this
+// call made the change, so the caller alone posts its ledger entry.
+func (s *PaymentService) complete(ctx context.Context, tx *model.Transaction, set bson.M) bool {
+	set["status"] = model.TransactionStatusCompleted
+	set["completedAt"] = time.Now()
+	ok, err := s.repository.TransitionTransaction(ctx, tx.ID, []model.TransactionStatus{model.TransactionStatusPending}, set)
+	if err != nil {
+		log.Printf("⚠️ Failed to complete transaction %s: %v", tx.ID.Hex(), err)
+	}
+	return ok
+}
+
+// end moves an open transaction to a final status without paying it out.
+// A withdrawal's held cash goes back to the account. It reports whether
+// this call made the change.
+func (s *PaymentService) end(ctx context.Context, tx *model.Transaction, currency string, status model.TransactionStatus, set bson.M) bool {
+	set["status"] = status
+	ok, err := s.repository.TransitionTransaction(ctx, tx.ID,
+		[]model.TransactionStatus{model.TransactionStatusPending, model.TransactionStatusPendingReview}, set)
+	if err != nil {
+		log.Printf("⚠️ Failed to move transaction %s to %s: %v", tx.ID.Hex(), status, err)
+	}
+	if ok && tx.Type == model.TransactionTypeWithdraw {
+		s.release(ctx, tx, currency)
+	}
+	return ok
+}
+
+// release returns a withdrawal's held cash to its account
+func (s *PaymentService) release(ctx context.Context, tx *model.Transaction, currency string) {
+	entry := WithdrawalReleaseJournal(tx.AccountID, currency, tx.Amount, tx.Description)
+	entry.ReferenceType = "TRANSACTION"
+	entry.ReferenceID = &tx.ID
+	if err := s.ledger.Post(ctx, entry); err != nil {
+		log.Printf("⚠️ Failed to release hold for %s: %v", tx.ID.Hex(), err)
+	}
+}
+
+func (s *PaymentService) reload(ctx context.Context, tx *model.Transaction) (*dto.TransactionResponse, error) {
+	updated, err := s.repository.FindTransactionByID(ctx, tx.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	return toTransactionResponse(updated), nil
+}
+
+func objectIDPtr(hex string) *primitive.ObjectID {
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
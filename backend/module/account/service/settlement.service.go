@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	orderModel "github.com/bricksocoolxd/bengi-investment-system/module/order/model"
+	orderRepo "github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// SettlementInterval is how often the settlement job looks for due fills
+	SettlementInterval = time.Minute
+
+	// settlementBatch caps how many due settlements one run clears
+	settlementBatch = 500
+)
+
+// Fill is an execution to book against an account. Amounts are in the
+// instrument currency; Rate converts them into the account currency.
+type Fill struct {
+	Buy            bool
+	Symbol         string
+	Total          float64
+	Commission     float64
+	RegulatoryFees float64
+	Currency       string
+	Rate           float64
+	SettleDate     time.Time
+	ReferenceType  string
+	ReferenceID    *primitive.ObjectID
+	Description    string
+}
+
+// SettlementService books fills and clears them on their settlement
+// date. Cash moves when a fill is booked, but sale proceeds stay
+// unsettled until then: they can buy (subject to good-faith rules) but
+// not leave the account.
+type SettlementService struct {
+	repository        *repository.SettlementRepository
+	accountRepository *repository.AccountRepository
+	ledger            *LedgerService
+	mu                sync.Mutex // Serializes settlement runs
+}
+
+func NewSettlementService(repository *repository.SettlementRepository, accountRepository *repository.AccountRepository, ledger *LedgerService) *SettlementService {
+	return &SettlementService{
+		repository:        repository,
+		accountRepository: accountRepository,
+		ledger:            ledger,
+	}
+}
+
+// PostFill books a fill for account, as it stood before the fill, and
+// schedules its settlement. Fills due to settle by now settle at once.
+// The trade and its fees are charged separately, each recorded as a
+// transaction. It returns the fill's cash movement in the account
+// currency, fees included.
+func (s *SettlementService) PostFill(ctx context.Context, account *model.Account, fill *Fill) (float64, error) {
+	now := time.Now()
+	entries, cash := FillJournals(account.ID, fill.Buy, fill.Total, 0, fill.Currency, account.Currency, fill.Rate, fill.Description)
+	for _, entry := range entries {
+		entry.ReferenceType = fill.ReferenceType
+		entry.ReferenceID = fill.ReferenceID
+	}
+	trade, settlement := entries[0], entries[1]
+
+	// Fees are charged in the account currency at the fill's rate
+	rate := 1.0
+	if fill.Currency != "" && fill.Currency != account.Currency {
+		rate = fill.Rate
+	}
+	commission := roundCents(fill.Commission * rate)
+	regulatory := roundCents(fill.RegulatoryFees * rate)
+	fees := commission + regulatory
+
+	// A buy is paid only from cash the account has free, so it can't
+	// overdraw it; if its fees can't be paid, the trade leg is reversed
+	post := s.ledger.Post
+	if fill.Buy {
+		post = s.ledger.PostFunded
+	}
+	if err := post(ctx, trade); err != nil {
+		return 0, err
+	}
+	if fees > 0 {
+		entry := FeeJournal(account.ID, account.Currency, commission, regulatory, "Fees: "+fill.Description)
+		entry.ReferenceType = fill.ReferenceType
+		entry.ReferenceID = fill.ReferenceID
+		if err := post(ctx, entry); err != nil {
+			if fill.Buy {
+				if reverseErr := s.ledger.Post(ctx, ReversalJournal(trade)); reverseErr != nil {
+					return cash, reverseErr
+				}
+				return 0, err
+			}
+			return cash, err
+		}
+	}
+
+	if err := s.recordTransactions(ctx, account, fill, cash, fees); err != nil {
+		return cash - fees, err
+	}
+
+	currency := fill.Currency
+	if currency == "" {
+		currency = account.Currency
+	}
+	record := &model.Settlement{
+		AccountID:     account.ID,
+		ReferenceType: fill.ReferenceType,
+		ReferenceID:   fill.ReferenceID,
+		Symbol:        fill.Symbol,
+		Buy:           fill.Buy,
+		Total:         fill.Total,
+		Currency:      currency,
+		Cash:          cash,
+		TradeDate:     now,
+		SettleDate:    fill.SettleDate,
+		Status:        model.SettlementStatusPending,
+	}
+
+	// Only cash accounts answer to good-faith rules
+	if account.IsCashAccount() {
+		if fill.Buy {
+			s.noteUnsettledFunding(ctx, account, record, fees-cash)
+		} else {
+			s.checkGoodFaith(ctx, account, fill.Symbol, now)
+		}
+	}
+
+	if !fill.SettleDate.After(now) {
+		if err := s.ledger.Post(ctx, settlement); err != nil {
+			return cash - fees, err
+		}
+		record.Status = model.SettlementStatusSettled
+		record.SettledAt = &now
+	} else if !fill.Buy {
+		if err := s.accountRepository.AddUnsettledCash(ctx, account.ID, cash); err != nil {
+			return cash - fees, err
+		}
+	}
+
+	return cash - fees, s.repository.Create(ctx, record)
+}
+
+// FillPosted reports whether a fill with this reference was already
+// booked, so a fill retried after a crash isn't charged twice
+func (s *SettlementService) FillPosted(ctx context.Context, referenceID primitive.ObjectID) (bool, error) {
+	return s.ledger.Posted(ctx, model.JournalTypeTrade, []primitive.ObjectID{referenceID})
+}
+
+// ReserveOrderCash holds amount of an account's free cash for a resting
+// buy, so the engine can fill it later without overdrawing the account
+func ReserveOrderCash(ctx context.Context, accounts *repository.AccountRepository, account *model.Account, amount float64) error {
+	ok, err := accounts.ReserveCash(ctx, account.ID, amount, account.IsRestricted(time.Now()))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+// ReleaseOrderCash gives the account back up to amount of the cash an
+// order holds, or all of it when amount is negative, and returns how much
+// it released. order is updated in place.
+func ReleaseOrderCash(ctx context.Context, orders *orderRepo.OrderRepository, accounts *repository.AccountRepository, order *orderModel.Order, amount float64) (float64, error) {
+	released, err := orders.ReleaseReservedCash(ctx, order.ID, amount)
+	if err != nil || released == 0 {
+		return 0, err
+	}
+	order.ReservedCash = math.Max(0, order.ReservedCash-released)
+	return released, accounts.AddReservedCash(ctx, order.AccountID, -released)
+}
+
+// recordTransactions adds a fill's TRADE and FEE rows to the account's
+// transaction history
+func (s *SettlementService) recordTransactions(ctx context.Context, account *model.Account, fill *Fill, cash, fees float64) error {
+	if err := s.accountRepository.CreateTransaction(ctx, &model.Transaction{
+		AccountID:     account.ID,
+		Type:          model.TransactionTypeTrade,
+		Amount:        math.Abs(cash),
+		BalanceBefore: account.Balance,
+		BalanceAfter:  account.Balance + cash,
+		ReferenceType: fill.ReferenceType,
+		ReferenceID:   fill.ReferenceID,
+		Status:        model.TransactionStatusCompleted,
+		Description:   fill.Description,
+	}); err != nil {
+		return err
+	}
+	if fees <= 0 {
+		return nil
+	}
+	return s.accountRepository.CreateTransaction(ctx, &model.Transaction{
+		AccountID:     account.ID,
+		Type:          model.TransactionTypeFee,
+		Amount:        fees,
+		BalanceBefore: account.Balance + cash,
+		BalanceAfter:  account.Balance + cash - fees,
+		ReferenceType: fill.ReferenceType,
+		ReferenceID:   fill.ReferenceID,
+		Status:        model.TransactionStatusCompleted,
+		Description:   "Fees: " + fill.Description,
+	})
+}
+
+// noteUnsettledFunding records how much of a buy's cost came out of
+// unsettled proceeds, and when the last of them settles
+func (s *SettlementService) noteUnsettledFunding(ctx context.Context, account *model.Account, record *model.Settlement, cost float64) {
+	unsettled := cost - account.SettledCash()
+	if unsettled <= model.LedgerTolerance {
+		return
+	}
+
+	pending, err := s.repository.FindPendingByAccountID(ctx, account.ID)
+	if err != nil {
+		log.Printf("⚠️ Failed to load pending settlements for %s: %v", account.ID.Hex(), err)
+		return
+	}
+	var settleAt *time.Time
+	for i := range pending {
+		if !pending[i].Buy && (settleAt == nil || pending[i].SettleDate.After(*settleAt)) {
+			settleAt = &pending[i].SettleDate
+		}
+	}
+	if settleAt != nil {
+		record.UnsettledFunded = unsettled
+		record.FundsSettleAt = settleAt
+	}
+}
+
+// checkGoodFaith records a good-faith violation when a sale comes before
+// the cash that paid for the position has settled. Reaching the limit
+// restricts the account to settled cash.
+func (s *SettlementService) checkGoodFaith(ctx context.Context, account *model.Account, symbol string, now time.Time) {
+	buys, err := s.repository.FindUnpaidBuys(ctx, account.ID, symbol, now)
+	if err != nil || len(buys) == 0 {
+		return
+	}
+	for _, buy := range buys {
+		if err := s.repository.ClearFunding(ctx, buy.ID); err != nil {
+			log.Printf("⚠️ Failed to clear funding on settlement %s: %v", buy.ID.Hex(), err)
+		}
+	}
+
+	var restrictedUntil *time.Time
+	if account.RecentViolations(now)+1 >= model.GoodFaithViolationLimit {
+		until := now.Add(model.GoodFaithRestrictionTime)
+		restrictedUntil = &until
+	}
+	if err := s.accountRepository.RecordGoodFaithViolation(ctx, account.ID, now, restrictedUntil); err != nil {
+		log.Printf("⚠️ Failed to record good-faith violation for %s: %v", account.ID.Hex(), err)
+		return
+	}
+	log.Printf("⚠️ Good-faith violation on account %s selling %s", account.ID.Hex(), symbol)
+}
+
+// Start clears due settlements every interval until ctx is cancelled
+func (s *SettlementService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.SettleDue(ctx, now); err != nil {
+				log.Printf("[Settlement] Failed to settle: %v", err)
+			}
+		}
+	}
+}
+
+// SettleDue clears every pending settlement due by now and returns how
+// many it cleared
+func (s *SettlementService) SettleDue(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due, err := s.repository.FindDue(ctx, now, settlementBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for i := range due {
+		record := &due[i]
+		ok, err := s.repository.MarkSettled(ctx, record.ID, now)
+		if err != nil {
+			return settled, err
+		}
+		if !ok {
+			continue
+		}
+
+		description := "Settle " + record.Symbol
+		entry := SettlementJournal(record.AccountID, record.Buy, record.Total, record.Currency, description)
+		entry.ReferenceType = record.ReferenceType
+		entry.ReferenceID = record.ReferenceID
+		if err := s.ledger.Post(ctx, entry); err != nil {
+			return settled, err
+		}
+		if !record.Buy {
+			if err := s.accountRepository.AddUnsettledCash(ctx, record.AccountID, -record.Cash); err != nil {
+				return settled, err
+			}
+		}
+		settled++
+	}
+	return settled, nil
+}
+
+// GetPendingSettlements returns an account's fills still waiting to settle
+func (s *SettlementService) GetPendingSettlements(ctx context.Context, accountID, userID string) ([]dto.SettlementResponse, error) {
+	account, err := s.accountRepository.FindByID(ctx, accountID)
+	if err != nil || account.UserID.Hex() != userID {
+		return nil, ErrAccountNotFound
+	}
+
+	settlements, err := s.repository.FindPendingByAccountID(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SettlementResponse, 0, len(settlements))
+	for i := range settlements {
+		responses = append(responses, *s.toSettlementResponse(&settlements[i]))
+	}
+	return responses, nil
+}
+
+// Helper: Convert Settlement to SettlementResponse
+func (s *SettlementService) toSettlementResponse(settlement *model.Settlement) *dto.SettlementResponse {
+	response := &dto.SettlementResponse{
+		ID:         settlement.ID.Hex(),
+		Symbol:     settlement.Symbol,
+		Side:       "SELL",
+		Total:      settlement.Total,
+		Currency:   settlement.Currency,
+		Cash:       settlement.Cash,
+		TradeDate:  settlement.TradeDate,
+		SettleDate: settlement.SettleDate,
+		Status:     string(settlement.Status),
+	}
+	if settlement.Buy {
+		response.Side = "BUY"
+	}
+	if settlement.ReferenceID != nil {
+		response.ReferenceType = settlement.ReferenceType
+		response.ReferenceID = settlement.ReferenceID.Hex()
+	}
+	return response
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
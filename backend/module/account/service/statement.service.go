@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/statement"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	tradeRepo "github.com/bricksocoolxd/bengi-investment-system/module/trade/repository"
+)
+
+// StatementInterval is how often the statement job checks for months
+// without statements
+const StatementInterval = time.Hour
+
+var (
+	ErrStatementNotFound = errors.New("statement not found")
+	ErrStatementPeriod   = errors.New("statement period must start before it ends and before now")
+)
+
+// StatementService gathers an account's balances, transactions, trades
+// and positions for a period and keeps them rendered as CSV and PDF.
+// Each account gets a statement for every month once it ends.
+type StatementService struct {
+	repository        *repository.StatementRepository
+	accountRepository *repository.AccountRepository
+	ledgerRepository  *repository.LedgerRepository
+	tradeRepository   *tradeRepo.TradeRepository
+	printRepository   *tradeRepo.TradePrintRepository
+	rates             *fx.Cache
+	mu                sync.Mutex // Serializes monthly runs
+}
+
+func NewStatementService(
+	repository *repository.StatementRepository,
+	accountRepository *repository.AccountRepository,
+	ledgerRepository *repository.LedgerRepository,
+	tradeRepository *tradeRepo.TradeRepository,
+	printRepository *tradeRepo.TradePrintRepository,
+	rates *fx.Cache,
+) *StatementService {
+	return &StatementService{
+		repository:        repository,
+		accountRepository: accountRepository,
+		ledgerRepository:  ledgerRepository,
+		tradeRepository:   tradeRepository,
+		printRepository:   printRepository,
+		rates:             rates,
+	}
+}
+
+// Start runs the monthly job every interval until ctx is cancelled
+func (s *StatementService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			monthStart := time.Date(now.UTC().Year(), now.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+			if _, err := s.GenerateMonthly(ctx, monthStart.AddDate(0, -1, 0)); err != nil {
+				log.Printf("[Statements] Failed to generate: %v", err)
+			}
+		}
+	}
+}
+
+// GenerateMonthly generates the month starting at monthStart for every
+// account open before it ended that doesn't have it yet. Returns how many
+// statements it generated.
+func (s *StatementService) GenerateMonthly(ctx context.Context, monthStart time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts, err := s.accountRepository.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	start, end := monthStart, monthStart.AddDate(0, 1, 0)
+	generated := 0
+	for i := range accounts {
+		account := &accounts[i]
+		if !account.CreatedAt.Before(end) {
+			continue
+		}
+		if existing, err := s.repository.FindFinal(ctx, account.ID, start, end); err != nil || existing != nil {
+			continue
+		}
+		if _, err := s.Generate(ctx, account, start, end, true); err != nil {
+			log.Printf("[Statements] Account %s: %v", account.ID.Hex(), err)
+			continue
+		}
+		generated++
+	}
+	return generated, nil
+}
+
+// Generate builds, renders and stores an account's statement for [start,
+// end)
+func (s *StatementService) Generate(ctx context.Context, account *model.Account, start, end time.Time, monthly bool) (*model.Statement, error) {
+	built, err := s.Build(ctx, account, start, end)
+	if err != nil {
+		return nil, err
+	}
+	csv, err := statement.CSV(built)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &model.Statement{
+		AccountID:      account.ID,
+		UserID:         account.UserID,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Monthly:        monthly,
+		Currency:       account.Currency,
+		OpeningBalance: built.OpeningBalance,
+		ClosingBalance: built.ClosingBalance,
+		PositionsValue: roundCents(built.PositionsValue()),
+		Fees:           roundCents(built.Summary.Fees),
+		Dividends:      roundCents(built.Summary.Dividends),
+		CSV:            csv,
+		PDF:            statement.PDF(built),
+	}
+	if err := s.repository.Create(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Build gathers an account's statement for [start, end). Balances come
+// from the ledger; positions are replayed from the account's trades and
+// valued at the last print before the period ended.
+func (s *StatementService) Build(ctx context.Context, account *model.Account, start, end time.Time) (*statement.Statement, error) {
+	opening, err := s.ledgerRepository.BalanceAt(ctx, model.LedgerUserCash, account.ID, start)
+	if err != nil {
+		return nil, err
+	}
+	closing, err := s.ledgerRepository.BalanceAt(ctx, model.LedgerUserCash, account.ID, end)
+	if err != nil {
+		return nil, err
+	}
+
+	built := &statement.Statement{
+		AccountID:      account.ID.Hex(),
+		AccountType:    string(account.Type),
+		Currency:       account.Currency,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		GeneratedAt:    time.Now(),
+		OpeningBalance: roundCents(opening),
+		ClosingBalance: roundCents(closing),
+	}
+
+	transactions, err := s.accountRepository.FindTransactionsBetween(ctx, account.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range transactions {
+		line := statement.Transaction{
+			Date:        tx.CreatedAt,
+			Type:        string(tx.Type),
+			Status:      string(tx.Status),
+			Description: tx.Description,
+			Amount:      tx.Amount,
+			Balance:     tx.BalanceAfter,
+		}
+		if tx.Status == model.TransactionStatusCompleted {
+			line.Change = tx.BalanceAfter - tx.BalanceBefore
+		}
+		built.Transactions = append(built.Transactions, line)
+	}
+	built.Summary = statement.Summarize(built.Transactions)
+
+	trades, err := s.tradeRepository.FindByAccountIDBefore(ctx, account.ID, end)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]statement.Trade, 0, len(trades))
+	lastPrice := map[string]float64{}
+	for _, trade := range trades {
+		line := statement.Trade{
+			Date:       trade.ExecutedAt,
+			Symbol:     trade.Symbol,
+			Side:       string(trade.Side),
+			Quantity:   trade.Quantity,
+			Price:      trade.Price,
+			Total:      trade.Total,
+			Commission: trade.Commission,
+			Currency:   trade.Currency,
+		}
+		if line.Currency == "" {
+			line.Currency = account.Currency
+		}
+		if trade.Fees != nil {
+			line.RegulatoryFees = trade.Fees.RegulatoryTotal()
+		}
+		history = append(history, line)
+		lastPrice[trade.Symbol] = trade.Price
+		if !trade.ExecutedAt.Before(start) {
+			built.Trades = append(built.Trades, line)
+		}
+	}
+
+	built.Positions = statement.BuildPositions(history)
+	for i := range built.Positions {
+		position := &built.Positions[i]
+		price := lastPrice[position.Symbol]
+		if tradePrint, err := s.printRepository.LastBefore(ctx, position.Symbol, end); err == nil && tradePrint != nil {
+			price = tradePrint.Price
+		}
+		position.MarkAt(price, s.rate(position.Currency, account.Currency))
+	}
+	return built, nil
+}
+
+// rate converts a position's value into the account currency at the mid
+// rate. Positions without a rate are left unvalued.
+func (s *StatementService) rate(from, to string) float64 {
+	if from == to {
+		return 1
+	}
+	rate, err := s.rates.Rate(from, to)
+	if err != nil {
+		log.Printf("[Statements] No %s/%s rate: %v", from, to, err)
+		return 0
+	}
+	return rate
+}
+
+// RequestStatement generates a statement for a month or range of days.
+// A period that has ended and already has a statement returns that one.
+// Periods running past now end now.
+func (s *StatementService) RequestStatement(ctx context.Context, accountID, userID string, req *dto.StatementRequest) (*dto.StatementResponse, error) {
+	account, err := s.account(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, monthly, err := statementPeriod(req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if end.After(now) {
+		end = now
+	}
+	if !start.Before(end) {
+		return nil, ErrStatementPeriod
+	}
+
+	existing, err := s.repository.FindFinal(ctx, account.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return toStatementResponse(existing), nil
+	}
+
+	record, err := s.Generate(ctx, account, start, end, monthly)
+	if err != nil {
+		return nil, err
+	}
+	return toStatementResponse(record), nil
+}
+
+// statementPeriod reads the request's period as [start, end) in UTC
+func statementPeriod(req *dto.StatementRequest) (time.Time, time.Time, bool, error) {
+	if req.Month != "" {
+		start, err := time.Parse("2006-01", req.Month)
+		if err != nil {
+			return time.Time{}, time.Time{}, false, ErrStatementPeriod
+		}
+		return start, start.AddDate(0, 1, 0), true, nil
+	}
+
+	start, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, ErrStatementPeriod
+	}
+	last, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, ErrStatementPeriod
+	}
+	return start, last.AddDate(0, 0, 1), false, nil
+}
+
+// GetStatements lists an account's statements, latest first
+func (s *StatementService) GetStatements(ctx context.Context, accountID, userID string) ([]dto.StatementResponse, error) {
+	account, err := s.account(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := s.repository.FindByAccountID(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.StatementResponse, 0, len(statements))
+	for i := range statements {
+		responses = append(responses, *toStatementResponse(&statements[i]))
+	}
+	return responses, nil
+}
+
+// Download returns a statement's content in a format and a file name for it
+func (s *StatementService) Download(ctx context.Context, accountID, statementID, userID string, format model.StatementFormat) ([]byte, string, error) {
+	account, err := s.account(ctx, accountID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	record, err := s.repository.FindByID(ctx, statementID)
+	if err != nil || record.AccountID != account.ID {
+		return nil, "", ErrStatementNotFound
+	}
+
+	name := fmt.Sprintf("statement-%s-%s-%s.%s", account.ID.Hex(),
+		record.PeriodStart.Format("20060102"), record.PeriodEnd.Add(-time.Nanosecond).Format("20060102"), format)
+	return record.Content(format), name, nil
+}
+
+// account loads an account the user owns. An empty userID is an admin,
+// who may see any account.
+func (s *StatementService) account(ctx context.Context, accountID, userID string) (*model.Account, error) {
+	account, err := s.accountRepository.FindByID(ctx, accountID)
+	if err != nil || account == nil {
+		return nil, ErrAccountNotFound
+	}
+	if userID != "" && account.UserID.Hex() != userID {
+		return nil, ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// Helper: Convert Statement to StatementResponse
+func toStatementResponse(record *model.Statement) *dto.StatementResponse {
+	base := fmt.Sprintf("/api/v1/accounts/%s/statements/%s/download?format=", record.AccountID.Hex(), record.ID.Hex())
+	return &dto.StatementResponse{
+		ID:             record.ID.Hex(),
+		AccountID:      record.AccountID.Hex(),
+		PeriodStart:    record.PeriodStart.Format("2006-01-02"),
+		PeriodEnd:      record.PeriodEnd.Add(-time.Nanosecond).Format("2006-01-02"),
+		Monthly:        record.Monthly,
+		Currency:       record.Currency,
+		OpeningBalance: record.OpeningBalance,
+		ClosingBalance: record.ClosingBalance,
+		PositionsValue: record.PositionsValue,
+		Fees:           record.Fees,
+		Dividends:      record.Dividends,
+		Downloads: map[string]string{
+			string(model.StatementFormatPDF): base + string(model.StatementFormatPDF),
+			string(model.StatementFormatCSV): base + string(model.StatementFormatCSV),
+		},
+		CreatedAt: record.CreatedAt,
+	}
+}
@@ -0,0 +1,270 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/pdf"
+)
+
+const dateFormat = "2006-01-02"
+
+// summaryRows are the headline figures, shared by both formats
+func summaryRows(s *Statement) [][2]string {
+	return [][2]string{
+		{"Opening balance", money(s.OpeningBalance)},
+		{"Deposits", money(s.Summary.Deposits)},
+		{"Withdrawals", money(-s.Summary.Withdrawals)},
+		{"Transfers in", money(s.Summary.TransfersIn)},
+		{"Transfers out", money(-s.Summary.TransfersOut)},
+		{"Trading", money(s.Summary.TradingNet)},
+		{"Fees", money(-s.Summary.Fees)},
+		{"Dividends", money(s.Summary.Dividends)},
+		{"Interest", money(s.Summary.Interest)},
+		{"Closing balance", money(s.ClosingBalance)},
+		{"Positions value", money(s.PositionsValue())},
+		{"Total value", money(s.ClosingBalance + s.PositionsValue())},
+	}
+}
+
+// CSV renders the statement as sections of comma-separated rows: the
+// header and summary, then transactions, trades and positions, each
+// after a blank row and a title row
+func CSV(s *Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{
+		{"Account statement", s.AccountID},
+		{"Account type", s.AccountType},
+		{"Currency", s.Currency},
+		{"Period start", s.PeriodStart.Format(dateFormat)},
+		{"Period end", s.LastDay().Format(dateFormat)},
+		{"Generated", s.GeneratedAt.UTC().Format(time.RFC3339)},
+	}
+	for _, row := range summaryRows(s) {
+		rows = append(rows, []string{row[0], row[1]})
+	}
+
+	rows = append(rows, nil, []string{"Transactions"},
+		[]string{"Date", "Type", "Status", "Description", "Amount", "Change", "Balance"})
+	for _, tx := range s.Transactions {
+		rows = append(rows, []string{
+			tx.Date.UTC().Format(time.RFC3339), tx.Type, tx.Status, tx.Description,
+			money(tx.Amount), money(tx.Change), money(tx.Balance),
+		})
+	}
+
+	rows = append(rows, nil, []string{"Trades"},
+		[]string{"Date", "Symbol", "Side", "Quantity", "Price", "Total", "Commission", "Regulatory fees", "Currency"})
+	for _, trade := range s.Trades {
+		rows = append(rows, []string{
+			trade.Date.UTC().Format(time.RFC3339), trade.Symbol, trade.Side,
+			quantity(trade.Quantity), price(trade.Price), money(trade.Total),
+			money(trade.Commission), money(trade.RegulatoryFees), trade.Currency,
+		})
+	}
+
+	rows = append(rows, nil, []string{"Positions at " + s.LastDay().Format(dateFormat)},
+		[]string{"Symbol", "Quantity", "Average cost", "Price", "Market value", "Unrealized P&L", "Currency", "Value (" + s.Currency + ")"})
+	for _, position := range s.Positions {
+		rows = append(rows, []string{
+			position.Symbol, quantity(position.Quantity), price(position.AvgCost), price(position.Price),
+			money(position.MarketValue), money(position.UnrealizedPnL), position.Currency, money(position.Value),
+		})
+	}
+
+	for _, row := range rows {
+		if row == nil {
+			row = []string{}
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// PDF layout, in points. Tables are set in Courier so columns line up.
+const (
+	margin    = 40.0
+	tableSize = 7.5
+	lineGap   = 11.0
+)
+
+// column is a fixed-width table column, in characters
+type column struct {
+	title string
+	width int
+	right bool
+}
+
+// page writes the PDF top to bottom, starting new pages as it fills them
+type page struct {
+	doc *pdf.Document
+	y   float64
+}
+
+func (p *page) newPage() {
+	p.doc.AddPage()
+	p.y = margin
+}
+
+// need starts a new page unless height fits on this one
+func (p *page) need(height float64) bool {
+	if p.y+height > pdf.A4Height-margin-lineGap {
+		p.newPage()
+		return true
+	}
+	return false
+}
+
+func (p *page) text(font pdf.Font, size float64, text string) {
+	p.doc.Text(margin, p.y, font, size, text)
+	p.y += size + 4
+}
+
+func (p *page) heading(text string) {
+	p.need(3 * lineGap)
+	p.y += lineGap / 2
+	p.text(pdf.HelveticaBold, 11, text)
+	p.doc.Line(margin, p.y-2, pdf.A4Width-margin, p.y-2, 0.5)
+	p.y += 4
+}
+
+// table writes rows under a header that repeats on each new page.
+// Columns without titles have no header.
+func (p *page) table(columns []column, rows [][]string) {
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.title
+	}
+	writeHeader := func() {
+		if columns[0].title != "" {
+			p.row(pdf.CourierBold, columns, header)
+		}
+	}
+
+	p.need(2 * lineGap)
+	writeHeader()
+	if len(rows) == 0 {
+		p.row(pdf.Courier, columns[:1], []string{"None"})
+		return
+	}
+	for _, row := range rows {
+		if p.need(lineGap) {
+			writeHeader()
+		}
+		p.row(pdf.Courier, columns, row)
+	}
+}
+
+func (p *page) row(font pdf.Font, columns []column, cells []string) {
+	var line strings.Builder
+	for i, c := range columns {
+		cell := []rune(cells[i])
+		if len(cell) > c.width {
+			cell = append(cell[:c.width-1], '~')
+		}
+		pad := strings.Repeat(" ", c.width-len(cell))
+		if c.right {
+			line.WriteString(pad + string(cell))
+		} else {
+			line.WriteString(string(cell) + pad)
+		}
+		line.WriteByte(' ')
+	}
+	p.doc.Text(margin, p.y, font, tableSize, strings.TrimRight(line.String(), " "))
+	p.y += lineGap
+}
+
+// PDF renders the statement as an A4 document
+func PDF(s *Statement) []byte {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.SetTitle("Account statement " + s.AccountID)
+	p := &page{doc: doc}
+	p.newPage()
+
+	p.text(pdf.HelveticaBold, 18, "Account Statement")
+	p.y += 4
+	p.text(pdf.Helvetica, 10, fmt.Sprintf("Account %s (%s, %s)", s.AccountID, s.AccountType, s.Currency))
+	p.text(pdf.Helvetica, 10, fmt.Sprintf("Period %s to %s", s.PeriodStart.Format(dateFormat), s.LastDay().Format(dateFormat)))
+	p.text(pdf.Helvetica, 8, "Generated "+s.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	p.heading("Summary (" + s.Currency + ")")
+	summary := summaryRows(s)
+	rows := make([][]string, len(summary))
+	for i, row := range summary {
+		rows[i] = []string{row[0], row[1]}
+	}
+	p.table([]column{{"", 24, false}, {"", 18, true}}, rows)
+
+	p.heading("Transactions")
+	rows = rows[:0]
+	for _, tx := range s.Transactions {
+		rows = append(rows, []string{
+			tx.Date.UTC().Format("2006-01-02 15:04"), tx.Type, tx.Status, tx.Description,
+			money(tx.Change), money(tx.Balance),
+		})
+	}
+	p.table([]column{
+		{"Date", 16, false}, {"Type", 9, false}, {"Status", 14, false},
+		{"Description", 35, false}, {"Change", 15, true}, {"Balance", 15, true},
+	}, rows)
+
+	p.heading("Trades")
+	rows = rows[:0]
+	for _, trade := range s.Trades {
+		rows = append(rows, []string{
+			trade.Date.UTC().Format("2006-01-02 15:04"), trade.Symbol, trade.Side,
+			quantity(trade.Quantity), price(trade.Price), money(trade.Total),
+			money(trade.Commission + trade.RegulatoryFees), trade.Currency,
+		})
+	}
+	p.table([]column{
+		{"Date", 16, false}, {"Symbol", 10, false}, {"Side", 4, false}, {"Quantity", 12, true},
+		{"Price", 12, true}, {"Total", 15, true}, {"Fees", 10, true}, {"Ccy", 3, false},
+	}, rows)
+
+	p.heading("Positions at " + s.LastDay().Format(dateFormat))
+	rows = rows[:0]
+	for _, position := range s.Positions {
+		rows = append(rows, []string{
+			position.Symbol, quantity(position.Quantity), price(position.AvgCost), price(position.Price),
+			money(position.MarketValue), money(position.UnrealizedPnL), position.Currency, money(position.Value),
+		})
+	}
+	p.table([]column{
+		{"Symbol", 10, false}, {"Quantity", 12, true}, {"Avg cost", 11, true}, {"Price", 11, true},
+		{"Mkt value", 14, true}, {"Unreal. P&L", 13, true}, {"Ccy", 3, false}, {"Value " + s.Currency, 14, true},
+	}, rows)
+
+	// Footers go on last, once the page count is known
+	for n := 1; n <= doc.Pages(); n++ {
+		doc.SetPage(n)
+		footer := fmt.Sprintf("Page %d of %d", n, doc.Pages())
+		doc.Text(pdf.A4Width-margin-pdf.CourierWidth(footer, 8), pdf.A4Height-margin/2, pdf.Courier, 8, footer)
+		doc.Text(margin, pdf.A4Height-margin/2, pdf.Courier, 8, s.AccountID)
+	}
+	return doc.Bytes()
+}
+
+func money(v float64) string {
+	if v > -0.005 && v < 0.005 {
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func price(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
+
+func quantity(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,175 @@
+// Package statement lays out an account statement for a period and
+// renders it as CSV or PDF. Gathering the figures is up to the caller.
+package statement
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Transaction types and the status the summary counts, as stored on
+// account transactions
+const (
+	typeDeposit  = "DEPOSIT"
+	typeWithdraw = "WITHDRAW"
+	typeTransfer = "TRANSFER"
+	typeTrade    = "TRADE"
+	typeFee      = "FEE"
+	typeDividend = "DIVIDEND"
+	typeInterest = "INTEREST"
+
+	statusCompleted = "COMPLETED"
+)
+
+// Transaction is a cash movement in the period. Change is its signed
+// effect on the balance; it is 0 unless the transaction completed.
+type Transaction struct {
+	Date        time.Time
+	Type        string
+	Status      string
+	Description string
+	Amount      float64
+	Change      float64
+	Balance     float64
+}
+
+// Trade is an execution in the period, in the instrument currency
+type Trade struct {
+	Date           time.Time
+	Symbol         string
+	Side           string // BUY or SELL
+	Quantity       float64
+	Price          float64
+	Total          float64
+	Commission     float64
+	RegulatoryFees float64
+	Currency       string
+}
+
+// Position is a holding at the end of the period. Price, MarketValue and
+// UnrealizedPnL are in the instrument currency, Value in the account's.
+type Position struct {
+	Symbol        string
+	Currency      string
+	Quantity      float64
+	AvgCost       float64
+	Price         float64
+	MarketValue   float64
+	UnrealizedPnL float64
+	Value         float64
+}
+
+// Summary totals the period's completed transactions by kind
+type Summary struct {
+	Deposits     float64
+	Withdrawals  float64
+	TransfersIn  float64
+	TransfersOut float64
+	TradingNet   float64 // Cash from trades: sale proceeds less purchases
+	Fees         float64
+	Dividends    float64
+	Interest     float64 // Negative when margin interest was charged
+}
+
+// Statement is everything shown for an account over [PeriodStart,
+// PeriodEnd)
+type Statement struct {
+	AccountID      string
+	AccountType    string
+	Currency       string
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	GeneratedAt    time.Time
+	OpeningBalance float64
+	ClosingBalance float64
+	Summary        Summary
+	Transactions   []Transaction
+	Trades         []Trade
+	Positions      []Position
+}
+
+// LastDay is the last day the period covers
+func (s *Statement) LastDay() time.Time {
+	return s.PeriodEnd.Add(-time.Nanosecond)
+}
+
+// PositionsValue totals the positions in the account currency
+func (s *Statement) PositionsValue() float64 {
+	total := 0.0
+	for _, position := range s.Positions {
+		total += position.Value
+	}
+	return total
+}
+
+// Summarize totals transactions by kind. Only completed transactions
+// count.
+func Summarize(transactions []Transaction) Summary {
+	var summary Summary
+	for _, tx := range transactions {
+		if tx.Status != statusCompleted {
+			continue
+		}
+		switch tx.Type {
+		case typeDeposit:
+			summary.Deposits += tx.Amount
+		case typeWithdraw:
+			summary.Withdrawals += tx.Amount
+		case typeTransfer:
+			if tx.Change >= 0 {
+				summary.TransfersIn += tx.Change
+			} else {
+				summary.TransfersOut -= tx.Change
+			}
+		case typeFee:
+			summary.Fees += tx.Amount
+		case typeDividend:
+			summary.Dividends += tx.Amount
+		case typeInterest:
+			summary.Interest += tx.Change
+		case typeTrade:
+			summary.TradingNet += tx.Change
+		}
+	}
+	return summary
+}
+
+// BuildPositions replays trades, oldest first, into the holdings they
+// leave. Buys average into the cost; sells reduce the quantity at the
+// average cost. Closed positions are dropped.
+func BuildPositions(trades []Trade) []Position {
+	bySymbol := map[string]*Position{}
+	for _, trade := range trades {
+		position, ok := bySymbol[trade.Symbol]
+		if !ok {
+			position = &Position{Symbol: trade.Symbol, Currency: trade.Currency}
+			bySymbol[trade.Symbol] = position
+		}
+		if trade.Side == "BUY" {
+			cost := position.AvgCost*position.Quantity + trade.Quantity*trade.Price
+			position.Quantity += trade.Quantity
+			position.AvgCost = cost / position.Quantity
+		} else {
+			position.Quantity = math.Max(0, position.Quantity-trade.Quantity)
+		}
+	}
+
+	positions := make([]Position, 0, len(bySymbol))
+	for _, position := range bySymbol {
+		if position.Quantity > 1e-9 {
+			positions = append(positions, *position)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Symbol < positions[j].Symbol })
+	return positions
+}
+
+// MarkAt prices a position at price, converting its market value into
+// the account currency at rate
+func (p *Position) MarkAt(price, rate float64) {
+	p.Price = price
+	p.MarketValue = p.Quantity * price
+	p.UnrealizedPnL = p.MarketValue - p.Quantity*p.AvgCost
+	p.Value = p.MarketValue * rate
+}
@@ -9,6 +9,10 @@ import (
 // UserCollection is the MongoDB collection name for users.
 const UserCollection = "users"
 
+// MarketMakerEmail identifies the system user the synthetic market maker
+// trades as. It is seeded with no usable password.
+const MarketMakerEmail = "market-maker@system.local"
+
 // User represents a registered user in the system.
 // Contains authentication info and user profile data.
 type User struct {
@@ -12,22 +12,47 @@ type (
 	}
 
 	UpdateInstrumentRequest struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		LogoURL     string `json:"logoUrl"`
-		Status      string `json:"status" validate:"omitempty,oneof=ACTIVE INACTIVE DELISTED"`
+		Name         string               `json:"name"`
+		Description  string               `json:"description"`
+		LogoURL      string               `json:"logoUrl"`
+		Status       string               `json:"status" validate:"omitempty,oneof=ACTIVE INACTIVE DELISTED"`
+		TradingRules *TradingRulesRequest `json:"tradingRules"`
+	}
+
+	// TradingRulesRequest overrides an instrument's trading rules.
+	// Omitted or zero fields fall back to the instrument type defaults.
+	TradingRulesRequest struct {
+		TickSize          float64 `json:"tickSize" validate:"gte=0"`
+		LotSize           float64 `json:"lotSize" validate:"gte=0"`
+		QuantityPrecision int     `json:"quantityPrecision" validate:"gte=0,lte=12"`
+		MinQuantity       float64 `json:"minQuantity" validate:"gte=0"`
+		MaxQuantity       float64 `json:"maxQuantity" validate:"gte=0"`
+		MinNotional       float64 `json:"minNotional" validate:"gte=0"`
+		PriceBandPct      float64 `json:"priceBandPct" validate:"gte=0,lte=100"`
+	}
+
+	// TradingRulesResponse is the effective set of rules for an instrument.
+	TradingRulesResponse struct {
+		TickSize          float64 `json:"tickSize"`
+		LotSize           float64 `json:"lotSize"`
+		QuantityPrecision int     `json:"quantityPrecision"`
+		MinQuantity       float64 `json:"minQuantity"`
+		MaxQuantity       float64 `json:"maxQuantity"`
+		MinNotional       float64 `json:"minNotional"`
+		PriceBandPct      float64 `json:"priceBandPct"`
 	}
 
 	InstrumentResponse struct {
-		ID          string `json:"id"`
-		Symbol      string `json:"symbol"`
-		Name        string `json:"name"`
-		Type        string `json:"type"`
-		Exchange    string `json:"exchange"`
-		Currency    string `json:"currency"`
-		Description string `json:"description"`
-		LogoURL     string `json:"logoUrl,omitempty"`
-		Status      string `json:"status"`
+		ID           string                `json:"id"`
+		Symbol       string                `json:"symbol"`
+		Name         string                `json:"name"`
+		Type         string                `json:"type"`
+		Exchange     string                `json:"exchange"`
+		Currency     string                `json:"currency"`
+		Description  string                `json:"description"`
+		LogoURL      string                `json:"logoUrl,omitempty"`
+		Status       string                `json:"status"`
+		TradingRules *TradingRulesResponse `json:"tradingRules,omitempty"`
 	}
 
 	InstrumentListResponse struct {
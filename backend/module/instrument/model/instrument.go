@@ -43,8 +43,10 @@ type Instrument struct {
 	Status      InstrumentStatus   `bson:"status" json:"status"`           // Active, Inactive, Delisted
 	Description string             `bson:"description" json:"description"` // Brief info about the instrument
 	LogoURL     string             `bson:"logoUrl,omitempty" json:"logoUrl,omitempty"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	// TradingRules overrides the per-type defaults; nil uses the defaults
+	TradingRules *TradingRules `bson:"tradingRules,omitempty" json:"tradingRules,omitempty"`
+	CreatedAt    time.Time     `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time     `bson:"updatedAt" json:"updatedAt"`
 }
 
 // Quote represents real-time price data for an instrument.
@@ -72,3 +74,23 @@ func (i *Instrument) IsStock() bool {
 func (i *Instrument) IsCrypto() bool {
 	return i.Type == InstrumentTypeCrypto
 }
+
+// settlementDays is the settlement cycle (T+n trading days) per type
+var settlementDays = map[InstrumentType]int{
+	InstrumentTypeStock:     1,
+	InstrumentTypeETF:       1,
+	InstrumentTypeOption:    1,
+	InstrumentTypeCrypto:    0,
+	InstrumentTypeFuture:    0,
+	InstrumentTypeForex:     2,
+	InstrumentTypeCommodity: 2,
+}
+
+// SettlementDays returns how many trading days after a trade it settles.
+// Unknown types settle like US equities.
+func (i *Instrument) SettlementDays() int {
+	if days, ok := settlementDays[i.Type]; ok {
+		return days
+	}
+	return 1
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// Trading rule violation codes returned to clients.
+const (
+	RuleCodeTickSize      = "PRICE_TICK_SIZE"      // Price is not a multiple of the tick size
+	RuleCodeLotSize       = "QUANTITY_LOT_SIZE"    // Quantity is not a multiple of the lot size
+	RuleCodePrecision     = "QUANTITY_PRECISION"   // Quantity has too many decimal places
+	RuleCodeMinQuantity   = "QUANTITY_BELOW_MIN"   // Quantity is below the minimum order size
+	RuleCodeMaxQuantity   = "QUANTITY_ABOVE_MAX"   // Quantity is above the maximum order size
+	RuleCodeMinNotional   = "NOTIONAL_BELOW_MIN"   // Price * quantity is below the minimum notional
+	RuleCodePriceBand     = "PRICE_OUTSIDE_BAND"   // Price is too far from the last traded price
+	RuleCodeStopPriceTick = "STOP_PRICE_TICK_SIZE" // Stop price is not a multiple of the tick size
+)
+
+// TradingRules are the order constraints for an instrument.
+// Zero values mean "no constraint", except QuantityPrecision where 0 means
+// whole units only.
+type TradingRules struct {
+	TickSize          float64 `bson:"tickSize,omitempty" json:"tickSize"`                   // Minimum price increment
+	LotSize           float64 `bson:"lotSize,omitempty" json:"lotSize"`                     // Minimum quantity increment
+	QuantityPrecision int     `bson:"quantityPrecision,omitempty" json:"quantityPrecision"` // Max decimal places in quantity
+	MinQuantity       float64 `bson:"minQuantity,omitempty" json:"minQuantity"`
+	MaxQuantity       float64 `bson:"maxQuantity,omitempty" json:"maxQuantity"`
+	MinNotional       float64 `bson:"minNotional,omitempty" json:"minNotional"`   // Minimum price * quantity
+	PriceBandPct      float64 `bson:"priceBandPct,omitempty" json:"priceBandPct"` // Max % distance from last price
+}
+
+// RuleViolation is returned when an order breaks a trading rule.
+type RuleViolation struct {
+	Code    string  `json:"code"`
+	Field   string  `json:"field"`
+	Message string  `json:"message"`
+	Limit   float64 `json:"limit"` // The rule value that was violated
+}
+
+func (v *RuleViolation) Error() string {
+	return v.Message
+}
+
+// defaultTradingRules are the per-type rules used when an instrument has none.
+var defaultTradingRules = map[InstrumentType]TradingRules{
+	InstrumentTypeStock:     {TickSize: 0.01, LotSize: 0.0001, QuantityPrecision: 4, MinQuantity: 0.0001, MaxQuantity: 1000000, MinNotional: 1, PriceBandPct: 10},
+	InstrumentTypeETF:       {TickSize: 0.01, LotSize: 0.0001, QuantityPrecision: 4, MinQuantity: 0.0001, MaxQuantity: 1000000, MinNotional: 1, PriceBandPct: 10},
+	InstrumentTypeCrypto:    {TickSize: 0.01, LotSize: 0.00000001, QuantityPrecision: 8, MinQuantity: 0.00000001, MaxQuantity: 10000, MinNotional: 10, PriceBandPct: 15},
+	InstrumentTypeForex:     {TickSize: 0.00001, LotSize: 1, QuantityPrecision: 0, MinQuantity: 1, MaxQuantity: 10000000, MinNotional: 1, PriceBandPct: 5},
+	InstrumentTypeCommodity: {TickSize: 0.01, LotSize: 0.01, QuantityPrecision: 2, MinQuantity: 0.01, MaxQuantity: 100000, MinNotional: 1, PriceBandPct: 10},
+	InstrumentTypeFuture:    {TickSize: 0.01, LotSize: 1, QuantityPrecision: 0, MinQuantity: 1, MaxQuantity: 10000, MinNotional: 1, PriceBandPct: 10},
+	InstrumentTypeOption:    {TickSize: 0.01, LotSize: 1, QuantityPrecision: 0, MinQuantity: 1, MaxQuantity: 10000, MinNotional: 1, PriceBandPct: 50},
+}
+
+// DefaultTradingRules returns the default rules for an instrument type.
+func DefaultTradingRules(instrumentType InstrumentType) TradingRules {
+	if rules, ok := defaultTradingRules[instrumentType]; ok {
+		return rules
+	}
+	return defaultTradingRules[InstrumentTypeStock]
+}
+
+// EffectiveTradingRules returns the type defaults overlaid with any
+// per-instrument overrides.
+func (i *Instrument) EffectiveTradingRules() TradingRules {
+	rules := DefaultTradingRules(i.Type)
+	if i.TradingRules == nil {
+		return rules
+	}
+	return rules.Merge(*i.TradingRules)
+}
+
+// Merge returns r with every non-zero field of override applied.
+func (r TradingRules) Merge(override TradingRules) TradingRules {
+	if override.TickSize > 0 {
+		r.TickSize = override.TickSize
+	}
+	if override.LotSize > 0 {
+		r.LotSize = override.LotSize
+	}
+	if override.QuantityPrecision > 0 {
+		r.QuantityPrecision = override.QuantityPrecision
+	}
+	if override.MinQuantity > 0 {
+		r.MinQuantity = override.MinQuantity
+	}
+	if override.MaxQuantity > 0 {
+		r.MaxQuantity = override.MaxQuantity
+	}
+	if override.MinNotional > 0 {
+		r.MinNotional = override.MinNotional
+	}
+	if override.PriceBandPct > 0 {
+		r.PriceBandPct = override.PriceBandPct
+	}
+	return r
+}
+
+// ValidateOrder checks an order against the rules. price is the limit price
+// (0 for MARKET orders) and lastPrice the reference price (0 if unknown).
+// Returns the first violation found, or nil.
+func (r TradingRules) ValidateOrder(quantity, price, stopPrice, lastPrice float64) *RuleViolation {
+	if r.MinQuantity > 0 && quantity < r.MinQuantity {
+		return &RuleViolation{RuleCodeMinQuantity, "quantity", fmt.Sprintf("Quantity must be at least %g", r.MinQuantity), r.MinQuantity}
+	}
+	if r.MaxQuantity > 0 && quantity > r.MaxQuantity {
+		return &RuleViolation{RuleCodeMaxQuantity, "quantity", fmt.Sprintf("Quantity must be at most %g", r.MaxQuantity), r.MaxQuantity}
+	}
+	if !hasPrecision(quantity, r.QuantityPrecision) {
+		return &RuleViolation{RuleCodePrecision, "quantity", fmt.Sprintf("Quantity allows at most %d decimal places", r.QuantityPrecision), float64(r.QuantityPrecision)}
+	}
+	if r.LotSize > 0 && !isMultiple(quantity, r.LotSize) {
+		return &RuleViolation{RuleCodeLotSize, "quantity", fmt.Sprintf("Quantity must be a multiple of %g", r.LotSize), r.LotSize}
+	}
+
+	if price > 0 && r.TickSize > 0 && !isMultiple(price, r.TickSize) {
+		return &RuleViolation{RuleCodeTickSize, "price", fmt.Sprintf("Price must be a multiple of %g", r.TickSize), r.TickSize}
+	}
+	if stopPrice > 0 && r.TickSize > 0 && !isMultiple(stopPrice, r.TickSize) {
+		return &RuleViolation{RuleCodeStopPriceTick, "stopPrice", fmt.Sprintf("Stop price must be a multiple of %g", r.TickSize), r.TickSize}
+	}
+
+	if price > 0 && lastPrice > 0 && r.PriceBandPct > 0 {
+		deviation := math.Abs(price-lastPrice) / lastPrice * 100
+		if deviation > r.PriceBandPct {
+			return &RuleViolation{RuleCodePriceBand, "price", fmt.Sprintf("Price must be within %g%% of the last price %g", r.PriceBandPct, lastPrice), r.PriceBandPct}
+		}
+	}
+
+	// MARKET orders are checked against the reference price
+	notionalPrice := price
+	if notionalPrice <= 0 {
+		notionalPrice = lastPrice
+	}
+	if r.MinNotional > 0 && notionalPrice > 0 && quantity*notionalPrice < r.MinNotional {
+		return &RuleViolation{RuleCodeMinNotional, "quantity", fmt.Sprintf("Order value must be at least %g", r.MinNotional), r.MinNotional}
+	}
+
+	return nil
+}
+
+// ruleEpsilon absorbs float rounding when checking increments
+const ruleEpsilon = 1e-9
+
+// isMultiple reports whether value is an integer multiple of step
+func isMultiple(value, step float64) bool {
+	n := value / step
+	return math.Abs(n-math.Round(n)) <= ruleEpsilon*math.Max(1, math.Abs(n))
+}
+
+// hasPrecision reports whether value has at most places decimal places
+func hasPrecision(value float64, places int) bool {
+	if places < 0 {
+		return true
+	}
+	return isMultiple(value, math.Pow10(-places))
+}
@@ -5,6 +5,9 @@ import (
 	"github.com/bricksocoolxd/bengi-investment-system/module/instrument/controller"
 	"github.com/bricksocoolxd/bengi-investment-system/module/instrument/repository"
 	"github.com/bricksocoolxd/bengi-investment-system/module/instrument/service"
+	tradeController "github.com/bricksocoolxd/bengi-investment-system/module/trade/controller"
+	tradeRepo "github.com/bricksocoolxd/bengi-investment-system/module/trade/repository"
+	tradeService "github.com/bricksocoolxd/bengi-investment-system/module/trade/service"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,6 +18,9 @@ func RegisterRoutes(app *fiber.App) {
 	marketSvc := service.NewMarketDataService()
 	instrumentSvc := service.NewInstrumentService(repo, marketSvc)
 	ctrl := controller.NewInstrumentController(instrumentSvc)
+	tapeCtrl := tradeController.NewTapeController(
+		tradeService.NewTapeService(tradeRepo.NewTradePrintRepository()),
+	)
 
 	instruments := app.Group("/api/v1/instruments")
 
@@ -24,6 +30,7 @@ func RegisterRoutes(app *fiber.App) {
 	instruments.Get("/:symbol", ctrl.GetInstrumentBySymbol)
 	instruments.Get("/:symbol/quote", ctrl.GetQuote)
 	instruments.Get("/:symbol/candles", ctrl.GetCandles)
+	instruments.Get("/:symbol/trades", tapeCtrl.GetTape)
 
 	// Admin routes (auth + admin role required)
 	admin := instruments.Group("", middleware.AuthRequired(), middleware.RoleRequired(model.RoleAdmin))
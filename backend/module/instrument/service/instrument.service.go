@@ -15,6 +15,13 @@ var (
 	ErrSymbolExists       = errors.New("symbol already exists")
 )
 
+// instrumentStatuses maps request status values to stored statuses
+var instrumentStatuses = map[string]model.InstrumentStatus{
+	"ACTIVE":   model.InstrumentStatusActive,
+	"INACTIVE": model.InstrumentStatusInactive,
+	"DELISTED": model.InstrumentStatusDelisted,
+}
+
 type InstrumentService struct {
 	repository        *repository.InstrumentRepository
 	MarketDataService *MarketDataService
@@ -197,7 +204,18 @@ func (s *InstrumentService) UpdateInstrument(ctx context.Context, symbol string,
 		update["logoUrl"] = req.LogoURL
 	}
 	if req.Status != "" {
-		update["status"] = req.Status
+		update["status"] = instrumentStatuses[req.Status]
+	}
+	if req.TradingRules != nil {
+		update["tradingRules"] = model.TradingRules{
+			TickSize:          req.TradingRules.TickSize,
+			LotSize:           req.TradingRules.LotSize,
+			QuantityPrecision: req.TradingRules.QuantityPrecision,
+			MinQuantity:       req.TradingRules.MinQuantity,
+			MaxQuantity:       req.TradingRules.MaxQuantity,
+			MinNotional:       req.TradingRules.MinNotional,
+			PriceBandPct:      req.TradingRules.PriceBandPct,
+		}
 	}
 
 	if len(update) > 0 {
@@ -217,6 +235,7 @@ func (s *InstrumentService) UpdateInstrument(ctx context.Context, symbol string,
 
 // Helper: Convert Instrument to InstrumentResponse
 func (s *InstrumentService) toInstrumentResponse(inst *model.Instrument) *dto.InstrumentResponse {
+	rules := inst.EffectiveTradingRules()
 	return &dto.InstrumentResponse{
 		ID:          inst.ID.Hex(),
 		Symbol:      inst.Symbol,
@@ -227,6 +246,15 @@ func (s *InstrumentService) toInstrumentResponse(inst *model.Instrument) *dto.In
 		Status:      string(inst.Status),
 		Description: inst.Description,
 		LogoURL:     inst.LogoURL,
+		TradingRules: &dto.TradingRulesResponse{
+			TickSize:          rules.TickSize,
+			LotSize:           rules.LotSize,
+			QuantityPrecision: rules.QuantityPrecision,
+			MinQuantity:       rules.MinQuantity,
+			MaxQuantity:       rules.MaxQuantity,
+			MinNotional:       rules.MinNotional,
+			PriceBandPct:      rules.PriceBandPct,
+		},
 	}
 }
 
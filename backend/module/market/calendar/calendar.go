@@ -0,0 +1,454 @@
+package calendar
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownExchange        = errors.New("unknown exchange")
+	ErrSessionClosed          = errors.New("market is closed")
+	ErrExtendedHoursRequired  = errors.New("order is not enabled for extended-hours trading")
+	ErrExtendedHoursLimitOnly = errors.New("extended-hours trading is only available for LIMIT orders")
+)
+
+// Phase is the trading session an exchange is in at a given moment.
+type Phase string
+
+const (
+	PhasePreMarket  Phase = "PRE_MARKET"  // Before the regular open
+	PhaseRegular    Phase = "OPEN"        // Regular trading hours
+	PhaseAfterHours Phase = "AFTER_HOURS" // After the regular close
+	PhaseClosed     Phase = "CLOSED"      // No trading at all
+)
+
+// Window is a session window expressed as minutes after local midnight.
+// End is exclusive; an End of 24*60 means the session runs to midnight.
+type Window struct {
+	Start int
+	End   int
+}
+
+// Contains reports whether a minute-of-day falls inside the window.
+func (w Window) Contains(minute int) bool {
+	return w.End > w.Start && minute >= w.Start && minute < w.End
+}
+
+// IsZero reports whether the window is empty.
+func (w Window) IsZero() bool {
+	return w.End <= w.Start
+}
+
+// Exchange describes the trading hours of a single venue.
+// All windows are in the exchange's local time zone.
+type Exchange struct {
+	Code        string
+	Name        string
+	TimeZone    string
+	AlwaysOpen  bool                  // 24/7 venues such as crypto
+	TradingDays map[time.Weekday]bool // Days with any session at all
+	PreMarket   Window
+	Regular     Window
+	AfterHours  Window
+	Holidays    map[string]string // "2006-01-02" -> holiday name
+	EarlyCloses map[string]int    // "2006-01-02" -> regular close (minute of day)
+	// Recurring closures and early closes, generated for any year on top
+	// of the one-off dates above
+	HolidayRules    []HolidayRule
+	EarlyCloseRules []EarlyCloseRule
+
+	location *time.Location
+	years    sync.Map // Year -> *yearDates generated from the rules
+}
+
+// Location returns the exchange's time zone, falling back to UTC.
+func (e *Exchange) Location() *time.Location {
+	if e.location == nil {
+		return time.UTC
+	}
+	return e.location
+}
+
+// Sessions returns the session windows for the local calendar date of t,
+// with early closes applied. ok is false when the exchange is shut all day.
+func (e *Exchange) Sessions(t time.Time) (pre, regular, after Window, ok bool) {
+	local := t.In(e.Location())
+	if e.AlwaysOpen {
+		return Window{}, Window{Start: 0, End: 24 * 60}, Window{}, true
+	}
+	if !e.TradingDays[local.Weekday()] {
+		return Window{}, Window{}, Window{}, false
+	}
+	if _, holiday := e.holiday(local); holiday {
+		return Window{}, Window{}, Window{}, false
+	}
+
+	pre, regular, after = e.PreMarket, e.Regular, e.AfterHours
+	if closeAt, early := e.earlyClose(local); early {
+		regular.End = closeAt
+		// Extended hours end with the same offset after an early close
+		if !after.IsZero() {
+			after = Window{Start: closeAt, End: closeAt + (e.AfterHours.End - e.Regular.End)}
+		}
+	}
+	return pre, regular, after, true
+}
+
+// PhaseAt returns the session phase at time t.
+func (e *Exchange) PhaseAt(t time.Time) Phase {
+	pre, regular, after, ok := e.Sessions(t)
+	if !ok {
+		return PhaseClosed
+	}
+
+	minute := minuteOfDay(t.In(e.Location()))
+	switch {
+	case regular.Contains(minute):
+		return PhaseRegular
+	case pre.Contains(minute):
+		return PhasePreMarket
+	case after.Contains(minute):
+		return PhaseAfterHours
+	default:
+		return PhaseClosed
+	}
+}
+
+// HolidayAt returns the holiday name for the local date of t, if any.
+func (e *Exchange) HolidayAt(t time.Time) string {
+	name, _ := e.holiday(t.In(e.Location()))
+	return name
+}
+
+// IsEarlyClose reports whether the local date of t has a shortened session.
+func (e *Exchange) IsEarlyClose(t time.Time) bool {
+	_, early := e.earlyClose(t.In(e.Location()))
+	return early
+}
+
+// holiday returns the closure on a local date, one-off or from the rules
+func (e *Exchange) holiday(local time.Time) (string, bool) {
+	key := dateKey(local)
+	if name, ok := e.Holidays[key]; ok {
+		return name, true
+	}
+	name, ok := e.datesFor(local.Year()).holidays[key]
+	return name, ok
+}
+
+// earlyClose returns the shortened close on a local date, if any
+func (e *Exchange) earlyClose(local time.Time) (int, bool) {
+	key := dateKey(local)
+	if closeAt, ok := e.EarlyCloses[key]; ok {
+		return closeAt, true
+	}
+	closeAt, ok := e.datesFor(local.Year()).earlyCloses[key]
+	return closeAt, ok
+}
+
+// NextOpen returns the next regular-session open strictly after t.
+// Returns the zero time for always-open venues.
+func (e *Exchange) NextOpen(t time.Time) time.Time {
+	if e.AlwaysOpen {
+		return time.Time{}
+	}
+	local := t.In(e.Location())
+	for i := 0; i < maxLookaheadDays; i++ {
+		day := startOfDay(local).AddDate(0, 0, i)
+		_, regular, _, ok := e.Sessions(day)
+		if !ok || regular.IsZero() {
+			continue
+		}
+		open := atMinute(day, regular.Start)
+		if open.After(local) {
+			return open
+		}
+	}
+	return time.Time{}
+}
+
+// NextClose returns the next regular-session close strictly after t.
+// Returns the zero time for always-open venues.
+func (e *Exchange) NextClose(t time.Time) time.Time {
+	if e.AlwaysOpen {
+		return time.Time{}
+	}
+	local := t.In(e.Location())
+	for i := 0; i < maxLookaheadDays; i++ {
+		day := startOfDay(local).AddDate(0, 0, i)
+		_, regular, _, ok := e.Sessions(day)
+		if !ok || regular.IsZero() {
+			continue
+		}
+		closeAt := atMinute(day, regular.End)
+		if closeAt.After(local) {
+			return closeAt
+		}
+	}
+	return time.Time{}
+}
+
+// NextTransition returns the next session boundary strictly after t: the
+// start or end of any pre-market, regular or after-hours window, where the
+// phase may change. Returns the zero time for always-open venues.
+func (e *Exchange) NextTransition(t time.Time) time.Time {
+	if e.AlwaysOpen {
+		return time.Time{}
+	}
+	local := t.In(e.Location())
+	for i := 0; i < maxLookaheadDays; i++ {
+		day := startOfDay(local).AddDate(0, 0, i)
+		pre, regular, after, ok := e.Sessions(day)
+		if !ok {
+			continue
+		}
+
+		var next time.Time
+		for _, window := range []Window{pre, regular, after} {
+			if window.IsZero() {
+				continue
+			}
+			for _, minute := range []int{window.Start, window.End} {
+				at := atMinute(day, minute)
+				if at.After(local) && (next.IsZero() || at.Before(next)) {
+					next = at
+				}
+			}
+		}
+		if !next.IsZero() {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// TradeDate returns the local trading day a trade at t belongs to: its
+// own date, or the next trading day when the exchange is shut.
+func (e *Exchange) TradeDate(t time.Time) time.Time {
+	day := startOfDay(t.In(e.Location()))
+	for i := 0; i < maxLookaheadDays; i++ {
+		if _, _, _, ok := e.Sessions(day); ok {
+			return day
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// SettlementDate returns when a trade at t settles, days trading days
+// after its trade date (T+days). Settlement happens at the start of that
+// local date; a T+0 trade settles at t.
+func (e *Exchange) SettlementDate(t time.Time, days int) time.Time {
+	if days <= 0 {
+		return t
+	}
+	day := e.TradeDate(t)
+	for days > 0 {
+		day = day.AddDate(0, 0, 1)
+		if _, _, _, ok := e.Sessions(day); ok {
+			days--
+		}
+	}
+	return day
+}
+
+// CheckEligibility reports whether an order may execute during phase.
+// Extended-hours sessions only accept LIMIT orders that opted in.
+func CheckEligibility(phase Phase, orderType string, extendedHours bool) error {
+	switch phase {
+	case PhaseRegular:
+		return nil
+	case PhasePreMarket, PhaseAfterHours:
+		if !extendedHours {
+			return ErrExtendedHoursRequired
+		}
+		if orderType != "LIMIT" {
+			return ErrExtendedHoursLimitOnly
+		}
+		return nil
+	default:
+		return ErrSessionClosed
+	}
+}
+
+// Status is a point-in-time view of an exchange's session state.
+type Status struct {
+	Exchange   string
+	Name       string
+	TimeZone   string
+	Phase      Phase
+	LocalTime  time.Time
+	Holiday    string
+	EarlyClose bool
+	NextOpen   time.Time
+	NextClose  time.Time
+}
+
+// IsOpen reports whether the regular session is in progress.
+func (s *Status) IsOpen() bool {
+	return s.Phase == PhaseRegular
+}
+
+// Calendar is a registry of exchanges keyed by their upper-cased code.
+type Calendar struct {
+	exchanges map[string]*Exchange
+	aliases   map[string]string
+	mu        sync.RWMutex
+}
+
+// NewCalendar creates an empty calendar.
+func NewCalendar() *Calendar {
+	return &Calendar{
+		exchanges: make(map[string]*Exchange),
+		aliases:   make(map[string]string),
+	}
+}
+
+var (
+	defaultCalendar *Calendar
+	calendarOnce    sync.Once
+)
+
+// Default returns the singleton calendar preloaded with the built-in exchanges.
+func Default() *Calendar {
+	calendarOnce.Do(func() {
+		defaultCalendar = NewCalendar()
+		registerBuiltins(defaultCalendar)
+	})
+	return defaultCalendar
+}
+
+// Register adds or replaces an exchange, resolving its time zone.
+func (c *Calendar) Register(exchange *Exchange, aliases ...string) {
+	exchange.location = loadLocation(exchange.TimeZone)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	code := normalize(exchange.Code)
+	c.exchanges[code] = exchange
+	for _, alias := range aliases {
+		c.aliases[normalize(alias)] = code
+	}
+}
+
+// Exchange looks up an exchange by code or alias.
+func (c *Calendar) Exchange(code string) (*Exchange, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	key := normalize(code)
+	if target, ok := c.aliases[key]; ok {
+		key = target
+	}
+	exchange, ok := c.exchanges[key]
+	return exchange, ok
+}
+
+// Canonical resolves an exchange code or alias to its registered code.
+// Unknown codes are returned normalized.
+func (c *Calendar) Canonical(code string) string {
+	if exchange, ok := c.Exchange(code); ok {
+		return exchange.Code
+	}
+	return normalize(code)
+}
+
+// Exchanges returns every registered exchange.
+func (c *Calendar) Exchanges() []*Exchange {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]*Exchange, 0, len(c.exchanges))
+	for _, exchange := range c.exchanges {
+		result = append(result, exchange)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// Status returns the session state of an exchange at time t.
+func (c *Calendar) Status(code string, t time.Time) (*Status, error) {
+	exchange, ok := c.Exchange(code)
+	if !ok {
+		return nil, ErrUnknownExchange
+	}
+
+	return &Status{
+		Exchange:   exchange.Code,
+		Name:       exchange.Name,
+		TimeZone:   exchange.Location().String(),
+		Phase:      exchange.PhaseAt(t),
+		LocalTime:  t.In(exchange.Location()),
+		Holiday:    exchange.HolidayAt(t),
+		EarlyClose: exchange.IsEarlyClose(t),
+		NextOpen:   exchange.NextOpen(t),
+		NextClose:  exchange.NextClose(t),
+	}, nil
+}
+
+// SettlementDate returns when a trade at t on an exchange settles at
+// T+days. Unknown exchanges count weekdays in UTC.
+func (c *Calendar) SettlementDate(code string, t time.Time, days int) time.Time {
+	exchange, ok := c.Exchange(code)
+	if !ok {
+		exchange = &Exchange{Code: code, TradingDays: usWeekdays}
+	}
+	return exchange.SettlementDate(t, days)
+}
+
+// ExchangeForInstrument picks the calendar code for an instrument.
+// Synced instruments often have no exchange, so fall back on the type.
+func ExchangeForInstrument(exchange, instrumentType string) string {
+	if exchange != "" {
+		return exchange
+	}
+	switch strings.ToLower(instrumentType) {
+	case "crypto":
+		return ExchangeCrypto
+	case "forex":
+		return ExchangeForex
+	default:
+		return ExchangeNYSE
+	}
+}
+
+// maxLookaheadDays bounds the NextOpen/NextClose search (long holiday weekends)
+const maxLookaheadDays = 14
+
+func normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func dateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// atMinute returns the wall-clock time minute minutes into day's local date.
+func atMinute(day time.Time, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minute/60, minute%60, 0, 0, day.Location())
+}
+
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,103 @@
+package calendar
+
+import "time"
+
+// Built-in exchange codes
+const (
+	ExchangeNYSE   = "NYSE"
+	ExchangeNASDAQ = "NASDAQ"
+	ExchangeBATS   = "BATS"
+	ExchangeNYMEX  = "NYMEX"
+	ExchangeCrypto = "CRYPTO"
+	ExchangeForex  = "FOREX"
+)
+
+// usWeekdays are the trading days for US venues
+var usWeekdays = map[time.Weekday]bool{
+	time.Monday:    true,
+	time.Tuesday:   true,
+	time.Wednesday: true,
+	time.Thursday:  true,
+	time.Friday:    true,
+}
+
+// usEquityHolidays are the recurring full-day closures shared by NYSE,
+// NASDAQ and Cboe
+var usEquityHolidays = []HolidayRule{
+	{Name: "New Year's Day", Date: FixedDate(time.January, 1), Observed: true},
+	{Name: "Martin Luther King Jr. Day", Date: NthWeekday(time.January, time.Monday, 3)},
+	{Name: "Washington's Birthday", Date: NthWeekday(time.February, time.Monday, 3)},
+	{Name: "Good Friday", Date: EasterOffset(-2)},
+	{Name: "Memorial Day", Date: NthWeekday(time.May, time.Monday, -1)},
+	{Name: "Juneteenth", Date: FixedDate(time.June, 19), Observed: true, From: 2022},
+	{Name: "Independence Day", Date: FixedDate(time.July, 4), Observed: true},
+	{Name: "Labor Day", Date: NthWeekday(time.September, time.Monday, 1)},
+	{Name: "Thanksgiving Day", Date: NthWeekday(time.November, time.Thursday, 4)},
+	{Name: "Christmas Day", Date: FixedDate(time.December, 25), Observed: true},
+}
+
+// usEquitySpecialClosures are one-off full-day closures
+var usEquitySpecialClosures = map[string]string{
+	"2025-01-09": "National Day of Mourning",
+}
+
+// usEquityEarlyCloses are the 1:00 PM ET early closes: the eve of
+// Independence Day and of Christmas when they fall on a weekday, and the
+// day after Thanksgiving
+var usEquityEarlyCloses = []EarlyCloseRule{
+	{Date: OnWeekdays(FixedDate(time.July, 3), time.Monday, time.Tuesday, time.Wednesday, time.Thursday), Close: 13 * 60},
+	{Date: DayAfter(NthWeekday(time.November, time.Thursday, 4)), Close: 13 * 60},
+	{Date: OnWeekdays(FixedDate(time.December, 24), time.Monday, time.Tuesday, time.Wednesday, time.Thursday), Close: 13 * 60},
+}
+
+// newUSEquityExchange builds a US equity venue: 4:00 pre-market,
+// 9:30-16:00 regular session, after-hours until 20:00 ET.
+func newUSEquityExchange(code, name string) *Exchange {
+	return &Exchange{
+		Code:            code,
+		Name:            name,
+		TimeZone:        "America/New_York",
+		TradingDays:     usWeekdays,
+		PreMarket:       Window{Start: 4 * 60, End: 9*60 + 30},
+		Regular:         Window{Start: 9*60 + 30, End: 16 * 60},
+		AfterHours:      Window{Start: 16 * 60, End: 20 * 60},
+		Holidays:        usEquitySpecialClosures,
+		HolidayRules:    usEquityHolidays,
+		EarlyCloseRules: usEquityEarlyCloses,
+	}
+}
+
+// registerBuiltins loads the venues used by the seeded instruments
+func registerBuiltins(c *Calendar) {
+	c.Register(newUSEquityExchange(ExchangeNYSE, "New York Stock Exchange"), "US", "AMEX", "NYSEARCA", "ARCA")
+	c.Register(newUSEquityExchange(ExchangeNASDAQ, "Nasdaq"))
+	c.Register(newUSEquityExchange(ExchangeBATS, "Cboe BZX"), "CBOE")
+
+	// Energy futures trade nearly around the clock on Globex; approximated
+	// as full weekdays in exchange time with the US holiday closures.
+	c.Register(&Exchange{
+		Code:         ExchangeNYMEX,
+		Name:         "New York Mercantile Exchange",
+		TimeZone:     "America/Chicago",
+		TradingDays:  usWeekdays,
+		Regular:      Window{Start: 0, End: 24 * 60},
+		Holidays:     usEquitySpecialClosures,
+		HolidayRules: usEquityHolidays,
+	}, "CME", "COMEX")
+
+	// Spot FX runs 24/5; approximated as full weekdays in New York time.
+	c.Register(&Exchange{
+		Code:        ExchangeForex,
+		Name:        "Foreign Exchange",
+		TimeZone:    "America/New_York",
+		TradingDays: usWeekdays,
+		Regular:     Window{Start: 0, End: 24 * 60},
+	}, "FX", "OANDA")
+
+	c.Register(&Exchange{
+		Code:       ExchangeCrypto,
+		Name:       "Crypto",
+		TimeZone:   "UTC",
+		AlwaysOpen: true,
+	}, "BINANCE", "COINBASE", "KRAKEN")
+}
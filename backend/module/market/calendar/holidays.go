@@ -0,0 +1,146 @@
+package calendar
+
+import "time"
+
+// DateFunc returns a recurring date in the given year, as a local
+// calendar date. ok is false when the date doesn't occur that year.
+type DateFunc func(year int) (date time.Time, ok bool)
+
+// HolidayRule is a full-day closure that recurs every year.
+type HolidayRule struct {
+	Name string
+	Date DateFunc
+	// Observed moves a Saturday holiday to the Friday before and a Sunday
+	// one to the Monday after. An observance that would land in another
+	// year is dropped, as US exchanges do for a Saturday New Year's Day.
+	Observed bool
+	// From is the first year the holiday is kept; 0 for always
+	From int
+}
+
+// EarlyCloseRule shortens the regular session on a recurring date.
+type EarlyCloseRule struct {
+	Date  DateFunc
+	Close int // Regular close (minute of day)
+}
+
+// FixedDate is the same month and day every year.
+func FixedDate(month time.Month, day int) DateFunc {
+	return func(year int) (time.Time, bool) {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), true
+	}
+}
+
+// NthWeekday is the nth given weekday of a month; a negative n counts
+// back from the end of the month, so -1 is the last one.
+func NthWeekday(month time.Month, weekday time.Weekday, n int) DateFunc {
+	return func(year int) (time.Time, bool) {
+		if n < 0 {
+			last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+			back := (int(last.Weekday()) - int(weekday) + 7) % 7
+			return last.AddDate(0, 0, -back+(n+1)*7), true
+		}
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		ahead := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, ahead+(n-1)*7), true
+	}
+}
+
+// EasterOffset is a number of days from Western Easter Sunday, e.g. -2
+// for Good Friday.
+func EasterOffset(days int) DateFunc {
+	return func(year int) (time.Time, bool) {
+		return easter(year).AddDate(0, 0, days), true
+	}
+}
+
+// DayAfter is the day following another recurring date.
+func DayAfter(date DateFunc) DateFunc {
+	return func(year int) (time.Time, bool) {
+		d, ok := date(year)
+		return d.AddDate(0, 0, 1), ok
+	}
+}
+
+// OnWeekdays keeps a recurring date only in years it falls on one of the
+// given weekdays.
+func OnWeekdays(date DateFunc, weekdays ...time.Weekday) DateFunc {
+	return func(year int) (time.Time, bool) {
+		d, ok := date(year)
+		if !ok {
+			return d, false
+		}
+		for _, weekday := range weekdays {
+			if d.Weekday() == weekday {
+				return d, true
+			}
+		}
+		return d, false
+	}
+}
+
+// easter returns Western Easter Sunday using the anonymous Gregorian
+// algorithm (Meeus/Jones/Butcher).
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// yearDates are the closures and early closes the rules give for a year
+type yearDates struct {
+	holidays    map[string]string
+	earlyCloses map[string]int
+}
+
+// datesFor returns the dates the exchange's rules give for year,
+// generating them the first time the year is asked for
+func (e *Exchange) datesFor(year int) *yearDates {
+	if cached, ok := e.years.Load(year); ok {
+		return cached.(*yearDates)
+	}
+
+	dates := &yearDates{
+		holidays:    make(map[string]string),
+		earlyCloses: make(map[string]int),
+	}
+	for _, rule := range e.HolidayRules {
+		if year < rule.From {
+			continue
+		}
+		date, ok := rule.Date(year)
+		if !ok {
+			continue
+		}
+		name := rule.Name
+		if rule.Observed {
+			switch date.Weekday() {
+			case time.Saturday:
+				date, name = date.AddDate(0, 0, -1), name+" (observed)"
+			case time.Sunday:
+				date, name = date.AddDate(0, 0, 1), name+" (observed)"
+			}
+			if date.Year() != year {
+				continue
+			}
+		}
+		dates.holidays[dateKey(date)] = name
+	}
+	for _, rule := range e.EarlyCloseRules {
+		if date, ok := rule.Date(year); ok {
+			dates.earlyCloses[dateKey(date)] = rule.Close
+		}
+	}
+
+	cached, _ := e.years.LoadOrStore(year, dates)
+	return cached.(*yearDates)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FXController struct {
+	fxService *service.FXService
+}
+
+func NewFXController(fxService *service.FXService) *FXController {
+	return &FXController{
+		fxService: fxService,
+	}
+}
+
+// GetRate returns the current rate for a currency pair, or the rate in
+// effect at ?at= (RFC3339)
+// GET /api/v1/fx/rates/:base/:quote
+func (ctrl *FXController) GetRate(c *fiber.Ctx) error {
+	var at *time.Time
+	if raw := c.Query("at"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return common.BadRequest(c, "at must be an RFC3339 time")
+		}
+		at = &parsed
+	}
+
+	result, err := ctrl.fxService.GetRate(c.Context(), c.Params("base"), c.Params("quote"), at)
+	if err != nil {
+		if errors.Is(err, service.ErrFXRateNotFound) {
+			return common.NotFound(c, "FX rate not found")
+		}
+		return common.InternalError(c, err.Error())
+	}
+
+	return common.Success(c, result, "")
+}
+
+// GetHistory returns the recorded rates for a currency pair between
+// ?from= and ?to= (RFC3339, default the last 24 hours)
+// GET /api/v1/fx/rates/:base/:quote/history
+func (ctrl *FXController) GetHistory(c *fiber.Ctx) error {
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	var err error
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			return common.BadRequest(c, "from must be an RFC3339 time")
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			return common.BadRequest(c, "to must be an RFC3339 time")
+		}
+	}
+
+	result, err := ctrl.fxService.GetHistory(c.Context(), c.Params("base"), c.Params("quote"), from, to)
+	if err != nil {
+		return common.InternalError(c, err.Error())
+	}
+
+	return common.Success(c, result, "")
+}
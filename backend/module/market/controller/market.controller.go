@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type MarketController struct {
+	marketService *service.MarketService
+	haltService   *service.HaltService
+}
+
+func NewMarketController(marketService *service.MarketService, haltService *service.HaltService) *MarketController {
+	return &MarketController{
+		marketService: marketService,
+		haltService:   haltService,
+	}
+}
+
+// GetMarkets returns the session state of every known exchange
+// GET /api/v1/markets
+func (ctrl *MarketController) GetMarkets(c *fiber.Ctx) error {
+	return common.Success(c, ctrl.marketService.GetAllStatuses(c.Context()), "")
+}
+
+// GetMarketStatus returns the current session state of an exchange
+// GET /api/v1/markets/:exchange/status
+func (ctrl *MarketController) GetMarketStatus(c *fiber.Ctx) error {
+	exchange, _ := url.PathUnescape(c.Params("exchange"))
+
+	result, err := ctrl.marketService.GetStatus(c.Context(), exchange)
+	if err != nil {
+		if errors.Is(err, service.ErrExchangeNotFound) {
+			return common.NotFound(c, "Exchange not found")
+		}
+		return common.InternalError(c, err.Error())
+	}
+
+	return common.Success(c, result, "")
+}
+
+// GetHalts returns every active trading halt
+// GET /api/v1/markets/halts
+func (ctrl *MarketController) GetHalts(c *fiber.Ctx) error {
+	result, err := ctrl.haltService.GetActiveHalts(c.Context())
+	if err != nil {
+		return common.InternalError(c, err.Error())
+	}
+
+	return common.Success(c, result, "")
+}
+
+// HaltSymbol halts trading on an instrument (admin only)
+// POST /api/v1/markets/symbols/:symbol/halt
+func (ctrl *MarketController) HaltSymbol(c *fiber.Ctx) error {
+	var req dto.HaltRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.haltService.HaltSymbol(c.Context(), c.Params("symbol"), middleware.GetUserID(c), &req)
+	if err != nil {
+		return handleHaltError(c, err)
+	}
+
+	return common.Created(c, result, "Trading halted")
+}
+
+// ResumeSymbol resumes trading on an instrument (admin only)
+// POST /api/v1/markets/symbols/:symbol/resume
+func (ctrl *MarketController) ResumeSymbol(c *fiber.Ctx) error {
+	var req dto.ResumeRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.haltService.ResumeSymbol(c.Context(), c.Params("symbol"), middleware.GetUserID(c), &req)
+	if err != nil {
+		return handleHaltError(c, err)
+	}
+
+	return common.Success(c, result, "Trading resumed")
+}
+
+// HaltExchange halts trading on every instrument of an exchange (admin only)
+// POST /api/v1/markets/:exchange/halt
+func (ctrl *MarketController) HaltExchange(c *fiber.Ctx) error {
+	exchange, _ := url.PathUnescape(c.Params("exchange"))
+
+	var req dto.HaltRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.haltService.HaltExchange(c.Context(), exchange, middleware.GetUserID(c), &req)
+	if err != nil {
+		return handleHaltError(c, err)
+	}
+
+	return common.Created(c, result, "Trading halted")
+}
+
+// ResumeExchange resumes trading on an exchange (admin only)
+// POST /api/v1/markets/:exchange/resume
+func (ctrl *MarketController) ResumeExchange(c *fiber.Ctx) error {
+	exchange, _ := url.PathUnescape(c.Params("exchange"))
+
+	var req dto.ResumeRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.haltService.ResumeExchange(c.Context(), exchange, middleware.GetUserID(c), &req)
+	if err != nil {
+		return handleHaltError(c, err)
+	}
+
+	return common.Success(c, result, "Trading resumed")
+}
+
+// handleHaltError maps halt service errors to responses
+func handleHaltError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInstrumentNotFound):
+		return common.NotFound(c, "Instrument not found")
+	case errors.Is(err, service.ErrExchangeNotFound):
+		return common.NotFound(c, "Exchange not found")
+	case errors.Is(err, service.ErrAlreadyHalted):
+		return common.BadRequest(c, "Trading is already halted")
+	case errors.Is(err, service.ErrNotHalted):
+		return common.BadRequest(c, "Trading is not halted")
+	default:
+		return common.InternalError(c, err.Error())
+	}
+}
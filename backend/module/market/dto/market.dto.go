@@ -0,0 +1,52 @@
+package dto
+
+// MarketStatusResponse describes the current session state of an exchange.
+// Times are RFC3339 in the exchange's local time zone.
+type MarketStatusResponse struct {
+	Exchange   string  `json:"exchange"`
+	Name       string  `json:"name"`
+	TimeZone   string  `json:"timeZone"`
+	Phase      string  `json:"phase"` // PRE_MARKET, OPEN, AFTER_HOURS, CLOSED
+	IsOpen     bool    `json:"isOpen"`
+	LocalTime  string  `json:"localTime"`
+	Holiday    string  `json:"holiday,omitempty"`
+	EarlyClose bool    `json:"earlyClose"`
+	NextOpen   *string `json:"nextOpen,omitempty"`
+	NextClose  *string `json:"nextClose,omitempty"`
+	Halted     bool    `json:"halted"`
+	HaltReason string  `json:"haltReason,omitempty"`
+}
+
+// HaltRequest halts trading on a symbol or exchange
+type HaltRequest struct {
+	Reason       string `json:"reason" validate:"required,min=3,max=500"`
+	CancelOrders bool   `json:"cancelOrders"` // Cancel resting orders instead of suspending them
+}
+
+// ResumeRequest resumes trading after a halt
+type ResumeRequest struct {
+	Reason string `json:"reason" validate:"omitempty,max=500"`
+}
+
+type HaltResponse struct {
+	ID             string  `json:"id"`
+	Scope          string  `json:"scope"`
+	Target         string  `json:"target"`
+	Reason         string  `json:"reason"`
+	Action         string  `json:"action"`
+	Active         bool    `json:"active"`
+	AffectedOrders int     `json:"affectedOrders"`
+	HaltedBy       string  `json:"haltedBy"`
+	HaltedAt       string  `json:"haltedAt"`
+	ResumedAt      *string `json:"resumedAt,omitempty"`
+	ResumeReason   string  `json:"resumeReason,omitempty"`
+}
+
+// FXRateResponse is an exchange rate: one unit of Base buys Rate units
+// of Quote
+type FXRateResponse struct {
+	Base  string  `json:"base"`
+	Quote string  `json:"quote"`
+	Rate  float64 `json:"rate"`
+	AsOf  string  `json:"asOf"`
+}
@@ -0,0 +1,107 @@
+package fx
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long a fetched rate is reused
+const DefaultCacheTTL = 5 * time.Minute
+
+type cachedRate struct {
+	rate      float64
+	fetchedAt time.Time
+}
+
+// Cache serves rates from a source provider, fetching each pair at most
+// once per TTL. Every fetch is reported to the refresh hooks, which is
+// how the rate history is kept.
+type Cache struct {
+	mu      sync.RWMutex
+	source  Provider
+	ttl     time.Duration
+	rates   map[string]cachedRate
+	onFetch []func(base, quote string, rate float64, at time.Time)
+	spread  float64 // Basis points charged on fill conversions
+}
+
+func NewCache(source Provider, ttl time.Duration) *Cache {
+	return &Cache{
+		source: source,
+		ttl:    ttl,
+		rates:  make(map[string]cachedRate),
+	}
+}
+
+// SetSource replaces the provider rates are fetched from and drops the
+// cached rates
+func (c *Cache) SetSource(source Provider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.source = source
+	c.rates = make(map[string]cachedRate)
+}
+
+// SetSpread sets the spread FillRate charges, in basis points
+func (c *Cache) SetSpread(spreadBps float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spread = spreadBps
+}
+
+// FillRate returns how many units of the account currency one unit of
+// the instrument currency costs (buy) or returns (sell) on a fill,
+// including the spread. An unknown instrument currency isn't converted.
+func (c *Cache) FillRate(instrumentCurrency, accountCurrency string, buy bool) (float64, error) {
+	if instrumentCurrency == "" || strings.EqualFold(instrumentCurrency, accountCurrency) {
+		return 1, nil
+	}
+	rate, err := c.Rate(instrumentCurrency, accountCurrency)
+	if err != nil {
+		return 0, err
+	}
+	c.mu.RLock()
+	spread := c.spread
+	c.mu.RUnlock()
+	return ApplySpread(rate, spread, buy), nil
+}
+
+// OnFetch registers a hook called with every rate fetched from the source
+func (c *Cache) OnFetch(fn func(base, quote string, rate float64, at time.Time)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onFetch = append(c.onFetch, fn)
+}
+
+func (c *Cache) Rate(base, quote string) (float64, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	if base == quote {
+		return 1, nil
+	}
+	key := base + "/" + quote
+	now := time.Now()
+
+	c.mu.RLock()
+	cached, ok := c.rates[key]
+	source := c.source
+	c.mu.RUnlock()
+	if ok && now.Sub(cached.fetchedAt) < c.ttl {
+		return cached.rate, nil
+	}
+
+	rate, err := source.Rate(base, quote)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.rates[key] = cachedRate{rate: rate, fetchedAt: now}
+	hooks := c.onFetch
+	c.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(base, quote, rate, now)
+	}
+	return rate, nil
+}
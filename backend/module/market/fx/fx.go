@@ -0,0 +1,119 @@
+// Package fx converts amounts between currencies using a pluggable rate
+// source.
+package fx
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrRateUnavailable = errors.New("fx rate unavailable")
+)
+
+// Provider quotes exchange rates: how many units of quote one unit of
+// base buys.
+type Provider interface {
+	Rate(base, quote string) (float64, error)
+}
+
+// ProviderFunc adapts a function to a Provider.
+type ProviderFunc func(base, quote string) (float64, error)
+
+func (f ProviderFunc) Rate(base, quote string) (float64, error) {
+	return f(base, quote)
+}
+
+// StaticProvider quotes from a fixed table of units per US dollar,
+// crossing through USD for other pairs.
+type StaticProvider struct {
+	perUSD map[string]float64
+}
+
+func NewStaticProvider(perUSD map[string]float64) *StaticProvider {
+	rates := make(map[string]float64, len(perUSD)+1)
+	for currency, rate := range perUSD {
+		rates[strings.ToUpper(currency)] = rate
+	}
+	rates["USD"] = 1
+	return &StaticProvider{perUSD: rates}
+}
+
+func (p *StaticProvider) Rate(base, quote string) (float64, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	if base == quote {
+		return 1, nil
+	}
+	baseRate, ok := p.perUSD[base]
+	if !ok || baseRate <= 0 {
+		return 0, ErrRateUnavailable
+	}
+	quoteRate, ok := p.perUSD[quote]
+	if !ok || quoteRate <= 0 {
+		return 0, ErrRateUnavailable
+	}
+	return quoteRate / baseRate, nil
+}
+
+// Built-in reference rates, units per USD
+var builtinRates = map[string]float64{
+	"EUR": 0.92,
+	"GBP": 0.79,
+	"JPY": 150.0,
+	"THB": 35.5,
+	"RUB": 90.0,
+	"SGD": 1.34,
+}
+
+// FallbackProvider asks each provider in turn until one has the rate.
+type FallbackProvider []Provider
+
+func (p FallbackProvider) Rate(base, quote string) (float64, error) {
+	for _, provider := range p {
+		if rate, err := provider.Rate(base, quote); err == nil && rate > 0 {
+			return rate, nil
+		}
+	}
+	return 0, ErrRateUnavailable
+}
+
+// Builtin returns a provider quoting the built-in reference rates
+func Builtin() *StaticProvider {
+	return NewStaticProvider(builtinRates)
+}
+
+var (
+	defaultCache *Cache
+	cacheOnce    sync.Once
+)
+
+// Default returns the singleton rate cache, sourced from the built-in
+// rates until SetSource plugs in another provider.
+func Default() *Cache {
+	cacheOnce.Do(func() {
+		defaultCache = NewCache(Builtin(), DefaultCacheTTL)
+	})
+	return defaultCache
+}
+
+// Convert turns amount of from into to, rounded to cents. It returns the
+// converted amount and the rate used.
+func Convert(p Provider, amount float64, from, to string) (float64, float64, error) {
+	rate, err := p.Rate(from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	return math.Round(amount*rate*100) / 100, rate, nil
+}
+
+// ApplySpread widens a rate against the customer. rate is units of the
+// account currency per unit of the instrument currency: buying the
+// instrument costs more account currency, selling it returns less.
+func ApplySpread(rate, spreadBps float64, buy bool) float64 {
+	if buy {
+		return rate * (1 + spreadBps/10000)
+	}
+	return rate * (1 - spreadBps/10000)
+}
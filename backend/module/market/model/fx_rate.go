@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FXRateCollection is the MongoDB collection name for historical FX rates.
+const FXRateCollection = "fx_rates"
+
+// FXRate is an exchange rate as fetched at a point in time: one unit of
+// Base buys Rate units of Quote.
+type FXRate struct {
+	ID    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Base  string             `bson:"base" json:"base"`
+	Quote string             `bson:"quote" json:"quote"`
+	Rate  float64            `bson:"rate" json:"rate"`
+	AsOf  time.Time          `bson:"asOf" json:"asOf"`
+}
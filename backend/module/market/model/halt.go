@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TradingHaltCollection is the MongoDB collection name for trading halts.
+const TradingHaltCollection = "trading_halts"
+
+// HaltScope is what a halt applies to.
+type HaltScope string
+
+const (
+	HaltScopeSymbol   HaltScope = "SYMBOL"   // A single instrument
+	HaltScopeExchange HaltScope = "EXCHANGE" // Every instrument on an exchange
+)
+
+// HaltAction is what happens to resting orders when trading halts.
+type HaltAction string
+
+const (
+	HaltActionCancel  HaltAction = "CANCEL"  // Cancel resting orders
+	HaltActionSuspend HaltAction = "SUSPEND" // Suspend, restore on resume
+)
+
+// TradingHalt records an admin halt of a symbol or exchange.
+// Resumed halts are kept as an audit trail.
+type TradingHalt struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Scope          HaltScope           `bson:"scope" json:"scope"`
+	Target         string              `bson:"target" json:"target"` // Symbol or exchange code
+	Reason         string              `bson:"reason" json:"reason"`
+	Action         HaltAction          `bson:"action" json:"action"`
+	Active         bool                `bson:"active" json:"active"`
+	AffectedOrders int                 `bson:"affectedOrders" json:"affectedOrders"`
+	HaltedBy       primitive.ObjectID  `bson:"haltedBy" json:"haltedBy"`
+	HaltedAt       time.Time           `bson:"haltedAt" json:"haltedAt"`
+	ResumedBy      *primitive.ObjectID `bson:"resumedBy,omitempty" json:"resumedBy,omitempty"`
+	ResumedAt      *time.Time          `bson:"resumedAt,omitempty" json:"resumedAt,omitempty"`
+	ResumeReason   string              `bson:"resumeReason,omitempty" json:"resumeReason,omitempty"`
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FXRateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewFXRateRepository() *FXRateRepository {
+	return &FXRateRepository{
+		collection: database.GetCollection(model.FXRateCollection),
+	}
+}
+
+func (r *FXRateRepository) Create(ctx context.Context, rate *model.FXRate) error {
+	result, err := r.collection.InsertOne(ctx, rate)
+	if err != nil {
+		return err
+	}
+	rate.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindAt returns the latest rate for a pair recorded at or before at
+func (r *FXRateRepository) FindAt(ctx context.Context, base, quote string, at time.Time) (*model.FXRate, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "asOf", Value: -1}})
+
+	var rate model.FXRate
+	err := r.collection.FindOne(ctx, bson.M{
+		"base":  base,
+		"quote": quote,
+		"asOf":  bson.M{"$lte": at},
+	}, opts).Decode(&rate)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// FindHistory returns the rates recorded for a pair in [from, to], oldest first
+func (r *FXRateRepository) FindHistory(ctx context.Context, base, quote string, from, to time.Time, limit int) ([]model.FXRate, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "asOf", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"base":  base,
+		"quote": quote,
+		"asOf":  bson.M{"$gte": from, "$lte": to},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rates []model.FXRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
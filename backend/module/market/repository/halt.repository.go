@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type HaltRepository struct {
+	collection *mongo.Collection
+}
+
+func NewHaltRepository() *HaltRepository {
+	return &HaltRepository{
+		collection: database.GetCollection(model.TradingHaltCollection),
+	}
+}
+
+// EnsureIndexes lets only one halt per scope and target be active, so
+// two admins halting the same target at once can't both succeed
+func (r *HaltRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "scope", Value: 1}, {Key: "target", Value: 1}},
+		Options: options.Index().
+			SetName("active_halt").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"active": true}),
+	})
+	return err
+}
+
+// Create records an active halt. It fails with a duplicate key error if
+// the target is already halted.
+func (r *HaltRepository) Create(ctx context.Context, halt *model.TradingHalt) error {
+	halt.HaltedAt = time.Now()
+	halt.Active = true
+
+	result, err := r.collection.InsertOne(ctx, halt)
+	if err != nil {
+		return err
+	}
+
+	halt.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindActive returns the active halt for a scope and target
+func (r *HaltRepository) FindActive(ctx context.Context, scope model.HaltScope, target string) (*model.TradingHalt, error) {
+	var halt model.TradingHalt
+	err := r.collection.FindOne(ctx, bson.M{
+		"scope":  scope,
+		"target": target,
+		"active": true,
+	}).Decode(&halt)
+	if err != nil {
+		return nil, err
+	}
+	return &halt, nil
+}
+
+// FindActiveFor returns the active halt covering a symbol or its exchange
+func (r *HaltRepository) FindActiveFor(ctx context.Context, symbol, exchange string) (*model.TradingHalt, error) {
+	var halt model.TradingHalt
+	err := r.collection.FindOne(ctx, bson.M{
+		"active": true,
+		"$or": []bson.M{
+			{"scope": model.HaltScopeSymbol, "target": symbol},
+			{"scope": model.HaltScopeExchange, "target": exchange},
+		},
+	}).Decode(&halt)
+	if err != nil {
+		return nil, err
+	}
+	return &halt, nil
+}
+
+func (r *HaltRepository) FindAllActive(ctx context.Context) ([]model.TradingHalt, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "haltedAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"active": true}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var halts []model.TradingHalt
+	if err := cursor.All(ctx, &halts); err != nil {
+		return nil, err
+	}
+	return halts, nil
+}
+
+func (r *HaltRepository) UpdateAffectedOrders(ctx context.Context, id primitive.ObjectID, count int) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$set": bson.M{"affectedOrders": count},
+	})
+	return err
+}
+
+// Resume ends an active halt. Returns false if it was no longer active,
+// e.g. another admin resumed it first.
+func (r *HaltRepository) Resume(ctx context.Context, id, resumedBy primitive.ObjectID, reason string) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "active": true}, bson.M{
+		"$set": bson.M{
+			"active":       false,
+			"resumedBy":    resumedBy,
+			"resumedAt":    time.Now(),
+			"resumeReason": reason,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"context"
+	"log"
+
+	authModel "github.com/bricksocoolxd/bengi-investment-system/module/auth/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/controller"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/config"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterRoutes(app *fiber.App) {
+	// Wire up dependencies
+	haltRepo := repository.NewHaltRepository()
+	if err := haltRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("⚠️ Failed to create trading halt indexes, concurrent halts are not prevented: %v", err)
+	}
+	marketSvc := service.NewMarketService(calendar.Default(), haltRepo)
+	haltSvc := service.NewHaltService(haltRepo, calendar.Default())
+	ctrl := controller.NewMarketController(marketSvc, haltSvc)
+
+	// Public routes (no auth required)
+	markets := app.Group("/api/v1/markets")
+
+	markets.Get("/", ctrl.GetMarkets)
+	markets.Get("/halts", ctrl.GetHalts)
+	markets.Get("/:exchange/status", ctrl.GetMarketStatus)
+
+	// Admin routes (auth + admin role required)
+	admin := markets.Group("", middleware.AuthRequired(), middleware.RoleRequired(authModel.RoleAdmin))
+	admin.Post("/symbols/:symbol/halt", ctrl.HaltSymbol)
+	admin.Post("/symbols/:symbol/resume", ctrl.ResumeSymbol)
+	admin.Post("/:exchange/halt", ctrl.HaltExchange)
+	admin.Post("/:exchange/resume", ctrl.ResumeExchange)
+
+	// FX rates (public). The rate cache is shared with settlement, so
+	// its provider is plugged in here for every module.
+	fxSvc := service.NewFXService(repository.NewFXRateRepository(), fx.Default())
+	fxSvc.Init(config.AppConfig.FXProvider, config.AppConfig.FXSpreadBps)
+	fxCtrl := controller.NewFXController(fxSvc)
+
+	rates := app.Group("/api/v1/fx/rates")
+	rates.Get("/:base/:quote", fxCtrl.GetRate)
+	rates.Get("/:base/:quote/history", fxCtrl.GetHistory)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	instrumentService "github.com/bricksocoolxd/bengi-investment-system/module/instrument/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/repository"
+)
+
+// FX rate sources selectable with FX_PROVIDER
+const (
+	FXProviderStatic = "static" // Built-in reference rates
+	FXProviderMarket = "market" // Live quotes, falling back to the built-in rates
+)
+
+// maxFXHistory caps how many historical rates one request returns
+const maxFXHistory = 1000
+
+var (
+	ErrFXRateNotFound = errors.New("fx rate not found")
+)
+
+// FXService configures the shared rate cache and keeps the history of
+// every rate it fetches
+type FXService struct {
+	repo  *repository.FXRateRepository
+	rates *fx.Cache
+}
+
+func NewFXService(repo *repository.FXRateRepository, rates *fx.Cache) *FXService {
+	return &FXService{
+		repo:  repo,
+		rates: rates,
+	}
+}
+
+// Init plugs the configured provider and fill spread into the rate
+// cache and starts recording fetched rates
+func (s *FXService) Init(provider string, spreadBps float64) {
+	s.rates.SetSpread(spreadBps)
+	if provider == FXProviderMarket {
+		s.rates.SetSource(fx.FallbackProvider{
+			marketRateProvider(instrumentService.NewMarketDataService()),
+			fx.Builtin(),
+		})
+	}
+	s.rates.OnFetch(s.record)
+	log.Printf("💱 FX rates from %s provider, %.1f bps fill spread", provider, spreadBps)
+}
+
+// marketRateProvider quotes the latest hourly close of the pair's Yahoo
+// Finance FX symbol, e.g. USDTHB=X
+func marketRateProvider(marketData *instrumentService.MarketDataService) fx.Provider {
+	return fx.ProviderFunc(func(base, quote string) (float64, error) {
+		now := time.Now()
+		candles, err := marketData.GetYahooFinanceCandles(base+quote+"=X", "60", now.Add(-72*time.Hour).Unix(), now.Unix())
+		if err != nil {
+			return 0, err
+		}
+		for i := len(candles) - 1; i >= 0; i-- {
+			if candles[i].Close > 0 {
+				return candles[i].Close, nil
+			}
+		}
+		return 0, fx.ErrRateUnavailable
+	})
+}
+
+// record saves a fetched rate to the history table
+func (s *FXService) record(base, quote string, rate float64, at time.Time) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.repo.Create(ctx, &model.FXRate{Base: base, Quote: quote, Rate: rate, AsOf: at}); err != nil {
+			log.Printf("⚠️ Failed to record %s/%s rate: %v", base, quote, err)
+		}
+	}()
+}
+
+// GetRate returns the current rate for a pair, or the rate in effect at
+// a past time if at is set
+func (s *FXService) GetRate(ctx context.Context, base, quote string, at *time.Time) (*dto.FXRateResponse, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+
+	if at == nil {
+		rate, err := s.rates.Rate(base, quote)
+		if err != nil {
+			return nil, ErrFXRateNotFound
+		}
+		return &dto.FXRateResponse{
+			Base:  base,
+			Quote: quote,
+			Rate:  rate,
+			AsOf:  time.Now().Format(time.RFC3339),
+		}, nil
+	}
+
+	rate, err := s.repo.FindAt(ctx, base, quote, *at)
+	if err != nil {
+		return nil, ErrFXRateNotFound
+	}
+	return s.toFXRateResponse(rate), nil
+}
+
+// GetHistory returns the rates recorded for a pair over a period
+func (s *FXService) GetHistory(ctx context.Context, base, quote string, from, to time.Time) ([]dto.FXRateResponse, error) {
+	rates, err := s.repo.FindHistory(ctx, strings.ToUpper(base), strings.ToUpper(quote), from, to, maxFXHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.FXRateResponse, 0, len(rates))
+	for i := range rates {
+		responses = append(responses, *s.toFXRateResponse(&rates[i]))
+	}
+	return responses, nil
+}
+
+// Helper: Convert FXRate to FXRateResponse
+func (s *FXService) toFXRateResponse(rate *model.FXRate) *dto.FXRateResponse {
+	return &dto.FXRateResponse{
+		Base:  rate.Base,
+		Quote: rate.Quote,
+		Rate:  rate.Rate,
+		AsOf:  rate.AsOf.Format(time.RFC3339),
+	}
+}
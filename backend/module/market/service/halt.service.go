@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	accountRepo "github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	accountService "github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	instrumentRepo "github.com/bricksocoolxd/bengi-investment-system/module/instrument/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/repository"
+	orderModel "github.com/bricksocoolxd/bengi-investment-system/module/order/model"
+	orderRepo "github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
+	orderService "github.com/bricksocoolxd/bengi-investment-system/module/order/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrInstrumentNotFound = errors.New("instrument not found")
+	ErrAlreadyHalted      = errors.New("trading is already halted")
+	ErrNotHalted          = errors.New("trading is not halted")
+)
+
+// Trading status values broadcast on the status topic
+const (
+	StatusHalted  = "HALTED"
+	StatusTrading = "TRADING"
+)
+
+// restingStatuses are the order states a halt acts on
+var restingStatuses = []orderModel.OrderStatus{
+	orderModel.OrderStatusPending,
+	orderModel.OrderStatusOpen,
+	orderModel.OrderStatusPartiallyFilled,
+}
+
+type HaltService struct {
+	haltRepo       *repository.HaltRepository
+	orderRepo      *orderRepo.OrderRepository
+	accountRepo    *accountRepo.AccountRepository
+	instrumentRepo *instrumentRepo.InstrumentRepository
+	calendar       *calendar.Calendar
+	engine         *matcher.Engine
+}
+
+func NewHaltService(haltRepo *repository.HaltRepository, cal *calendar.Calendar) *HaltService {
+	return &HaltService{
+		haltRepo:       haltRepo,
+		orderRepo:      orderRepo.NewOrderRepository(),
+		accountRepo:    accountRepo.NewAccountRepository(),
+		instrumentRepo: instrumentRepo.NewInstrumentRepository(),
+		calendar:       cal,
+		engine:         matcher.Default(),
+	}
+}
+
+// HaltSymbol halts trading on a single instrument
+func (s *HaltService) HaltSymbol(ctx context.Context, symbol, adminID string, req *dto.HaltRequest) (*dto.HaltResponse, error) {
+	instrument, err := s.instrumentRepo.FindBySymbol(ctx, strings.ToUpper(symbol))
+	if err != nil {
+		return nil, ErrInstrumentNotFound
+	}
+	return s.halt(ctx, model.HaltScopeSymbol, instrument.Symbol, adminID, req)
+}
+
+// HaltExchange halts trading on every instrument of an exchange
+func (s *HaltService) HaltExchange(ctx context.Context, exchange, adminID string, req *dto.HaltRequest) (*dto.HaltResponse, error) {
+	ex, ok := s.calendar.Exchange(exchange)
+	if !ok {
+		return nil, ErrExchangeNotFound
+	}
+	return s.halt(ctx, model.HaltScopeExchange, ex.Code, adminID, req)
+}
+
+// ResumeSymbol lifts the halt on an instrument
+func (s *HaltService) ResumeSymbol(ctx context.Context, symbol, adminID string, req *dto.ResumeRequest) (*dto.HaltResponse, error) {
+	return s.resume(ctx, model.HaltScopeSymbol, strings.ToUpper(symbol), adminID, req)
+}
+
+// ResumeExchange lifts the halt on an exchange
+func (s *HaltService) ResumeExchange(ctx context.Context, exchange, adminID string, req *dto.ResumeRequest) (*dto.HaltResponse, error) {
+	ex, ok := s.calendar.Exchange(exchange)
+	if !ok {
+		return nil, ErrExchangeNotFound
+	}
+	return s.resume(ctx, model.HaltScopeExchange, ex.Code, adminID, req)
+}
+
+// GetActiveHalts returns every halt currently in force
+func (s *HaltService) GetActiveHalts(ctx context.Context) ([]dto.HaltResponse, error) {
+	halts, err := s.haltRepo.FindAllActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.HaltResponse, 0, len(halts))
+	for i := range halts {
+		responses = append(responses, *s.toHaltResponse(&halts[i]))
+	}
+	return responses, nil
+}
+
+func (s *HaltService) halt(ctx context.Context, scope model.HaltScope, target, adminID string, req *dto.HaltRequest) (*dto.HaltResponse, error) {
+	if existing, _ := s.haltRepo.FindActive(ctx, scope, target); existing != nil {
+		return nil, ErrAlreadyHalted
+	}
+
+	adminObjectID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	action := model.HaltActionSuspend
+	newStatus := orderModel.OrderStatusSuspended
+	if req.CancelOrders {
+		action = model.HaltActionCancel
+		newStatus = orderModel.OrderStatusCancelled
+	}
+
+	halt := &model.TradingHalt{
+		Scope:    scope,
+		Target:   target,
+		Reason:   req.Reason,
+		Action:   action,
+		HaltedBy: adminObjectID,
+	}
+	if err := s.haltRepo.Create(ctx, halt); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrAlreadyHalted
+		}
+		return nil, err
+	}
+
+	// New orders are refused from here on; now deal with the resting ones
+	orders, err := s.affectedOrders(ctx, scope, target, restingStatuses)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		if err := s.orderRepo.UpdateStatus(ctx, orders[i].ID, newStatus); err != nil {
+			return nil, err
+		}
+		s.engine.CancelOrder(orders[i].Symbol, orders[i].ID.Hex())
+		if newStatus == orderModel.OrderStatusCancelled {
+			if _, err := accountService.ReleaseOrderCash(ctx, s.orderRepo, s.accountRepo, &orders[i], -1); err != nil {
+				return nil, err
+			}
+		}
+		publishOrderStatus(&orders[i], newStatus)
+	}
+
+	halt.AffectedOrders = len(orders)
+	if err := s.haltRepo.UpdateAffectedOrders(ctx, halt.ID, halt.AffectedOrders); err != nil {
+		return nil, err
+	}
+
+	ws.PublishStatus(&ws.StatusPayload{
+		Scope:  string(scope),
+		Target: target,
+		Status: StatusHalted,
+		Reason: req.Reason,
+	})
+
+	return s.toHaltResponse(halt), nil
+}
+
+func (s *HaltService) resume(ctx context.Context, scope model.HaltScope, target, adminID string, req *dto.ResumeRequest) (*dto.HaltResponse, error) {
+	halt, err := s.haltRepo.FindActive(ctx, scope, target)
+	if err != nil {
+		return nil, ErrNotHalted
+	}
+
+	adminObjectID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	resumed, err := s.haltRepo.Resume(ctx, halt.ID, adminObjectID, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, ErrNotHalted
+	}
+
+	// Restore suspended orders unless another halt still covers them
+	orders, err := s.affectedOrders(ctx, scope, target, []orderModel.OrderStatus{orderModel.OrderStatusSuspended})
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		if other, _ := s.haltRepo.FindActiveFor(ctx, orders[i].Symbol, s.exchangeFor(ctx, orders[i].Symbol)); other != nil {
+			continue
+		}
+
+		status := orderModel.OrderStatusPending
+		if orders[i].FilledQty > 0 {
+			status = orderModel.OrderStatusPartiallyFilled
+		}
+		if err := s.orderRepo.UpdateStatus(ctx, orders[i].ID, status); err != nil {
+			return nil, err
+		}
+		publishOrderStatus(&orders[i], status)
+		if orderService.RestsInBook(&orders[i]) {
+			s.engine.AddOrder(orderService.ToEngineOrder(&orders[i]))
+		}
+	}
+
+	ws.PublishStatus(&ws.StatusPayload{
+		Scope:  string(scope),
+		Target: target,
+		Status: StatusTrading,
+		Reason: req.Reason,
+	})
+
+	now := time.Now()
+	halt.Active = false
+	halt.ResumedBy = &adminObjectID
+	halt.ResumedAt = &now
+	halt.ResumeReason = req.Reason
+	return s.toHaltResponse(halt), nil
+}
+
+// affectedOrders returns orders in the given statuses covered by a halt target
+func (s *HaltService) affectedOrders(ctx context.Context, scope model.HaltScope, target string, statuses []orderModel.OrderStatus) ([]orderModel.Order, error) {
+	if scope == model.HaltScopeSymbol {
+		return s.orderRepo.FindResting(ctx, []string{target}, statuses)
+	}
+
+	// Orders only carry a symbol, so resolve each symbol's exchange
+	orders, err := s.orderRepo.FindResting(ctx, nil, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	exchanges := make(map[string]string)
+	var affected []orderModel.Order
+	for _, order := range orders {
+		exchange, ok := exchanges[order.Symbol]
+		if !ok {
+			exchange = s.exchangeFor(ctx, order.Symbol)
+			exchanges[order.Symbol] = exchange
+		}
+		if exchange == target {
+			affected = append(affected, order)
+		}
+	}
+	return affected, nil
+}
+
+// exchangeFor returns the calendar code of a symbol's exchange
+func (s *HaltService) exchangeFor(ctx context.Context, symbol string) string {
+	instrument, err := s.instrumentRepo.FindBySymbol(ctx, symbol)
+	if err != nil {
+		return ""
+	}
+	return s.calendar.Canonical(calendar.ExchangeForInstrument(instrument.Exchange, string(instrument.Type)))
+}
+
+func publishOrderStatus(order *orderModel.Order, status orderModel.OrderStatus) {
+	ws.PublishOrderUpdate(order.UserID.Hex(), &ws.OrderPayload{
+		OrderID:   order.ID.Hex(),
+		Symbol:    order.Symbol,
+		Side:      string(order.Side),
+		Status:    string(status),
+		FilledQty: order.FilledQty,
+		AvgPrice:  order.AvgFillPrice,
+	})
+}
+
+// Helper: Convert TradingHalt to HaltResponse
+func (s *HaltService) toHaltResponse(halt *model.TradingHalt) *dto.HaltResponse {
+	resp := &dto.HaltResponse{
+		ID:             halt.ID.Hex(),
+		Scope:          string(halt.Scope),
+		Target:         halt.Target,
+		Reason:         halt.Reason,
+		Action:         string(halt.Action),
+		Active:         halt.Active,
+		AffectedOrders: halt.AffectedOrders,
+		HaltedBy:       halt.HaltedBy.Hex(),
+		HaltedAt:       halt.HaltedAt.Format(time.RFC3339),
+		ResumeReason:   halt.ResumeReason,
+	}
+
+	if halt.ResumedAt != nil {
+		t := halt.ResumedAt.Format(time.RFC3339)
+		resp.ResumedAt = &t
+	}
+
+	return resp
+}
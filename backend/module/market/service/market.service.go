@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/repository"
+)
+
+var (
+	ErrExchangeNotFound = errors.New("exchange not found")
+)
+
+type MarketService struct {
+	calendar *calendar.Calendar
+	haltRepo *repository.HaltRepository
+}
+
+func NewMarketService(cal *calendar.Calendar, haltRepo *repository.HaltRepository) *MarketService {
+	return &MarketService{
+		calendar: cal,
+		haltRepo: haltRepo,
+	}
+}
+
+// GetStatus returns the current session state of an exchange
+func (s *MarketService) GetStatus(ctx context.Context, exchange string) (*dto.MarketStatusResponse, error) {
+	status, err := s.calendar.Status(exchange, time.Now())
+	if err != nil {
+		return nil, ErrExchangeNotFound
+	}
+
+	return s.toStatusResponse(ctx, status), nil
+}
+
+// GetAllStatuses returns the current session state of every known exchange
+func (s *MarketService) GetAllStatuses(ctx context.Context) []dto.MarketStatusResponse {
+	now := time.Now()
+
+	var responses []dto.MarketStatusResponse
+	for _, exchange := range s.calendar.Exchanges() {
+		status, err := s.calendar.Status(exchange.Code, now)
+		if err != nil {
+			continue
+		}
+		responses = append(responses, *s.toStatusResponse(ctx, status))
+	}
+	return responses
+}
+
+// Helper: Convert calendar.Status to MarketStatusResponse
+func (s *MarketService) toStatusResponse(ctx context.Context, status *calendar.Status) *dto.MarketStatusResponse {
+	resp := &dto.MarketStatusResponse{
+		Exchange:   status.Exchange,
+		Name:       status.Name,
+		TimeZone:   status.TimeZone,
+		Phase:      string(status.Phase),
+		IsOpen:     status.IsOpen(),
+		LocalTime:  status.LocalTime.Format(time.RFC3339),
+		Holiday:    status.Holiday,
+		EarlyClose: status.EarlyClose,
+	}
+
+	if !status.NextOpen.IsZero() {
+		t := status.NextOpen.Format(time.RFC3339)
+		resp.NextOpen = &t
+	}
+	if !status.NextClose.IsZero() {
+		t := status.NextClose.Format(time.RFC3339)
+		resp.NextClose = &t
+	}
+	if halt, _ := s.haltRepo.FindActive(ctx, model.HaltScopeExchange, status.Exchange); halt != nil {
+		resp.Halted = true
+		resp.HaltReason = halt.Reason
+	}
+
+	return resp
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"errors"
 
+	instrumentModel "github.com/bricksocoolxd/bengi-investment-system/module/instrument/model"
 	"github.com/bricksocoolxd/bengi-investment-system/module/order/dto"
 	"github.com/bricksocoolxd/bengi-investment-system/module/order/service"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
@@ -36,12 +37,52 @@ func (ctrl *OrderController) CreateOrder(c *fiber.Ctx) error {
 
 	result, err := ctrl.orderService.CreateOrder(c.Context(), userID, &req)
 	if err != nil {
+		var violation *instrumentModel.RuleViolation
+		if errors.As(err, &violation) {
+			return common.ErrorWithDetail(c, fiber.StatusBadRequest, violation)
+		}
 		if errors.Is(err, service.ErrInvalidOrderType) {
 			return common.BadRequest(c, "Invalid order: LIMIT orders require price, STOP orders require stopPrice")
 		}
 		if errors.Is(err, service.ErrInsufficientBalance) {
 			return common.BadRequest(c, "Insufficient balance")
 		}
+		if errors.Is(err, service.ErrNoFXRate) {
+			return common.BadRequest(c, "No exchange rate between the instrument and account currencies")
+		}
+		if errors.Is(err, service.ErrMarketClosed) {
+			return common.BadRequest(c, "Market is closed: only LIMIT and STOP orders can be queued until the open")
+		}
+		if errors.Is(err, service.ErrOutsideRegularHours) {
+			return common.BadRequest(c, "Market is in extended hours: set extendedHours on a LIMIT order to trade now")
+		}
+		if errors.Is(err, service.ErrExtendedHoursLimit) {
+			return common.BadRequest(c, "Extended-hours trading is only available for LIMIT orders")
+		}
+		if errors.Is(err, service.ErrReserveLimitOnly) {
+			return common.BadRequest(c, "Iceberg and hidden orders must be LIMIT orders")
+		}
+		if errors.Is(err, service.ErrInvalidDisplayQty) {
+			return common.BadRequest(c, "displayQuantity must be below quantity and cannot be combined with hidden")
+		}
+		if errors.Is(err, service.ErrInstrumentNotFound) {
+			return common.NotFound(c, "Instrument not found")
+		}
+		if errors.Is(err, service.ErrInstrumentInactive) {
+			return common.BadRequest(c, "Instrument is not active for trading")
+		}
+		if errors.Is(err, service.ErrInstrumentDelisted) {
+			return common.BadRequest(c, "Instrument has been delisted")
+		}
+		if errors.Is(err, service.ErrTradingHalted) {
+			return common.BadRequest(c, "Trading is halted for this instrument")
+		}
+		if errors.Is(err, service.ErrAccountFrozen) {
+			return common.BadRequest(c, "Account is frozen")
+		}
+		if errors.Is(err, service.ErrAccountClosed) {
+			return common.BadRequest(c, "Account is closed")
+		}
 		return common.InternalError(c, err.Error())
 	}
 
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/order/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/order/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ParentOrderController struct {
+	parentOrderService *service.ParentOrderService
+}
+
+func NewParentOrderController(parentOrderService *service.ParentOrderService) *ParentOrderController {
+	return &ParentOrderController{
+		parentOrderService: parentOrderService,
+	}
+}
+
+// CreateParentOrder starts a TWAP or VWAP parent order
+// POST /api/v1/parent-orders
+func (ctrl *ParentOrderController) CreateParentOrder(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+
+	var req dto.CreateParentOrderRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.parentOrderService.CreateParentOrder(c.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWindow) {
+			return common.BadRequest(c, "Invalid window: endTime must be in the future, after startTime, and at most 24 hours after it")
+		}
+		if errors.Is(err, service.ErrInstrumentNotFound) {
+			return common.NotFound(c, "Instrument not found")
+		}
+		if errors.Is(err, service.ErrUnauthorized) {
+			return common.Unauthorized(c, "Access denied")
+		}
+		if errors.Is(err, service.ErrAccountFrozen) {
+			return common.BadRequest(c, "Account is frozen")
+		}
+		if errors.Is(err, service.ErrAccountClosed) {
+			return common.BadRequest(c, "Account is closed")
+		}
+		return common.InternalError(c, err.Error())
+	}
+
+	return common.Created(c, result, "Parent order created successfully")
+}
+
+// GetParentOrders returns all parent orders for current user
+// GET /api/v1/parent-orders
+func (ctrl *ParentOrderController) GetParentOrders(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+
+	result, err := ctrl.parentOrderService.GetParentOrders(c.Context(), userID)
+	if err != nil {
+		return common.InternalError(c, err.Error())
+	}
+
+	return common.Success(c, result, "")
+}
+
+// GetParentOrderByID returns a parent order with its progress
+// GET /api/v1/parent-orders/:id
+func (ctrl *ParentOrderController) GetParentOrderByID(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+
+	result, err := ctrl.parentOrderService.GetParentOrder(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		return parentOrderError(c, err)
+	}
+
+	return common.Success(c, result, "")
+}
+
+// PauseParentOrder stops sending slices
+// POST /api/v1/parent-orders/:id/pause
+func (ctrl *ParentOrderController) PauseParentOrder(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+
+	result, err := ctrl.parentOrderService.PauseParentOrder(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		return parentOrderError(c, err)
+	}
+
+	return common.Success(c, result, "Parent order paused")
+}
+
+// ResumeParentOrder continues a paused parent order
+// POST /api/v1/parent-orders/:id/resume
+func (ctrl *ParentOrderController) ResumeParentOrder(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+
+	result, err := ctrl.parentOrderService.ResumeParentOrder(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		return parentOrderError(c, err)
+	}
+
+	return common.Success(c, result, "Parent order resumed")
+}
+
+// CancelParentOrder stops a parent order for good
+// POST /api/v1/parent-orders/:id/cancel
+func (ctrl *ParentOrderController) CancelParentOrder(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+
+	result, err := ctrl.parentOrderService.CancelParentOrder(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		return parentOrderError(c, err)
+	}
+
+	return common.Success(c, result, "Parent order cancelled successfully")
+}
+
+// parentOrderError maps errors shared by the single parent order routes
+func parentOrderError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrParentOrderNotFound):
+		return common.NotFound(c, "Parent order not found")
+	case errors.Is(err, service.ErrUnauthorized):
+		return common.Unauthorized(c, "Access denied")
+	case errors.Is(err, service.ErrParentOrderState):
+		return common.BadRequest(c, "Parent order cannot do that in its current status")
+	}
+	return common.InternalError(c, err.Error())
+}
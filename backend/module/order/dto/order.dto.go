@@ -39,30 +39,44 @@ type CreateOrderRequest struct {
 	Price       float64 `json:"price" validate:"omitempty,gt=0"`     // Required for LIMIT orders
 	StopPrice   float64 `json:"stopPrice" validate:"omitempty,gt=0"` // Required for STOP orders
 	TimeInForce string  `json:"timeInForce" validate:"omitempty,oneof=GTC DAY IOC FOK"`
+	// ExtendedHours lets a LIMIT order execute pre-market and after-hours
+	ExtendedHours bool `json:"extendedHours"`
+	// SelfTradeMode overrides the account's self-trade prevention mode
+	SelfTradeMode string `json:"selfTradeMode" validate:"omitempty,oneof=CANCEL_NEWEST CANCEL_OLDEST CANCEL_BOTH DECREMENT_AND_CANCEL"`
+	// DisplayQuantity makes a LIMIT order an iceberg that shows only this
+	// much of its size at a time
+	DisplayQuantity float64 `json:"displayQuantity" validate:"omitempty,gt=0"`
+	// Hidden keeps a LIMIT order out of the public depth entirely
+	Hidden bool `json:"hidden"`
 }
 
 // OrderResponse represents a complete order with all its details.
 // Used when returning order data to clients.
 type OrderResponse struct {
-	ID           string  `json:"id"`
-	UserID       string  `json:"userId"`
-	AccountID    string  `json:"accountId"`
-	PortfolioID  string  `json:"portfolioId"`
-	InstrumentID string  `json:"instrumentId"`
-	Symbol       string  `json:"symbol"`
-	Side         string  `json:"side"`
-	Type         string  `json:"type"`
-	Status       string  `json:"status"`
-	TimeInForce  string  `json:"timeInForce"`
-	Quantity     float64 `json:"quantity"`
-	FilledQty    float64 `json:"filledQty"`              // Partially filled amount
-	Price        float64 `json:"price,omitempty"`        // Limit price if applicable
-	StopPrice    float64 `json:"stopPrice,omitempty"`    // Stop trigger price
-	AvgFillPrice float64 `json:"avgFillPrice,omitempty"` // Weighted average of all fills
-	Commission   float64 `json:"commission"`
-	CreatedAt    string  `json:"createdAt"`
-	FilledAt     *string `json:"filledAt,omitempty"`
-	CancelledAt  *string `json:"cancelledAt,omitempty"`
+	ID            string  `json:"id"`
+	UserID        string  `json:"userId"`
+	AccountID     string  `json:"accountId"`
+	PortfolioID   string  `json:"portfolioId"`
+	InstrumentID  string  `json:"instrumentId"`
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	Type          string  `json:"type"`
+	Status        string  `json:"status"`
+	TimeInForce   string  `json:"timeInForce"`
+	ExtendedHours bool    `json:"extendedHours"`
+	SelfTradeMode string  `json:"selfTradeMode,omitempty"`
+	Quantity      float64 `json:"quantity"`
+	DisplayQty    float64 `json:"displayQuantity,omitempty"` // Iceberg slice size
+	Hidden        bool    `json:"hidden,omitempty"`
+	FilledQty     float64 `json:"filledQty"`              // Partially filled amount
+	CancelledQty  float64 `json:"cancelledQty,omitempty"` // Cancelled by self-trade prevention
+	Price         float64 `json:"price,omitempty"`        // Limit price if applicable
+	StopPrice     float64 `json:"stopPrice,omitempty"`    // Stop trigger price
+	AvgFillPrice  float64 `json:"avgFillPrice,omitempty"` // Weighted average of all fills
+	Commission    float64 `json:"commission"`
+	CreatedAt     string  `json:"createdAt"`
+	FilledAt      *string `json:"filledAt,omitempty"`
+	CancelledAt   *string `json:"cancelledAt,omitempty"`
 }
 
 // OrderListResponse wraps a paginated list of orders.
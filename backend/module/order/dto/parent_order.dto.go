@@ -0,0 +1,44 @@
+package dto
+
+// CreateParentOrderRequest starts an algorithmic order that is worked
+// over a time window in child orders.
+type CreateParentOrderRequest struct {
+	AccountID   string  `json:"accountId" validate:"required"`
+	PortfolioID string  `json:"portfolioId" validate:"required"`
+	Symbol      string  `json:"symbol" validate:"required"`
+	Side        string  `json:"side" validate:"required,oneof=BUY SELL"`
+	Strategy    string  `json:"strategy" validate:"required,oneof=TWAP VWAP"`
+	Quantity    float64 `json:"quantity" validate:"required,gt=0"`
+	LimitPrice  float64 `json:"limitPrice" validate:"omitempty,gt=0"` // Children never trade through it; empty sends MARKET children
+	StartTime   string  `json:"startTime"`                            // RFC3339, defaults to now
+	EndTime     string  `json:"endTime" validate:"required"`          // RFC3339
+	Slices      int     `json:"slices" validate:"omitempty,min=1,max=500"`
+}
+
+// ParentOrderResponse is a parent order with its progress against the
+// schedule and benchmark.
+type ParentOrderResponse struct {
+	ID             string   `json:"id"`
+	AccountID      string   `json:"accountId"`
+	PortfolioID    string   `json:"portfolioId"`
+	Symbol         string   `json:"symbol"`
+	Side           string   `json:"side"`
+	Strategy       string   `json:"strategy"`
+	Status         string   `json:"status"`
+	Quantity       float64  `json:"quantity"`
+	LimitPrice     float64  `json:"limitPrice,omitempty"`
+	StartTime      string   `json:"startTime"`
+	EndTime        string   `json:"endTime"`
+	Slices         int      `json:"slices"`
+	SlicesSent     int      `json:"slicesSent"`
+	FilledQty      float64  `json:"filledQty"`
+	AvgPrice       float64  `json:"avgPrice,omitempty"`
+	ProgressPct    float64  `json:"progressPct"`              // Filled share of the total
+	SchedulePct    float64  `json:"schedulePct"`              // Share the schedule has released so far
+	ArrivalPrice   float64  `json:"arrivalPrice,omitempty"`   // Reference price when the first slice went out
+	BenchmarkPrice float64  `json:"benchmarkPrice,omitempty"` // TWAP or VWAP of the market over the slices so far
+	SlippageBps    float64  `json:"slippageBps"`              // Fill price vs benchmark; positive is worse
+	ChildOrderIDs  []string `json:"childOrderIds"`
+	LastError      string   `json:"lastError,omitempty"`
+	CreatedAt      string   `json:"createdAt"`
+}
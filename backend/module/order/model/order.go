@@ -32,6 +32,7 @@ const (
 	OrderStatusCancelled       OrderStatus = "CANCELLED"
 	OrderStatusRejected        OrderStatus = "REJECTED"
 	OrderStatusExpired         OrderStatus = "EXPIRED"
+	OrderStatusSuspended       OrderStatus = "SUSPENDED" // Held by a trading halt
 )
 
 const (
@@ -42,24 +43,48 @@ const (
 )
 
 type Order struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID       primitive.ObjectID `bson:"userId" json:"userId"`
-	AccountID    primitive.ObjectID `bson:"accountId" json:"accountId"`
-	PortfolioID  primitive.ObjectID `bson:"portfolioId" json:"portfolioId"`
-	InstrumentID primitive.ObjectID `bson:"instrumentId" json:"instrumentId"`
-	Symbol       string             `bson:"symbol" json:"symbol"`
-	Side         OrderSide          `bson:"side" json:"side"`
-	Type         OrderType          `bson:"type" json:"type"`
-	Status       OrderStatus        `bson:"status" json:"status"`
-	TimeInForce  TimeInForce        `bson:"timeInForce" json:"timeInForce"`
-	Quantity     float64            `bson:"quantity" json:"quantity"`
-	FilledQty    float64            `bson:"filledQty" json:"filledQty"`
-	Price        float64            `bson:"price,omitempty" json:"price,omitempty"`
-	StopPrice    float64            `bson:"stopPrice,omitempty" json:"stopPrice,omitempty"`
-	AvgFillPrice float64            `bson:"avgFillPrice,omitempty" json:"avgFillPrice,omitempty"`
-	Commission   float64            `bson:"commission" json:"commission"`
-	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
-	FilledAt     *time.Time         `bson:"filledAt,omitempty" json:"filledAt,omitempty"`
-	CancelledAt  *time.Time         `bson:"cancelledAt,omitempty" json:"cancelledAt,omitempty"`
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID         primitive.ObjectID `bson:"userId" json:"userId"`
+	AccountID      primitive.ObjectID `bson:"accountId" json:"accountId"`
+	PortfolioID    primitive.ObjectID `bson:"portfolioId" json:"portfolioId"`
+	InstrumentID   primitive.ObjectID `bson:"instrumentId" json:"instrumentId"`
+	Symbol         string             `bson:"symbol" json:"symbol"`
+	Currency       string             `bson:"currency,omitempty" json:"currency,omitempty"`             // Instrument's trading currency
+	Exchange       string             `bson:"exchange,omitempty" json:"exchange,omitempty"`             // Calendar code its fills settle on
+	SettleDays     int                `bson:"settleDays,omitempty" json:"settleDays,omitempty"`         // Settlement cycle, T+n trading days
+	InstrumentType string             `bson:"instrumentType,omitempty" json:"instrumentType,omitempty"` // Picks the fee schedule
+	Side           OrderSide          `bson:"side" json:"side"`
+	Type           OrderType          `bson:"type" json:"type"`
+	Status         OrderStatus        `bson:"status" json:"status"`
+	TimeInForce    TimeInForce        `bson:"timeInForce" json:"timeInForce"`
+	ExtendedHours  bool               `bson:"extendedHours" json:"extendedHours"`                     // Eligible for pre-market/after-hours
+	SelfTradeMode  string             `bson:"selfTradeMode,omitempty" json:"selfTradeMode,omitempty"` // Self-trade prevention mode; empty uses the engine default
+	Quantity       float64            `bson:"quantity" json:"quantity"`
+	DisplayQty     float64            `bson:"displayQty,omitempty" json:"displayQuantity,omitempty"` // Iceberg slice shown in depth; 0 shows it all
+	Hidden         bool               `bson:"hidden,omitempty" json:"hidden,omitempty"`              // Never shown in depth
+	FilledQty      float64            `bson:"filledQty" json:"filledQty"`
+	CancelledQty   float64            `bson:"cancelledQty,omitempty" json:"cancelledQty,omitempty"` // Removed by the engine without trading
+	Price          float64            `bson:"price,omitempty" json:"price,omitempty"`
+	StopPrice      float64            `bson:"stopPrice,omitempty" json:"stopPrice,omitempty"`
+	AvgFillPrice   float64            `bson:"avgFillPrice,omitempty" json:"avgFillPrice,omitempty"`
+	Commission     float64            `bson:"commission" json:"commission"`
+	RegulatoryFees float64            `bson:"regulatoryFees,omitempty" json:"regulatoryFees,omitempty"`
+	ReservedCash   float64            `bson:"reservedCash,omitempty" json:"reservedCash,omitempty"` // Account cash held for the unfilled part of a resting buy
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+	FilledAt       *time.Time         `bson:"filledAt,omitempty" json:"filledAt,omitempty"`
+	CancelledAt    *time.Time         `bson:"cancelledAt,omitempty" json:"cancelledAt,omitempty"`
+}
+
+// ReservedFor returns the part of the order's cash hold that covers
+// quantity of its open quantity; all of it once quantity closes the order
+func (o *Order) ReservedFor(quantity float64) float64 {
+	open := o.Quantity - o.FilledQty - o.CancelledQty
+	if o.ReservedCash <= 0 || open <= 0 {
+		return 0
+	}
+	if quantity >= open-1e-9 {
+		return o.ReservedCash
+	}
+	return o.ReservedCash * quantity / open
 }
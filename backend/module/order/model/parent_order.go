@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const ParentOrderCollection = "parent_orders"
+
+type ParentStrategy string
+type ParentOrderStatus string
+
+const (
+	ParentStrategyTWAP ParentStrategy = "TWAP" // Equal slices across the window
+	ParentStrategyVWAP ParentStrategy = "VWAP" // Slices follow the intraday volume curve
+)
+
+const (
+	ParentOrderStatusActive    ParentOrderStatus = "ACTIVE"
+	ParentOrderStatusPaused    ParentOrderStatus = "PAUSED"
+	ParentOrderStatusCompleted ParentOrderStatus = "COMPLETED" // Fully filled
+	ParentOrderStatusExpired   ParentOrderStatus = "EXPIRED"   // Window ended before it filled
+	ParentOrderStatusCancelled ParentOrderStatus = "CANCELLED"
+)
+
+// ParentOrder is an algorithmic order worked over a time window by
+// sending child orders. Weights holds each slice's share of the total,
+// fixed when the order is created.
+type ParentOrder struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID   `bson:"userId" json:"userId"`
+	AccountID   primitive.ObjectID   `bson:"accountId" json:"accountId"`
+	PortfolioID primitive.ObjectID   `bson:"portfolioId" json:"portfolioId"`
+	Symbol      string               `bson:"symbol" json:"symbol"`
+	Side        OrderSide            `bson:"side" json:"side"`
+	Strategy    ParentStrategy       `bson:"strategy" json:"strategy"`
+	Status      ParentOrderStatus    `bson:"status" json:"status"`
+	Quantity    float64              `bson:"quantity" json:"quantity"`
+	LimitPrice  float64              `bson:"limitPrice,omitempty" json:"limitPrice,omitempty"` // Children are LIMIT at this price; 0 sends MARKET children
+	StartTime   time.Time            `bson:"startTime" json:"startTime"`
+	EndTime     time.Time            `bson:"endTime" json:"endTime"`
+	Weights     []float64            `bson:"weights" json:"weights"`
+	NextSlice   int                  `bson:"nextSlice" json:"nextSlice"`     // Index of the next slice; len(Weights) once all are sent
+	NextSliceAt time.Time            `bson:"nextSliceAt" json:"nextSliceAt"` // When the scheduler next acts; EndTime after the last slice
+	ChildIDs    []primitive.ObjectID `bson:"childIds" json:"childIds"`
+	FilledQty   float64              `bson:"filledQty" json:"filledQty"`
+	AvgPrice    float64              `bson:"avgPrice,omitempty" json:"avgPrice,omitempty"`
+	// Benchmark is the strategy's market average over the slices so far:
+	// reference prices weighted by slice weight
+	BenchmarkNotional float64   `bson:"benchmarkNotional" json:"-"`
+	BenchmarkWeight   float64   `bson:"benchmarkWeight" json:"-"`
+	ArrivalPrice      float64   `bson:"arrivalPrice,omitempty" json:"arrivalPrice,omitempty"`
+	LastError         string    `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	CreatedAt         time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt         time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// SliceTime returns when slice i is due
+func (p *ParentOrder) SliceTime(i int) time.Time {
+	step := p.EndTime.Sub(p.StartTime) / time.Duration(len(p.Weights))
+	return p.StartTime.Add(step * time.Duration(i))
+}
+
+// BenchmarkPrice returns the benchmark average so far, or 0 before any slice
+func (p *ParentOrder) BenchmarkPrice() float64 {
+	if p.BenchmarkWeight <= 0 {
+		return 0
+	}
+	return p.BenchmarkNotional / p.BenchmarkWeight
+}
+
+// IsTerminal reports whether the parent order is done working
+func (p *ParentOrder) IsTerminal() bool {
+	return p.Status == ParentOrderStatusCompleted ||
+		p.Status == ParentOrderStatusExpired ||
+		p.Status == ParentOrderStatusCancelled
+}
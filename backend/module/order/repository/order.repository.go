@@ -127,6 +127,69 @@ func (r *OrderRepository) UpdateFill(ctx context.Context, id primitive.ObjectID,
 	return err
 }
 
+// AddFees adds a fill's commission and regulatory fees to the order's totals
+func (r *OrderRepository) AddFees(ctx context.Context, id primitive.ObjectID, commission, regulatory float64) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$inc": bson.M{"commission": commission, "regulatoryFees": regulatory},
+		"$set": bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+// ReleaseReservedCash takes up to amount off the cash an order holds, or
+// all of it when amount is negative, and returns how much was released.
+// Concurrent releases never free the same cash twice.
+func (r *OrderRepository) ReleaseReservedCash(ctx context.Context, id primitive.ObjectID, amount float64) (float64, error) {
+	var remaining interface{} = 0
+	if amount >= 0 {
+		remaining = bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$reservedCash", amount}}}}
+	}
+
+	var before model.Order
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "reservedCash": bson.M{"$gt": 0}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"reservedCash": remaining, "updatedAt": time.Now()}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if amount < 0 || amount > before.ReservedCash {
+		return before.ReservedCash, nil
+	}
+	return amount, nil
+}
+
+// AddReservedCash moves the cash an order holds by delta, e.g. to hold a
+// fill's share again when it couldn't be paid
+func (r *OrderRepository) AddReservedCash(ctx context.Context, id primitive.ObjectID, delta float64) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$inc": bson.M{"reservedCash": delta},
+		"$set": bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+// AddCancelledQty records quantity cancelled by the engine. done marks the
+// order CANCELLED once nothing is left working.
+func (r *OrderRepository) AddCancelledQty(ctx context.Context, id primitive.ObjectID, qty float64, done bool) error {
+	set := bson.M{"updatedAt": time.Now()}
+	if done {
+		set["status"] = model.OrderStatusCancelled
+		set["cancelledAt"] = time.Now()
+	}
+
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$inc": bson.M{"cancelledQty": qty},
+		"$set": set,
+	})
+	return err
+}
+
 func (r *OrderRepository) FindOpenOrders(ctx context.Context, portfolioID primitive.ObjectID, symbol string) ([]model.Order, error) {
 	query := bson.M{
 		"portfolioId": portfolioID,
@@ -150,3 +213,51 @@ func (r *OrderRepository) FindOpenOrders(ctx context.Context, portfolioID primit
 	}
 	return orders, nil
 }
+
+// FindWorkingByAccountID returns an account's orders that could still
+// fill, including ones suspended by a halt
+func (r *OrderRepository) FindWorkingByAccountID(ctx context.Context, accountID primitive.ObjectID) ([]model.Order, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"accountId": accountID,
+		"status": bson.M{"$in": []model.OrderStatus{
+			model.OrderStatusPending,
+			model.OrderStatusOpen,
+			model.OrderStatusPartiallyFilled,
+			model.OrderStatusSuspended,
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orders []model.Order
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// FindResting returns orders in the given statuses, optionally limited to
+// symbols, oldest first so books rebuilt from them keep time priority
+func (r *OrderRepository) FindResting(ctx context.Context, symbols []string, statuses []model.OrderStatus) ([]model.Order, error) {
+	query := bson.M{
+		"status": bson.M{"$in": statuses},
+	}
+	if len(symbols) > 0 {
+		query["symbol"] = bson.M{"$in": symbols}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orders []model.Order
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
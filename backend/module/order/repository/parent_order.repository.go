@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/order/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ParentOrderRepository struct {
+	collection *mongo.Collection
+}
+
+func NewParentOrderRepository() *ParentOrderRepository {
+	return &ParentOrderRepository{
+		collection: database.GetCollection(model.ParentOrderCollection),
+	}
+}
+
+func (r *ParentOrderRepository) Create(ctx context.Context, parent *model.ParentOrder) error {
+	parent.ID = primitive.NewObjectID()
+	parent.CreatedAt = time.Now()
+	parent.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, parent)
+	return err
+}
+
+func (r *ParentOrderRepository) FindByID(ctx context.Context, id string) (*model.ParentOrder, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent model.ParentOrder
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&parent); err != nil {
+		return nil, err
+	}
+	return &parent, nil
+}
+
+// FindByUserID returns a user's parent orders, newest first
+func (r *ParentOrderRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]model.ParentOrder, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	parents := make([]model.ParentOrder, 0)
+	if err := cursor.All(ctx, &parents); err != nil {
+		return nil, err
+	}
+	return parents, nil
+}
+
+// FindDue returns active parent orders the scheduler should act on
+func (r *ParentOrderRepository) FindDue(ctx context.Context, now time.Time) ([]model.ParentOrder, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"status":      model.ParentOrderStatusActive,
+		"nextSliceAt": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var parents []model.ParentOrder
+	if err := cursor.All(ctx, &parents); err != nil {
+		return nil, err
+	}
+	return parents, nil
+}
+
+// Update saves the parent order's working state
+func (r *ParentOrderRepository) Update(ctx context.Context, parent *model.ParentOrder) error {
+	parent.UpdatedAt = time.Now()
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": parent.ID}, parent)
+	return err
+}
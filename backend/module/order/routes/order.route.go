@@ -1,9 +1,12 @@
 package routes
 
 import (
+	"context"
+
 	"github.com/bricksocoolxd/bengi-investment-system/module/order/controller"
 	"github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
 	"github.com/bricksocoolxd/bengi-investment-system/module/order/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
 	"github.com/gofiber/fiber/v2"
 )
@@ -14,6 +17,10 @@ func RegisterRoutes(app *fiber.App) {
 	orderSvc := service.NewOrderService(repo)
 	ctrl := controller.NewOrderController(orderSvc)
 
+	// Quantity the engine cancels (self-trade prevention) is written back
+	// to the order and reported to its owner
+	matcher.Default().SetCancelHandler(orderSvc.HandleEngineCancel)
+
 	// All routes are protected
 	orders := app.Group("/api/v1/orders", middleware.AuthRequired())
 
@@ -21,4 +28,18 @@ func RegisterRoutes(app *fiber.App) {
 	orders.Get("/", ctrl.GetOrders)
 	orders.Get("/:id", ctrl.GetOrderByID)
 	orders.Post("/:id/cancel", ctrl.CancelOrder)
+
+	// Algorithmic parent orders, sliced into child orders by the scheduler
+	parentSvc := service.NewParentOrderService(repository.NewParentOrderRepository(), orderSvc)
+	parentCtrl := controller.NewParentOrderController(parentSvc)
+	go parentSvc.Start(context.Background(), service.ParentOrderInterval)
+
+	parents := app.Group("/api/v1/parent-orders", middleware.AuthRequired())
+
+	parents.Post("/", parentCtrl.CreateParentOrder)
+	parents.Get("/", parentCtrl.GetParentOrders)
+	parents.Get("/:id", parentCtrl.GetParentOrderByID)
+	parents.Post("/:id/pause", parentCtrl.PauseParentOrder)
+	parents.Post("/:id/resume", parentCtrl.ResumeParentOrder)
+	parents.Post("/:id/cancel", parentCtrl.CancelParentOrder)
 }
@@ -3,14 +3,25 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
 	"time"
 
+	accountModel "github.com/bricksocoolxd/bengi-investment-system/module/account/model"
 	accountRepo "github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	accountService "github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	instrumentModel "github.com/bricksocoolxd/bengi-investment-system/module/instrument/model"
+	instrumentRepo "github.com/bricksocoolxd/bengi-investment-system/module/instrument/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	marketRepo "github.com/bricksocoolxd/bengi-investment-system/module/market/repository"
 	"github.com/bricksocoolxd/bengi-investment-system/module/order/dto"
 	"github.com/bricksocoolxd/bengi-investment-system/module/order/model"
 	"github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
 	portfolioModel "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/model"
 	portfolioRepo "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/repository"
+	portfolioService "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/fees"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -23,19 +34,53 @@ var (
 	ErrCannotCancelOrder   = errors.New("order cannot be cancelled")
 	ErrInsufficientBalance = errors.New("insufficient balance")
 	ErrInvalidOrderType    = errors.New("invalid order type")
+	ErrMarketClosed        = errors.New("market is closed")
+	ErrOutsideRegularHours = errors.New("order is not eligible outside regular trading hours")
+	ErrExtendedHoursLimit  = errors.New("extended-hours trading is only available for LIMIT orders")
+	ErrInstrumentNotFound  = errors.New("instrument not found")
+	ErrInstrumentInactive  = errors.New("instrument is not active for trading")
+	ErrInstrumentDelisted  = errors.New("instrument has been delisted")
+	ErrTradingHalted       = errors.New("trading is halted")
+	ErrReserveLimitOnly    = errors.New("iceberg and hidden orders must be LIMIT orders")
+	ErrInvalidDisplayQty   = errors.New("display quantity must be below the order quantity and cannot be hidden")
+	ErrNoFXRate            = errors.New("no fx rate between the instrument and account currencies")
+	ErrAccountFrozen       = accountService.ErrAccountFrozen
+	ErrAccountClosed       = accountService.ErrAccountClosed
 )
 
 type OrderService struct {
-	repo          *repository.OrderRepository
-	portfolioRepo *portfolioRepo.PortfolioRepository
-	accountRepo   *accountRepo.AccountRepository
+	repo           *repository.OrderRepository
+	portfolioRepo  *portfolioRepo.PortfolioRepository
+	lots           *portfolioService.LotService
+	accountRepo    *accountRepo.AccountRepository
+	instrumentRepo *instrumentRepo.InstrumentRepository
+	haltRepo       *marketRepo.HaltRepository
+	calendar       *calendar.Calendar
+	engine         *matcher.Engine
+	settlements    *accountService.SettlementService
+	rates          *fx.Cache
+	fees           *fees.Engine
 }
 
 func NewOrderService(repo *repository.OrderRepository) *OrderService {
+	accounts := accountRepo.NewAccountRepository()
+	portfolios := portfolioRepo.NewPortfolioRepository()
 	return &OrderService{
-		repo:          repo,
-		portfolioRepo: portfolioRepo.NewPortfolioRepository(),
-		accountRepo:   accountRepo.NewAccountRepository(),
+		repo:           repo,
+		portfolioRepo:  portfolios,
+		lots:           portfolioService.NewLotService(portfolios),
+		accountRepo:    accounts,
+		instrumentRepo: instrumentRepo.NewInstrumentRepository(),
+		haltRepo:       marketRepo.NewHaltRepository(),
+		calendar:       calendar.Default(),
+		engine:         matcher.Default(),
+		settlements: accountService.NewSettlementService(
+			accountRepo.NewSettlementRepository(),
+			accounts,
+			accountService.NewLedgerService(accountRepo.NewLedgerRepository(), accounts),
+		),
+		rates: fx.Default(),
+		fees:  fees.Default(),
 	}
 }
 
@@ -69,6 +114,47 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID string, req *dto.
 		return nil, ErrInvalidOrderType
 	}
 
+	if req.ExtendedHours && req.Type != "LIMIT" {
+		return nil, ErrExtendedHoursLimit
+	}
+
+	if (req.DisplayQuantity > 0 || req.Hidden) && req.Type != "LIMIT" {
+		return nil, ErrReserveLimitOnly
+	}
+	if req.DisplayQuantity > 0 && (req.Hidden || req.DisplayQuantity >= req.Quantity) {
+		return nil, ErrInvalidDisplayQty
+	}
+
+	instrument, err := s.instrumentRepo.FindBySymbol(ctx, req.Symbol)
+	if err != nil {
+		return nil, ErrInstrumentNotFound
+	}
+	switch instrument.Status {
+	case instrumentModel.InstrumentStatusActive:
+	case instrumentModel.InstrumentStatusDelisted:
+		return nil, ErrInstrumentDelisted
+	default:
+		return nil, ErrInstrumentInactive
+	}
+
+	exchange := s.calendar.Canonical(calendar.ExchangeForInstrument(instrument.Exchange, string(instrument.Type)))
+	if halt, _ := s.haltRepo.FindActiveFor(ctx, instrument.Symbol, exchange); halt != nil {
+		return nil, ErrTradingHalted
+	}
+
+	if err := s.validateTradingRules(instrument, req); err != nil {
+		return nil, err
+	}
+
+	// Orders that can't execute in the current session and must execute now
+	// are rejected; the rest stay PENDING until an eligible session
+	eligibility := calendar.CheckEligibility(s.sessionPhase(exchange), req.Type, req.ExtendedHours)
+	if eligibility != nil {
+		if req.Type == "MARKET" || timeInForce == model.TimeInForceIOC || timeInForce == model.TimeInForceFOK {
+			return nil, sessionError(eligibility)
+		}
+	}
+
 	// For market orders, use current price (passed from frontend or mock)
 	fillPrice := req.Price
 	if fillPrice <= 0 {
@@ -76,35 +162,75 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID string, req *dto.
 	}
 	totalCost := req.Quantity * fillPrice
 
-	// Check balance for BUY orders
+	account, err := s.accountRepo.FindByID(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := accountService.CheckActive(account); err != nil {
+		return nil, err
+	}
+
+	// Check balance for BUY orders, in the account's currency
+	cost := 0.0
 	if req.Side == "BUY" {
-		account, err := s.accountRepo.FindByID(ctx, req.AccountID)
+		rate, err := s.rates.FillRate(instrument.Currency, account.Currency, true)
 		if err != nil {
-			return nil, err
+			return nil, ErrNoFXRate
 		}
-		if account.Balance < totalCost {
+		estimate := s.quoteFees(ctx, account, &model.Order{
+			Side:           model.OrderSideBuy,
+			InstrumentType: string(instrument.Type),
+		}, req.Quantity, fillPrice)
+		cost = (totalCost + estimate.Total) * rate
+		if account.BuyingPower(time.Now()) < cost {
 			return nil, ErrInsufficientBalance
 		}
 	}
 
+	// Self-trade prevention: the order's own mode, else the account's
+	selfTradeMode := req.SelfTradeMode
+	if selfTradeMode == "" {
+		selfTradeMode = account.SelfTradeMode
+	}
+
 	order := &model.Order{
-		UserID:       userObjectID,
-		AccountID:    accountObjectID,
-		PortfolioID:  portfolioObjectID,
-		InstrumentID: primitive.NewObjectID(),
-		Symbol:       req.Symbol,
-		Side:         model.OrderSide(req.Side),
-		Type:         model.OrderType(req.Type),
-		TimeInForce:  timeInForce,
-		Quantity:     req.Quantity,
-		FilledQty:    0,
-		Price:        req.Price,
-		StopPrice:    req.StopPrice,
-		Commission:   0,
+		UserID:         userObjectID,
+		AccountID:      accountObjectID,
+		PortfolioID:    portfolioObjectID,
+		InstrumentID:   instrument.ID,
+		Symbol:         instrument.Symbol,
+		Currency:       instrument.Currency,
+		Exchange:       exchange,
+		SettleDays:     instrument.SettlementDays(),
+		InstrumentType: string(instrument.Type),
+		Side:           model.OrderSide(req.Side),
+		Type:           model.OrderType(req.Type),
+		TimeInForce:    timeInForce,
+		ExtendedHours:  req.ExtendedHours,
+		SelfTradeMode:  selfTradeMode,
+		Quantity:       req.Quantity,
+		DisplayQty:     req.DisplayQuantity,
+		Hidden:         req.Hidden,
+		FilledQty:      0,
+		Price:          req.Price,
+		StopPrice:      req.StopPrice,
+		Commission:     0,
+	}
+
+	// A buy that rests in the book holds its cost until it fills or is
+	// cancelled, so the engine can't match it against cash spent elsewhere
+	if order.Side == model.OrderSideBuy && RestsInBook(order) {
+		if err := accountService.ReserveOrderCash(ctx, s.accountRepo, account, cost); err != nil {
+			return nil, err
+		}
+		order.ReservedCash = cost
 	}
 
 	// Create order first
 	if err := s.repo.Create(ctx, order); err != nil {
+		if order.ReservedCash > 0 {
+			s.accountRepo.AddReservedCash(ctx, account.ID, -order.ReservedCash)
+		}
 		return nil, err
 	}
 
@@ -126,46 +252,221 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID string, req *dto.
 		AvgPrice:  order.AvgFillPrice,
 	})
 
+	// Resting LIMIT orders go to the matching engine, which settles any
+	// fills through the trade service before returning
+	if RestsInBook(order) {
+		s.engine.AddOrder(ToEngineOrder(order))
+		if updated, err := s.repo.FindByID(ctx, order.ID.Hex()); err == nil {
+			order = updated
+		}
+	}
+
 	return s.toOrderResponse(order), nil
 }
 
+// HandleEngineCancel records quantity the matching engine cancelled, e.g.
+// to prevent a self-trade, and reports it to the order's owner
+func (s *OrderService) HandleEngineCancel(cancel *matcher.Cancellation) {
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelCtx()
+
+	order, err := s.repo.FindByID(ctx, cancel.OrderID)
+	if err != nil {
+		log.Printf("[Order] Engine cancelled unknown order %s: %v", cancel.OrderID, err)
+		return
+	}
+	// Already closed, e.g. cancelled by its user while the engine rejected it
+	if order.Status == model.OrderStatusCancelled || order.Status == model.OrderStatusFilled {
+		return
+	}
+
+	done := cancel.Remaining <= 0
+	if err := s.repo.AddCancelledQty(ctx, order.ID, cancel.Quantity, done); err != nil {
+		log.Printf("[Order] Failed to record cancel of %s: %v", cancel.OrderID, err)
+		return
+	}
+	if _, err := accountService.ReleaseOrderCash(ctx, s.repo, s.accountRepo, order, order.ReservedFor(cancel.Quantity)); err != nil {
+		log.Printf("[Order] Failed to release cash held by %s: %v", cancel.OrderID, err)
+	}
+
+	status := order.Status
+	if done {
+		status = model.OrderStatusCancelled
+	}
+	ws.PublishOrderUpdate(order.UserID.Hex(), &ws.OrderPayload{
+		OrderID:      order.ID.Hex(),
+		Symbol:       order.Symbol,
+		Side:         string(order.Side),
+		Status:       string(status),
+		FilledQty:    order.FilledQty,
+		AvgPrice:     order.AvgFillPrice,
+		CancelledQty: order.CancelledQty + cancel.Quantity,
+		Reason:       cancel.Reason,
+	})
+}
+
+// RestsInBook reports whether an order is matched by the engine.
+// MARKET orders execute immediately; IOC/FOK limits are not supported yet.
+func RestsInBook(order *model.Order) bool {
+	return order.Type == model.OrderTypeLimit &&
+		(order.TimeInForce == model.TimeInForceGTC || order.TimeInForce == model.TimeInForceDay)
+}
+
+// ToEngineOrder converts an order to its matching engine representation
+func ToEngineOrder(order *model.Order) *matcher.Order {
+	return &matcher.Order{
+		ID:            order.ID.Hex(),
+		UserID:        order.UserID.Hex(),
+		Symbol:        order.Symbol,
+		Side:          string(order.Side),
+		Type:          string(order.Type),
+		Price:         order.Price,
+		Quantity:      order.Quantity - order.CancelledQty,
+		FilledQty:     order.FilledQty,
+		Timestamp:     order.CreatedAt.UnixMilli(),
+		PortfolioID:   order.PortfolioID.Hex(),
+		AccountID:     order.AccountID.Hex(),
+		ExtendedHours: order.ExtendedHours,
+		SelfTradeMode: matcher.SelfTradeMode(order.SelfTradeMode),
+
+		DisplayQuantity: order.DisplayQty,
+		Hidden:          order.Hidden,
+	}
+}
+
+// validateTradingRules checks the order against the instrument's tick size,
+// lot size, size limits, minimum notional and price band
+func (s *OrderService) validateTradingRules(instrument *instrumentModel.Instrument, req *dto.CreateOrderRequest) error {
+	limitPrice := req.Price
+	if req.Type == "MARKET" {
+		limitPrice = 0
+	}
+
+	// Reference price: live last trade, else the price the client saw
+	lastPrice := 0.0
+	if last := ws.GetPriceStream().GetLastPrice(req.Symbol); last != nil {
+		lastPrice = last.Price
+	} else if req.Type == "MARKET" {
+		lastPrice = req.Price
+	}
+
+	rules := instrument.EffectiveTradingRules()
+	if violation := rules.ValidateOrder(req.Quantity, limitPrice, req.StopPrice, lastPrice); violation != nil {
+		return violation
+	}
+	return nil
+}
+
+// sessionPhase returns the current session phase of an exchange.
+// Unknown exchanges are treated as open.
+func (s *OrderService) sessionPhase(code string) calendar.Phase {
+	exchange, ok := s.calendar.Exchange(code)
+	if !ok {
+		return calendar.PhaseRegular
+	}
+	return exchange.PhaseAt(time.Now())
+}
+
+// sessionError maps a calendar eligibility error to an order error
+func sessionError(err error) error {
+	switch {
+	case errors.Is(err, calendar.ErrExtendedHoursRequired):
+		return ErrOutsideRegularHours
+	case errors.Is(err, calendar.ErrExtendedHoursLimitOnly):
+		return ErrExtendedHoursLimit
+	default:
+		return ErrMarketClosed
+	}
+}
+
 // executeMarketOrder executes a market order immediately
 func (s *OrderService) executeMarketOrder(ctx context.Context, order *model.Order, fillPrice float64) error {
 	now := time.Now()
 	totalCost := order.Quantity * fillPrice
-	commission := totalCost * 0.001 // 0.1% commission
+
+	account, err := s.accountRepo.FindByID(ctx, order.AccountID.Hex())
+	if err != nil {
+		return err
+	}
+	breakdown := s.quoteFees(ctx, account, order, order.Quantity, fillPrice)
 
 	// Update order as filled
 	order.Status = model.OrderStatusFilled
 	order.FilledQty = order.Quantity
 	order.AvgFillPrice = fillPrice
-	order.Commission = commission
+	order.Commission = breakdown.Commission
+	order.RegulatoryFees = breakdown.RegulatoryTotal()
 	order.FilledAt = &now
 
+	// Cash moves first, so a fill that can't be paid for leaves the order
+	// and the portfolio untouched. A sale must hold the shares before it
+	// is paid.
+	if order.Side == model.OrderSideSell {
+		if _, err := s.positionToSell(ctx, order); err != nil {
+			return err
+		}
+	}
+	if err := s.postFill(ctx, order, account, totalCost); err != nil {
+		return err
+	}
+
 	if err := s.repo.UpdateFill(ctx, order.ID, order.Quantity, fillPrice, model.OrderStatusFilled); err != nil {
 		return err
 	}
+	if err := s.repo.AddFees(ctx, order.ID, order.Commission, order.RegulatoryFees); err != nil {
+		return err
+	}
 
 	// Update/create position in portfolio
 	if order.Side == model.OrderSideBuy {
-		if err := s.addPosition(ctx, order, fillPrice); err != nil {
-			return err
-		}
-		// Deduct balance
-		if err := s.accountRepo.UpdateBalanceDelta(ctx, order.AccountID, -totalCost-commission); err != nil {
-			return err
-		}
-	} else {
-		// SELL - reduce position and credit balance
-		if err := s.reducePosition(ctx, order, fillPrice); err != nil {
-			return err
-		}
-		if err := s.accountRepo.UpdateBalanceDelta(ctx, order.AccountID, totalCost-commission); err != nil {
-			return err
-		}
+		return s.addPosition(ctx, order, fillPrice)
 	}
+	// SELL - reduce position
+	return s.reducePosition(ctx, order, fillPrice)
+}
 
-	return nil
+// postFill books an immediate fill and its fees in the ledger, converting
+// them into the account's currency and scheduling the fill's settlement
+func (s *OrderService) postFill(ctx context.Context, order *model.Order, account *accountModel.Account, totalCost float64) error {
+	buy := order.Side == model.OrderSideBuy
+	rate, err := s.rates.FillRate(order.Currency, account.Currency, buy)
+	if err != nil {
+		return ErrNoFXRate
+	}
+
+	_, err = s.settlements.PostFill(ctx, account, &accountService.Fill{
+		Buy:            buy,
+		Symbol:         order.Symbol,
+		Total:          totalCost,
+		Commission:     order.Commission,
+		RegulatoryFees: order.RegulatoryFees,
+		Currency:       order.Currency,
+		Rate:           rate,
+		SettleDate:     s.calendar.SettlementDate(order.Exchange, time.Now(), order.SettleDays),
+		ReferenceType:  "ORDER",
+		ReferenceID:    &order.ID,
+		Description:    string(order.Side) + " " + order.Symbol,
+	})
+	return err
+}
+
+// quoteFees prices a fill of order on its account's fee plan and 30-day
+// trading volume
+func (s *OrderService) quoteFees(ctx context.Context, account *accountModel.Account, order *model.Order, quantity, price float64) *fees.Breakdown {
+	volume, err := s.accountRepo.TradingVolume(ctx, account.ID, time.Now().Add(-fees.VolumeWindow))
+	if err != nil {
+		log.Printf("⚠️ Failed to load trading volume for %s: %v", account.ID.Hex(), err)
+	}
+	return s.fees.Quote(fees.Fill{
+		Plan:           account.FeePlan,
+		InstrumentType: order.InstrumentType,
+		Buy:            order.Side == model.OrderSideBuy,
+		Quantity:       quantity,
+		Price:          price,
+		Volume:         volume,
+		OrderValue:     order.FilledQty * order.AvgFillPrice,
+		OrderCharged:   order.Commission,
+	})
 }
 
 // addPosition creates or updates a position for a BUY order
@@ -182,7 +483,10 @@ func (s *OrderService) addPosition(ctx context.Context, order *model.Order, pric
 			AvgCost:      price,
 			TotalCost:    order.Quantity * price,
 		}
-		return s.portfolioRepo.CreatePosition(ctx, newPosition)
+		if err := s.portfolioRepo.CreatePosition(ctx, newPosition); err != nil {
+			return err
+		}
+		return s.openLot(ctx, order, newPosition.ID, price)
 	}
 
 	// Update existing position with weighted average
@@ -190,22 +494,64 @@ func (s *OrderService) addPosition(ctx context.Context, order *model.Order, pric
 	newTotalCost := existingPos.TotalCost + (order.Quantity * price)
 	newAvgCost := newTotalCost / newTotalQty
 
-	return s.portfolioRepo.UpdatePosition(ctx, existingPos.ID, bson.M{
+	if err := s.portfolioRepo.UpdatePosition(ctx, existingPos.ID, bson.M{
 		"quantity":  newTotalQty,
 		"avgCost":   newAvgCost,
 		"totalCost": newTotalCost,
+	}); err != nil {
+		return err
+	}
+	return s.openLot(ctx, order, existingPos.ID, price)
+}
+
+// openLot opens a tax lot for a filled BUY order
+func (s *OrderService) openLot(ctx context.Context, order *model.Order, positionID primitive.ObjectID, price float64) error {
+	return s.lots.Open(ctx, &portfolioModel.PositionLot{
+		PortfolioID:  order.PortfolioID,
+		PositionID:   positionID,
+		InstrumentID: order.InstrumentID,
+		OrderID:      order.ID,
+		Symbol:       order.Symbol,
+		Currency:     order.Currency,
+		Quantity:     order.Quantity,
+		CostPerUnit:  price,
+		Fees:         order.Commission + order.RegulatoryFees,
+		PurchasedAt:  *order.FilledAt,
 	})
 }
 
+// positionToSell returns the position a SELL order sells from, refusing
+// one that holds fewer shares than the order
+func (s *OrderService) positionToSell(ctx context.Context, order *model.Order) (*portfolioModel.Position, error) {
+	existingPos, err := s.portfolioRepo.FindPositionByPortfolioAndSymbol(ctx, order.PortfolioID, order.Symbol)
+	if err != nil {
+		return nil, errors.New("no position to sell")
+	}
+	if existingPos.Quantity < order.Quantity {
+		return nil, errors.New("insufficient shares")
+	}
+	return existingPos, nil
+}
+
 // reducePosition reduces a position for a SELL order
 func (s *OrderService) reducePosition(ctx context.Context, order *model.Order, price float64) error {
-	existingPos, err := s.portfolioRepo.FindPositionByPortfolioAndSymbol(ctx, order.PortfolioID, order.Symbol)
+	existingPos, err := s.positionToSell(ctx, order)
 	if err != nil {
-		return errors.New("no position to sell")
+		return err
 	}
 
-	if existingPos.Quantity < order.Quantity {
-		return errors.New("insufficient shares")
+	if _, err := s.lots.Close(ctx, &portfolioService.Sale{
+		PortfolioID: order.PortfolioID,
+		PositionID:  existingPos.ID,
+		OrderID:     order.ID,
+		Symbol:      order.Symbol,
+		Currency:    order.Currency,
+		Quantity:    order.Quantity,
+		Price:       price,
+		Fees:        order.Commission + order.RegulatoryFees,
+		SoldAt:      *order.FilledAt,
+	}); err != nil {
+		return err
 	}
 
 	newQty := existingPos.Quantity - order.Quantity
@@ -285,13 +631,18 @@ func (s *OrderService) CancelOrder(ctx context.Context, orderID, userID string)
 	// Check if order can be cancelled
 	if order.Status != model.OrderStatusPending &&
 		order.Status != model.OrderStatusOpen &&
-		order.Status != model.OrderStatusPartiallyFilled {
+		order.Status != model.OrderStatusPartiallyFilled &&
+		order.Status != model.OrderStatusSuspended {
 		return nil, ErrCannotCancelOrder
 	}
 
 	if err := s.repo.UpdateStatus(ctx, order.ID, model.OrderStatusCancelled); err != nil {
 		return nil, err
 	}
+	s.engine.CancelOrder(order.Symbol, order.ID.Hex())
+	if _, err := accountService.ReleaseOrderCash(ctx, s.repo, s.accountRepo, order, -1); err != nil {
+		return nil, err
+	}
 
 	ws.PublishOrderUpdate(userID, &ws.OrderPayload{
 		OrderID:   order.ID.Hex(),
@@ -313,23 +664,28 @@ func (s *OrderService) CancelOrder(ctx context.Context, orderID, userID string)
 
 func (s *OrderService) toOrderResponse(order *model.Order) *dto.OrderResponse {
 	resp := &dto.OrderResponse{
-		ID:           order.ID.Hex(),
-		UserID:       order.UserID.Hex(),
-		AccountID:    order.AccountID.Hex(),
-		PortfolioID:  order.PortfolioID.Hex(),
-		InstrumentID: order.InstrumentID.Hex(),
-		Symbol:       order.Symbol,
-		Side:         string(order.Side),
-		Type:         string(order.Type),
-		Status:       string(order.Status),
-		TimeInForce:  string(order.TimeInForce),
-		Quantity:     order.Quantity,
-		FilledQty:    order.FilledQty,
-		Price:        order.Price,
-		StopPrice:    order.StopPrice,
-		AvgFillPrice: order.AvgFillPrice,
-		Commission:   order.Commission,
-		CreatedAt:    order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:            order.ID.Hex(),
+		UserID:        order.UserID.Hex(),
+		AccountID:     order.AccountID.Hex(),
+		PortfolioID:   order.PortfolioID.Hex(),
+		InstrumentID:  order.InstrumentID.Hex(),
+		Symbol:        order.Symbol,
+		Side:          string(order.Side),
+		Type:          string(order.Type),
+		Status:        string(order.Status),
+		TimeInForce:   string(order.TimeInForce),
+		ExtendedHours: order.ExtendedHours,
+		SelfTradeMode: order.SelfTradeMode,
+		Quantity:      order.Quantity,
+		DisplayQty:    order.DisplayQty,
+		Hidden:        order.Hidden,
+		FilledQty:     order.FilledQty,
+		CancelledQty:  order.CancelledQty,
+		Price:         order.Price,
+		StopPrice:     order.StopPrice,
+		AvgFillPrice:  order.AvgFillPrice,
+		Commission:    order.Commission,
+		CreatedAt:     order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	if order.FilledAt != nil {
@@ -0,0 +1,574 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	accountRepo "github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	accountService "github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	instrumentModel "github.com/bricksocoolxd/bengi-investment-system/module/instrument/model"
+	instrumentRepo "github.com/bricksocoolxd/bengi-investment-system/module/instrument/repository"
+	instrumentService "github.com/bricksocoolxd/bengi-investment-system/module/instrument/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/order/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/order/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// ParentOrderInterval is how often the scheduler looks for due slices
+	ParentOrderInterval = time.Second
+
+	// MaxParentOrderWindow bounds how long a parent order can work
+	MaxParentOrderWindow = 24 * time.Hour
+
+	// defaultMaxSlices caps the one-slice-per-minute default
+	defaultMaxSlices = 60
+
+	// volumeCurveLookback is how much intraday history builds the VWAP curve
+	volumeCurveLookback = 5 * 24 * time.Hour
+
+	// parentFillTolerance absorbs float rounding when checking for a full fill
+	parentFillTolerance = 1e-9
+)
+
+var (
+	ErrParentOrderNotFound = errors.New("parent order not found")
+	ErrInvalidWindow       = errors.New("end time must be in the future, after start time, and within 24 hours of it")
+	ErrParentOrderState    = errors.New("parent order cannot do that in its current status")
+)
+
+// ParentOrderService works TWAP and VWAP parent orders by sending child
+// orders through the OrderService on a schedule. State lives in the
+// parent order document, so the schedule survives restarts.
+type ParentOrderService struct {
+	repo           *repository.ParentOrderRepository
+	orderRepo      *repository.OrderRepository
+	orderService   *OrderService
+	accountRepo    *accountRepo.AccountRepository
+	instrumentRepo *instrumentRepo.InstrumentRepository
+	marketData     *instrumentService.MarketDataService
+	prices         func(symbol string) float64
+
+	// Serialises the scheduler with user actions, since both rewrite
+	// the whole parent order
+	mu sync.Mutex
+}
+
+func NewParentOrderService(repo *repository.ParentOrderRepository, orderService *OrderService) *ParentOrderService {
+	return &ParentOrderService{
+		repo:           repo,
+		orderRepo:      orderService.repo,
+		orderService:   orderService,
+		accountRepo:    accountRepo.NewAccountRepository(),
+		instrumentRepo: instrumentRepo.NewInstrumentRepository(),
+		marketData:     instrumentService.NewMarketDataService(),
+		prices:         referencePrice,
+	}
+}
+
+// referencePrice returns the last trade price from the live price stream
+func referencePrice(symbol string) float64 {
+	if last := ws.GetPriceStream().GetLastPrice(symbol); last != nil {
+		return last.Price
+	}
+	return 0
+}
+
+func (s *ParentOrderService) CreateParentOrder(ctx context.Context, userID string, req *dto.CreateParentOrderRequest) (*dto.ParentOrderResponse, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	portfolioObjectID, err := primitive.ObjectIDFromHex(req.PortfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	start := now
+	if req.StartTime != "" {
+		if start, err = time.Parse(time.RFC3339, req.StartTime); err != nil {
+			return nil, ErrInvalidWindow
+		}
+	}
+	end, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil || !end.After(start) || !end.After(now) || end.Sub(start) > MaxParentOrderWindow {
+		return nil, ErrInvalidWindow
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.UserID != userObjectID {
+		return nil, ErrUnauthorized
+	}
+	if err := accountService.CheckActive(account); err != nil {
+		return nil, err
+	}
+
+	instrument, err := s.instrumentRepo.FindBySymbol(ctx, req.Symbol)
+	if err != nil {
+		return nil, ErrInstrumentNotFound
+	}
+
+	slices := req.Slices
+	if slices == 0 {
+		slices = int(math.Ceil(end.Sub(start).Minutes()))
+		slices = max(1, min(slices, defaultMaxSlices))
+	}
+
+	strategy := model.ParentStrategy(req.Strategy)
+	var curve []float64
+	if strategy == model.ParentStrategyVWAP {
+		curve = s.volumeCurve(instrument.Symbol, now)
+	}
+
+	parent := &model.ParentOrder{
+		UserID:      userObjectID,
+		AccountID:   account.ID,
+		PortfolioID: portfolioObjectID,
+		Symbol:      instrument.Symbol,
+		Side:        model.OrderSide(req.Side),
+		Strategy:    strategy,
+		Status:      model.ParentOrderStatusActive,
+		Quantity:    req.Quantity,
+		LimitPrice:  req.LimitPrice,
+		StartTime:   start,
+		EndTime:     end,
+		Weights:     SliceWeights(strategy, start, end, slices, curve),
+		NextSliceAt: start,
+		ChildIDs:    make([]primitive.ObjectID, 0),
+	}
+	if err := s.repo.Create(ctx, parent); err != nil {
+		return nil, err
+	}
+
+	return s.toParentOrderResponse(parent), nil
+}
+
+// volumeCurve builds the symbol's intraday volume curve from recent
+// 5-minute candles. Returns nil if no candle data is available, which
+// makes VWAP fall back to equal slices.
+func (s *ParentOrderService) volumeCurve(symbol string, now time.Time) []float64 {
+	candles, err := s.marketData.GetCandles(symbol, "5", now.Add(-volumeCurveLookback).Unix(), now.Unix())
+	if err != nil {
+		log.Printf("[ParentOrder] No candles for %s, using equal slices: %v", symbol, err)
+		return nil
+	}
+	return VolumeCurve(candles, 5*time.Minute)
+}
+
+func (s *ParentOrderService) GetParentOrders(ctx context.Context, userID string) ([]dto.ParentOrderResponse, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parents, err := s.repo.FindByUserID(ctx, userObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.ParentOrderResponse, len(parents))
+	for i := range parents {
+		result[i] = *s.toParentOrderResponse(&parents[i])
+	}
+	return result, nil
+}
+
+func (s *ParentOrderService) GetParentOrder(ctx context.Context, id, userID string) (*dto.ParentOrderResponse, error) {
+	parent, err := s.findOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toParentOrderResponse(parent), nil
+}
+
+// PauseParentOrder stops sending slices and pulls the working child
+func (s *ParentOrderService) PauseParentOrder(ctx context.Context, id, userID string) (*dto.ParentOrderResponse, error) {
+	return s.transition(ctx, id, userID, model.ParentOrderStatusPaused, func(parent *model.ParentOrder) bool {
+		return parent.Status == model.ParentOrderStatusActive
+	})
+}
+
+// ResumeParentOrder picks the schedule up at the current slice. Slices
+// missed while paused are not sent; their quantity is spread over the
+// slices that are left.
+func (s *ParentOrderService) ResumeParentOrder(ctx context.Context, id, userID string) (*dto.ParentOrderResponse, error) {
+	return s.transition(ctx, id, userID, model.ParentOrderStatusActive, func(parent *model.ParentOrder) bool {
+		return parent.Status == model.ParentOrderStatusPaused
+	})
+}
+
+// CancelParentOrder stops the parent order and pulls the working child.
+// Quantity already filled stays filled.
+func (s *ParentOrderService) CancelParentOrder(ctx context.Context, id, userID string) (*dto.ParentOrderResponse, error) {
+	return s.transition(ctx, id, userID, model.ParentOrderStatusCancelled, func(parent *model.ParentOrder) bool {
+		return !parent.IsTerminal()
+	})
+}
+
+// transition moves a parent order to status if allowed says it may
+func (s *ParentOrderService) transition(ctx context.Context, id, userID string, status model.ParentOrderStatus, allowed func(*model.ParentOrder) bool) (*dto.ParentOrderResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parent, err := s.findOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed(parent) {
+		return nil, ErrParentOrderState
+	}
+
+	if status == model.ParentOrderStatusActive {
+		parent.NextSliceAt = time.Now()
+	} else {
+		s.cancelChildren(ctx, parent)
+	}
+	s.refreshFills(ctx, parent)
+	parent.Status = status
+
+	if err := s.repo.Update(ctx, parent); err != nil {
+		return nil, err
+	}
+	return s.toParentOrderResponse(parent), nil
+}
+
+// findOwned loads a parent order that belongs to userID
+func (s *ParentOrderService) findOwned(ctx context.Context, id, userID string) (*model.ParentOrder, error) {
+	parent, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrParentOrderNotFound
+	}
+	if parent.UserID.Hex() != userID {
+		return nil, ErrUnauthorized
+	}
+	return parent, nil
+}
+
+// Start works due parent orders until ctx is done
+func (s *ParentOrderService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.runDue(ctx, now)
+		}
+	}
+}
+
+// runDue works every active parent order whose next slice is due
+func (s *ParentOrderService) runDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parents, err := s.repo.FindDue(ctx, now)
+	if err != nil {
+		log.Printf("[ParentOrder] Failed to load due orders: %v", err)
+		return
+	}
+
+	for i := range parents {
+		parent := &parents[i]
+		s.work(ctx, parent, now)
+		if err := s.repo.Update(ctx, parent); err != nil {
+			log.Printf("[ParentOrder] Failed to save %s: %v", parent.ID.Hex(), err)
+		}
+	}
+}
+
+// work sends the parent order's due slice, or finishes it once it is
+// filled or its window has closed. The child still working from the
+// previous slice is cancelled first, so what it missed is caught up
+// across the slices that remain.
+func (s *ParentOrderService) work(ctx context.Context, parent *model.ParentOrder, now time.Time) {
+	s.cancelChildren(ctx, parent)
+	s.refreshFills(ctx, parent)
+
+	remaining := parent.Quantity - parent.FilledQty
+	if remaining <= parentFillTolerance {
+		parent.Status = model.ParentOrderStatusCompleted
+		return
+	}
+	if !now.Before(parent.EndTime) || parent.NextSlice >= len(parent.Weights) {
+		parent.Status = model.ParentOrderStatusExpired
+		return
+	}
+
+	// After a pause or downtime, skip to the slice for the current time
+	slice := parent.NextSlice
+	for slice+1 < len(parent.Weights) && !parent.SliceTime(slice+1).After(now) {
+		slice++
+	}
+
+	s.sendSlice(ctx, parent, slice, remaining)
+
+	parent.NextSlice = slice + 1
+	if parent.NextSlice < len(parent.Weights) {
+		parent.NextSliceAt = parent.SliceTime(parent.NextSlice)
+	} else {
+		parent.NextSliceAt = parent.EndTime
+	}
+}
+
+// sendSlice records the benchmark for slice i and sends its child order
+func (s *ParentOrderService) sendSlice(ctx context.Context, parent *model.ParentOrder, i int, remaining float64) {
+	reference := s.prices(parent.Symbol)
+	if reference > 0 {
+		if parent.ArrivalPrice == 0 {
+			parent.ArrivalPrice = reference
+		}
+		parent.BenchmarkNotional += reference * parent.Weights[i]
+		parent.BenchmarkWeight += parent.Weights[i]
+	}
+
+	req := &dto.CreateOrderRequest{
+		AccountID:   parent.AccountID.Hex(),
+		PortfolioID: parent.PortfolioID.Hex(),
+		Symbol:      parent.Symbol,
+		Side:        string(parent.Side),
+		Type:        string(model.OrderTypeLimit),
+		Price:       parent.LimitPrice,
+	}
+	if parent.LimitPrice == 0 {
+		if reference <= 0 {
+			parent.LastError = fmt.Sprintf("no reference price for %s, slice %d skipped", parent.Symbol, i)
+			return
+		}
+		req.Type = string(model.OrderTypeMarket)
+		req.Price = reference
+	}
+
+	rules := instrumentModel.DefaultTradingRules(instrumentModel.InstrumentTypeStock)
+	if instrument, err := s.instrumentRepo.FindBySymbol(ctx, parent.Symbol); err == nil {
+		rules = instrument.EffectiveTradingRules()
+	}
+
+	// The last slice takes everything left; earlier ones round down to
+	// the lot size and carry the rest forward
+	qty := remaining
+	if i < len(parent.Weights)-1 {
+		qty = roundToLot(SliceQuantity(remaining, parent.Weights, i), rules)
+	}
+	if qty <= 0 || qty < rules.MinQuantity {
+		return
+	}
+	req.Quantity = qty
+
+	child, err := s.orderService.CreateOrder(ctx, parent.UserID.Hex(), req)
+	if err != nil {
+		parent.LastError = err.Error()
+		log.Printf("[ParentOrder] %s slice %d failed: %v", parent.ID.Hex(), i, err)
+		// A frozen account may be unfrozen in time; a closed one won't be
+		if errors.Is(err, ErrAccountClosed) {
+			parent.Status = model.ParentOrderStatusCancelled
+		}
+		return
+	}
+	parent.LastError = ""
+	if childID, err := primitive.ObjectIDFromHex(child.ID); err == nil {
+		parent.ChildIDs = append(parent.ChildIDs, childID)
+	}
+	s.refreshFills(ctx, parent)
+}
+
+// cancelChildren cancels any child order still working
+func (s *ParentOrderService) cancelChildren(ctx context.Context, parent *model.ParentOrder) {
+	for _, childID := range parent.ChildIDs {
+		child, err := s.orderRepo.FindByID(ctx, childID.Hex())
+		if err != nil || !isWorking(child.Status) {
+			continue
+		}
+		if _, err := s.orderService.CancelOrder(ctx, childID.Hex(), parent.UserID.Hex()); err != nil {
+			log.Printf("[ParentOrder] Failed to cancel child %s: %v", childID.Hex(), err)
+		}
+	}
+}
+
+// refreshFills totals the children's fills onto the parent
+func (s *ParentOrderService) refreshFills(ctx context.Context, parent *model.ParentOrder) {
+	filled, notional := 0.0, 0.0
+	for _, childID := range parent.ChildIDs {
+		child, err := s.orderRepo.FindByID(ctx, childID.Hex())
+		if err != nil {
+			continue
+		}
+		filled += child.FilledQty
+		notional += child.FilledQty * child.AvgFillPrice
+	}
+
+	parent.FilledQty = filled
+	parent.AvgPrice = 0
+	if filled > 0 {
+		parent.AvgPrice = notional / filled
+	}
+}
+
+// isWorking reports whether an order can still trade
+func isWorking(status model.OrderStatus) bool {
+	return status == model.OrderStatusPending ||
+		status == model.OrderStatusOpen ||
+		status == model.OrderStatusPartiallyFilled ||
+		status == model.OrderStatusSuspended
+}
+
+// roundToLot rounds qty down to the instrument's lot size and precision
+func roundToLot(qty float64, rules instrumentModel.TradingRules) float64 {
+	if rules.LotSize > 0 {
+		qty = math.Floor(qty/rules.LotSize+1e-9) * rules.LotSize
+	}
+	scale := math.Pow(10, float64(rules.QuantityPrecision))
+	return math.Floor(qty*scale+1e-9) / scale
+}
+
+// VolumeCurve averages candle volume into a per-minute curve over the
+// day (UTC), spreading each candle's volume evenly across its bucket
+func VolumeCurve(candles []instrumentService.Candle, bucket time.Duration) []float64 {
+	const minutesPerDay = 24 * 60
+	totals := make([]float64, minutesPerDay)
+	counts := make([]int, minutesPerDay)
+
+	width := max(1, int(bucket/time.Minute))
+	for _, candle := range candles {
+		at := time.Unix(candle.Time, 0).UTC()
+		start := at.Hour()*60 + at.Minute()
+		for m := 0; m < width; m++ {
+			minute := (start + m) % minutesPerDay
+			totals[minute] += float64(candle.Volume) / float64(width)
+			counts[minute]++
+		}
+	}
+
+	curve := make([]float64, minutesPerDay)
+	for i := range curve {
+		if counts[i] > 0 {
+			curve[i] = totals[i] / float64(counts[i])
+		}
+	}
+	return curve
+}
+
+// SliceWeights splits the window [start, end) into slices and returns
+// each slice's share of the total quantity. TWAP shares equally; VWAP
+// follows the volume curve, falling back to equal shares where the
+// curve has no volume for the window.
+func SliceWeights(strategy model.ParentStrategy, start, end time.Time, slices int, curve []float64) []float64 {
+	weights := make([]float64, slices)
+	total := 0.0
+
+	if strategy == model.ParentStrategyVWAP && len(curve) > 0 {
+		step := end.Sub(start) / time.Duration(slices)
+		for i := range weights {
+			from := start.Add(step * time.Duration(i))
+			weights[i] = curveVolume(curve, from, from.Add(step))
+			total += weights[i]
+		}
+	}
+
+	if total <= 0 {
+		for i := range weights {
+			weights[i] = 1 / float64(slices)
+		}
+		return weights
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+	return weights
+}
+
+// curveVolume integrates a per-minute volume curve over [from, to)
+func curveVolume(curve []float64, from, to time.Time) float64 {
+	volume := 0.0
+	for t := from; t.Before(to); {
+		next := t.Truncate(time.Minute).Add(time.Minute)
+		if next.After(to) {
+			next = to
+		}
+		utc := t.UTC()
+		volume += curve[(utc.Hour()*60+utc.Minute())%len(curve)] * next.Sub(t).Minutes()
+		t = next
+	}
+	return volume
+}
+
+// SliceQuantity returns what slice i should send given what is left to
+// fill: slice i's share of the weight still ahead. Shortfalls from
+// earlier slices are spread over the rest of the schedule this way.
+func SliceQuantity(remaining float64, weights []float64, i int) float64 {
+	ahead := 0.0
+	for _, w := range weights[i:] {
+		ahead += w
+	}
+	if ahead <= 0 {
+		return remaining / float64(len(weights)-i)
+	}
+	return remaining * weights[i] / ahead
+}
+
+// Helper: Convert ParentOrder to ParentOrderResponse
+func (s *ParentOrderService) toParentOrderResponse(parent *model.ParentOrder) *dto.ParentOrderResponse {
+	scheduled := 0.0
+	for _, w := range parent.Weights[:min(parent.NextSlice, len(parent.Weights))] {
+		scheduled += w
+	}
+
+	childIDs := make([]string, len(parent.ChildIDs))
+	for i, id := range parent.ChildIDs {
+		childIDs[i] = id.Hex()
+	}
+
+	response := &dto.ParentOrderResponse{
+		ID:             parent.ID.Hex(),
+		AccountID:      parent.AccountID.Hex(),
+		PortfolioID:    parent.PortfolioID.Hex(),
+		Symbol:         parent.Symbol,
+		Side:           string(parent.Side),
+		Strategy:       string(parent.Strategy),
+		Status:         string(parent.Status),
+		Quantity:       parent.Quantity,
+		LimitPrice:     parent.LimitPrice,
+		StartTime:      parent.StartTime.Format(time.RFC3339),
+		EndTime:        parent.EndTime.Format(time.RFC3339),
+		Slices:         len(parent.Weights),
+		SlicesSent:     parent.NextSlice,
+		FilledQty:      parent.FilledQty,
+		AvgPrice:       parent.AvgPrice,
+		ProgressPct:    parent.FilledQty / parent.Quantity * 100,
+		SchedulePct:    scheduled * 100,
+		ArrivalPrice:   parent.ArrivalPrice,
+		BenchmarkPrice: parent.BenchmarkPrice(),
+		ChildOrderIDs:  childIDs,
+		LastError:      parent.LastError,
+		CreatedAt:      parent.CreatedAt.Format(time.RFC3339),
+	}
+	response.SlippageBps = SlippageBps(parent.Side, parent.AvgPrice, response.BenchmarkPrice)
+	return response
+}
+
+// SlippageBps compares an average fill price with a benchmark in basis
+// points. Positive means the fills were worse than the benchmark: paid
+// more on a buy, received less on a sell.
+func SlippageBps(side model.OrderSide, avgPrice, benchmark float64) float64 {
+	if avgPrice <= 0 || benchmark <= 0 {
+		return 0
+	}
+	bps := (avgPrice - benchmark) / benchmark * 10000
+	if side == model.OrderSideSell {
+		bps = -bps
+	}
+	return bps
+}
@@ -81,7 +81,8 @@ func (ctrl *PortfolioController) GetPortfolioByID(c *fiber.Ctx) error {
 	return common.Success(c, result, "")
 }
 
-// GetPortfolioSummary returns portfolio with positions and P&L
+// GetPortfolioSummary returns portfolio with positions and P&L, totalled
+// in ?currency= (default the account's currency)
 // GET /api/v1/portfolios/:id/summary
 func (ctrl *PortfolioController) GetPortfolioSummary(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -89,9 +90,17 @@ func (ctrl *PortfolioController) GetPortfolioSummary(c *fiber.Ctx) error {
 		return common.Unauthorized(c, "User not authenticated")
 	}
 
+	currency := c.Query("currency")
+	if currency != "" && len(currency) != 3 {
+		return common.BadRequest(c, "currency must be a 3-letter code")
+	}
+
 	portfolioID := c.Params("id")
-	result, err := ctrl.portfolioService.GetPortfolioSummary(c.Context(), portfolioID, userID)
+	result, err := ctrl.portfolioService.GetPortfolioSummary(c.Context(), portfolioID, userID, currency)
 	if err != nil {
+		if errors.Is(err, service.ErrNoFXRate) {
+			return common.BadRequest(c, "No exchange rate into the report currency")
+		}
 		if errors.Is(err, service.ErrPortfolioNotFound) {
 			return common.NotFound(c, "Portfolio not found")
 		}
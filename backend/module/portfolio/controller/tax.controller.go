@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaxController struct {
+	taxService *service.TaxService
+}
+
+func NewTaxController(taxService *service.TaxService) *TaxController {
+	return &TaxController{
+		taxService: taxService,
+	}
+}
+
+// GetRealizedGains reports the lots closed in a tax year
+// GET /api/v1/portfolios/realized-gains?year=2026&portfolioId=
+func (ctrl *TaxController) GetRealizedGains(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	year, ok := taxYear(c)
+	if !ok {
+		return common.BadRequest(c, "Invalid year")
+	}
+
+	result, err := ctrl.taxService.RealizedGains(c.Context(), userID, year, c.Query("portfolioId"))
+	if err != nil {
+		return taxError(c, err)
+	}
+	return common.Success(c, result, "")
+}
+
+// ExportRealizedGains downloads a tax year's realized gains as Form 8949 CSV
+// GET /api/v1/portfolios/realized-gains/export?year=2026&portfolioId=
+func (ctrl *TaxController) ExportRealizedGains(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return common.Unauthorized(c, "User not authenticated")
+	}
+	year, ok := taxYear(c)
+	if !ok {
+		return common.BadRequest(c, "Invalid year")
+	}
+
+	content, err := ctrl.taxService.ExportRealizedGains(c.Context(), userID, year, c.Query("portfolioId"))
+	if err != nil {
+		return taxError(c, err)
+	}
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="realized-gains-%d.csv"`, year))
+	return c.Send(content)
+}
+
+// taxYear reads the year query, defaulting to the current one
+func taxYear(c *fiber.Ctx) (int, bool) {
+	year := c.QueryInt("year", time.Now().UTC().Year())
+	return year, year >= 1900 && year <= 9999
+}
+
+func taxError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, service.ErrPortfolioNotFound) {
+		return common.NotFound(c, "Portfolio not found")
+	}
+	return common.InternalError(c, err.Error())
+}
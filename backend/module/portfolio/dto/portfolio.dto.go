@@ -20,9 +20,13 @@ type (
 		Value     float64 `json:"value,omitempty"`
 	}
 
+	// PortfolioSummary totals are in Currency. Positions stay in their
+	// own currency; Rates holds what each was converted at.
 	PortfolioSummary struct {
 		Portfolio   PortfolioResponse  `json:"portfolio"`
 		Positions   []PositionResponse `json:"positions"`
+		Currency    string             `json:"currency"`
+		Rates       map[string]float64 `json:"rates,omitempty"`
 		TotalValue  float64            `json:"totalValue"`
 		TotalCost   float64            `json:"totalCost"`
 		TotalPnL    float64            `json:"totalPnL"`
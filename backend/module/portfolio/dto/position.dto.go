@@ -6,6 +6,7 @@ type (
 		PortfolioID      string  `json:"portfolioId"`
 		InstrumentID     string  `json:"instrumentId"`
 		Symbol           string  `json:"symbol"`
+		Currency         string  `json:"currency,omitempty"`
 		Quantity         float64 `json:"quantity"`
 		AvgCost          float64 `json:"avgCost"`
 		TotalCost        float64 `json:"totalCost"`
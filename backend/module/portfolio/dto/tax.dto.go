@@ -0,0 +1,38 @@
+package dto
+
+type (
+	// RealizedGainResponse is one closed lot, or the part of one carrying
+	// wash-sale basis. Amounts are in Currency; Gain includes Adjustment.
+	RealizedGainResponse struct {
+		LotID       string  `json:"lotId"`
+		PortfolioID string  `json:"portfolioId"`
+		Symbol      string  `json:"symbol"`
+		Currency    string  `json:"currency"`
+		Quantity    float64 `json:"quantity"`
+		AcquiredAt  string  `json:"acquiredAt"`
+		DisposedAt  string  `json:"disposedAt"`
+		Proceeds    float64 `json:"proceeds"`
+		CostBasis   float64 `json:"costBasis"`
+		Code        string  `json:"code,omitempty"`       // W for a wash sale
+		Adjustment  float64 `json:"adjustment,omitempty"` // Loss disallowed
+		Gain        float64 `json:"gain"`
+		Term        string  `json:"term"` // SHORT or LONG
+	}
+
+	// RealizedGainsTotals sums one term's gains in one currency
+	RealizedGainsTotals struct {
+		Term       string  `json:"term"`
+		Currency   string  `json:"currency"`
+		Proceeds   float64 `json:"proceeds"`
+		CostBasis  float64 `json:"costBasis"`
+		Adjustment float64 `json:"adjustment"`
+		Gain       float64 `json:"gain"`
+	}
+
+	// RealizedGainsResponse is a tax year's realized gains report
+	RealizedGainsResponse struct {
+		Year   int                    `json:"year"`
+		Gains  []RealizedGainResponse `json:"gains"`
+		Totals []RealizedGainsTotals  `json:"totals"`
+	}
+)
@@ -8,6 +8,11 @@ import (
 
 const PositionLotCollection = "positionLots"
 
+// LotDisposalCollection is the MongoDB collection name for closed lot quantities.
+const LotDisposalCollection = "lotDisposals"
+
+// PositionLot is one purchase of a position, its tax lot. Sales close
+// lots first in, first out.
 type PositionLot struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	PortfolioID  primitive.ObjectID `bson:"portfolioId" json:"portfolioId"`
@@ -19,4 +24,39 @@ type PositionLot struct {
 	CostPerUnit  float64            `bson:"costPerUnit" json:"costPerUnit"`
 	PurchasedAt  time.Time          `bson:"purchasedAt" json:"purchasedAt"`
 	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+
+	// Reporting: the order it filled, what it is priced in and the
+	// purchase's fees, which add to its basis
+	OrderID  primitive.ObjectID `bson:"orderId,omitempty" json:"orderId,omitempty"`
+	Symbol   string             `bson:"symbol,omitempty" json:"symbol,omitempty"`
+	Currency string             `bson:"currency,omitempty" json:"currency,omitempty"`
+	Fees     float64            `bson:"fees,omitempty" json:"fees,omitempty"`
+}
+
+// CostBasis is what quantity of the lot cost, its share of the fees
+// included
+func (l *PositionLot) CostBasis(quantity float64) float64 {
+	basis := quantity * l.CostPerUnit
+	if l.Quantity > 0 {
+		basis += l.Fees * quantity / l.Quantity
+	}
+	return basis
+}
+
+// LotDisposal is the part of a lot a sale closed. Proceeds are net of the
+// sale's fees and CostBasis includes the purchase's, both in Currency.
+type LotDisposal struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PortfolioID primitive.ObjectID `bson:"portfolioId" json:"portfolioId"`
+	LotID       primitive.ObjectID `bson:"lotId" json:"lotId"`
+	TradeID     primitive.ObjectID `bson:"tradeId,omitempty" json:"tradeId,omitempty"`
+	OrderID     primitive.ObjectID `bson:"orderId,omitempty" json:"orderId,omitempty"`
+	Symbol      string             `bson:"symbol" json:"symbol"`
+	Currency    string             `bson:"currency,omitempty" json:"currency,omitempty"`
+	Quantity    float64            `bson:"quantity" json:"quantity"`
+	AcquiredAt  time.Time          `bson:"acquiredAt" json:"acquiredAt"`
+	DisposedAt  time.Time          `bson:"disposedAt" json:"disposedAt"`
+	Proceeds    float64            `bson:"proceeds" json:"proceeds"`
+	CostBasis   float64            `bson:"costBasis" json:"costBasis"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
 }
@@ -16,6 +16,7 @@ type PortfolioRepository struct {
 	portfolioCollection   *mongo.Collection
 	positionCollection    *mongo.Collection
 	positionLotCollection *mongo.Collection
+	lotDisposalCollection *mongo.Collection
 }
 
 func NewPortfolioRepository() *PortfolioRepository {
@@ -23,6 +24,7 @@ func NewPortfolioRepository() *PortfolioRepository {
 		portfolioCollection:   database.GetCollection(model.PortfolioCollection),
 		positionCollection:    database.GetCollection(model.PositionCollection),
 		positionLotCollection: database.GetCollection(model.PositionLotCollection),
+		lotDisposalCollection: database.GetCollection(model.LotDisposalCollection),
 	}
 }
 
@@ -74,6 +76,30 @@ func (r *PortfolioRepository) FindPortfoliosByUserID(ctx context.Context, userID
 	return portfolios, nil
 }
 
+// FindPortfoliosByAccountID returns the portfolios trading through an account
+func (r *PortfolioRepository) FindPortfoliosByAccountID(ctx context.Context, accountID primitive.ObjectID) ([]model.Portfolio, error) {
+	cursor, err := r.portfolioCollection.Find(ctx, bson.M{"accountId": accountID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var portfolios []model.Portfolio
+	if err := cursor.All(ctx, &portfolios); err != nil {
+		return nil, err
+	}
+	return portfolios, nil
+}
+
+// CountOpenPositions counts positions still holding a quantity across
+// portfolios
+func (r *PortfolioRepository) CountOpenPositions(ctx context.Context, portfolioIDs []primitive.ObjectID) (int64, error) {
+	return r.positionCollection.CountDocuments(ctx, bson.M{
+		"portfolioId": bson.M{"$in": portfolioIDs},
+		"quantity":    bson.M{"$gt": 0},
+	})
+}
+
 func (r *PortfolioRepository) UpdatePortfolio(ctx context.Context, id primitive.ObjectID, update bson.M) error {
 	update["updatedAt"] = time.Now()
 	_, err := r.portfolioCollection.UpdateByID(ctx, id, bson.M{"$set": update})
@@ -204,3 +230,51 @@ func (r *PortfolioRepository) UpdatePositionLot(ctx context.Context, id primitiv
 	})
 	return err
 }
+
+// FindLotsByPortfolioIDs returns every lot in the portfolios, closed ones
+// included, oldest first
+func (r *PortfolioRepository) FindLotsByPortfolioIDs(ctx context.Context, portfolioIDs []primitive.ObjectID) ([]model.PositionLot, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "purchasedAt", Value: 1}})
+	cursor, err := r.positionLotCollection.Find(ctx, bson.M{"portfolioId": bson.M{"$in": portfolioIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var lots []model.PositionLot
+	if err := cursor.All(ctx, &lots); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// ==================== Lot Disposal Methods ====================
+
+func (r *PortfolioRepository) CreateLotDisposal(ctx context.Context, disposal *model.LotDisposal) error {
+	disposal.CreatedAt = time.Now()
+
+	result, err := r.lotDisposalCollection.InsertOne(ctx, disposal)
+	if err != nil {
+		return err
+	}
+
+	disposal.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindDisposalsByPortfolioIDs returns every lot disposal in the
+// portfolios, oldest first
+func (r *PortfolioRepository) FindDisposalsByPortfolioIDs(ctx context.Context, portfolioIDs []primitive.ObjectID) ([]model.LotDisposal, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "disposedAt", Value: 1}})
+	cursor, err := r.lotDisposalCollection.Find(ctx, bson.M{"portfolioId": bson.M{"$in": portfolioIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var disposals []model.LotDisposal
+	if err := cursor.All(ctx, &disposals); err != nil {
+		return nil, err
+	}
+	return disposals, nil
+}
@@ -13,12 +13,18 @@ func RegisterRoutes(app *fiber.App) {
 	repo := repository.NewPortfolioRepository()
 	portfolioSvc := service.NewPortfolioService(repo)
 	ctrl := controller.NewPortfolioController(portfolioSvc)
+	taxCtrl := controller.NewTaxController(service.NewTaxService(repo))
 
 	// All routes are protected
 	portfolios := app.Group("/api/v1/portfolios", middleware.AuthRequired())
 
 	portfolios.Post("/", ctrl.CreatePortfolio)
 	portfolios.Get("/", ctrl.GetPortfolios)
+
+	// Realized gains across the user's portfolios, before /:id claims the path
+	portfolios.Get("/realized-gains", taxCtrl.GetRealizedGains)
+	portfolios.Get("/realized-gains/export", taxCtrl.ExportRealizedGains)
+
 	portfolios.Get("/:id", ctrl.GetPortfolioByID)
 	portfolios.Get("/:id/summary", ctrl.GetPortfolioSummary)
 	portfolios.Put("/:id", ctrl.UpdatePortfolio)
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sale is a fill that reduces a position. Fees are the sale's total fees,
+// in the instrument currency.
+type Sale struct {
+	PortfolioID primitive.ObjectID
+	PositionID  primitive.ObjectID
+	TradeID     primitive.ObjectID
+	OrderID     primitive.ObjectID
+	Symbol      string
+	Currency    string
+	Quantity    float64
+	Price       float64
+	Fees        float64
+	SoldAt      time.Time
+}
+
+// LotService keeps a position's tax lots: one opens for each purchase and
+// sales close them first in, first out, recording what each closed
+type LotService struct {
+	repo *repository.PortfolioRepository
+}
+
+func NewLotService(repo *repository.PortfolioRepository) *LotService {
+	return &LotService{repo: repo}
+}
+
+// Open records a purchase as a new lot
+func (s *LotService) Open(ctx context.Context, lot *model.PositionLot) error {
+	lot.RemainingQty = lot.Quantity
+	return s.repo.CreatePositionLot(ctx, lot)
+}
+
+// Close takes a sale out of the position's open lots, oldest first, and
+// records a disposal for each lot it touches. The sale's proceeds and
+// fees are shared between them by quantity. Quantity beyond the open
+// lots (positions from before lots were kept) is not recorded.
+func (s *LotService) Close(ctx context.Context, sale *Sale) ([]model.LotDisposal, error) {
+	lots, err := s.repo.FindLotsByPositionID(ctx, sale.PositionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var disposals []model.LotDisposal
+	remaining := sale.Quantity
+	for i := range lots {
+		if remaining <= 0 {
+			break
+		}
+		lot := &lots[i]
+		quantity := math.Min(lot.RemainingQty, remaining)
+		if err := s.repo.UpdatePositionLot(ctx, lot.ID, lot.RemainingQty-quantity); err != nil {
+			return disposals, err
+		}
+		remaining -= quantity
+
+		symbol, currency := lot.Symbol, lot.Currency
+		if symbol == "" {
+			symbol = sale.Symbol
+		}
+		if currency == "" {
+			currency = sale.Currency
+		}
+		disposal := model.LotDisposal{
+			PortfolioID: sale.PortfolioID,
+			LotID:       lot.ID,
+			TradeID:     sale.TradeID,
+			OrderID:     sale.OrderID,
+			Symbol:      symbol,
+			Currency:    currency,
+			Quantity:    quantity,
+			AcquiredAt:  lot.PurchasedAt,
+			DisposedAt:  sale.SoldAt,
+			Proceeds:    quantity*sale.Price - sale.Fees*quantity/sale.Quantity,
+			CostBasis:   lot.CostBasis(quantity),
+		}
+		if err := s.repo.CreateLotDisposal(ctx, &disposal); err != nil {
+			return disposals, err
+		}
+		disposals = append(disposals, disposal)
+	}
+	return disposals, nil
+}
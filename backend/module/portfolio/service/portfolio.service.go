@@ -3,7 +3,11 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 
+	accountRepo "github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	instrumentRepo "github.com/bricksocoolxd/bengi-investment-system/module/instrument/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
 	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/dto"
 	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/model"
 	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/repository"
@@ -15,14 +19,26 @@ var (
 	ErrPortfolioNotFound = errors.New("portfolio not found")
 	ErrPositionNotFound  = errors.New("position not found")
 	ErrUnauthorized      = errors.New("unauthorized access")
+	ErrNoFXRate          = errors.New("no fx rate into the requested currency")
 )
 
+// defaultBaseCurrency reports summaries whose account can't be found
+const defaultBaseCurrency = "USD"
+
 type PortfolioService struct {
-	repo *repository.PortfolioRepository
+	repo           *repository.PortfolioRepository
+	accountRepo    *accountRepo.AccountRepository
+	instrumentRepo *instrumentRepo.InstrumentRepository
+	rates          fx.Provider
 }
 
 func NewPortfolioService(repo *repository.PortfolioRepository) *PortfolioService {
-	return &PortfolioService{repo: repo}
+	return &PortfolioService{
+		repo:           repo,
+		accountRepo:    accountRepo.NewAccountRepository(),
+		instrumentRepo: instrumentRepo.NewInstrumentRepository(),
+		rates:          fx.Default(),
+	}
 }
 
 // ==================== Portfolio Methods ====================
@@ -78,7 +94,9 @@ func (s *PortfolioService) GetPortfolioByID(ctx context.Context, portfolioID, us
 	return s.toPortfolioResponse(portfolio), nil
 }
 
-func (s *PortfolioService) GetPortfolioSummary(ctx context.Context, portfolioID, userID string) (*dto.PortfolioSummary, error) {
+// GetPortfolioSummary returns the portfolio's positions with totals in
+// currency, or in the portfolio account's currency if currency is empty
+func (s *PortfolioService) GetPortfolioSummary(ctx context.Context, portfolioID, userID, currency string) (*dto.PortfolioSummary, error) {
 	portfolio, err := s.repo.FindPortfolioByID(ctx, portfolioID)
 	if err != nil {
 		return nil, ErrPortfolioNotFound
@@ -88,6 +106,14 @@ func (s *PortfolioService) GetPortfolioSummary(ctx context.Context, portfolioID,
 		return nil, ErrUnauthorized
 	}
 
+	base := strings.ToUpper(currency)
+	if base == "" {
+		base = defaultBaseCurrency
+		if account, err := s.accountRepo.FindByID(ctx, portfolio.AccountID.Hex()); err == nil && account.Currency != "" {
+			base = account.Currency
+		}
+	}
+
 	positions, err := s.repo.FindPositionsByPortfolioID(ctx, portfolioID)
 	if err != nil {
 		return nil, err
@@ -96,12 +122,26 @@ func (s *PortfolioService) GetPortfolioSummary(ctx context.Context, portfolioID,
 	var positionResponses []dto.PositionResponse
 	var totalCost float64
 	var totalValue float64
+	rates := make(map[string]float64)
 
 	for _, pos := range positions {
 		resp := s.toPositionResponse(&pos)
+		if instrument, err := s.instrumentRepo.FindByID(ctx, pos.InstrumentID.Hex()); err == nil {
+			resp.Currency = instrument.Currency
+		}
 		positionResponses = append(positionResponses, *resp)
-		totalCost += pos.TotalCost
-		totalValue += pos.TotalCost // Will be replaced with market value when integrated with price service
+
+		// Positions of unknown currency are taken to be in the base
+		rate := 1.0
+		if resp.Currency != "" && resp.Currency != base {
+			if rate, err = s.rates.Rate(resp.Currency, base); err != nil {
+				return nil, ErrNoFXRate
+			}
+			rates[resp.Currency] = rate
+		}
+
+		totalCost += pos.TotalCost * rate
+		totalValue += pos.TotalCost * rate // Will be replaced with market value when integrated with price service
 	}
 
 	totalPnL := totalValue - totalCost
@@ -113,6 +153,8 @@ func (s *PortfolioService) GetPortfolioSummary(ctx context.Context, portfolioID,
 	return &dto.PortfolioSummary{
 		Portfolio:   *s.toPortfolioResponse(portfolio),
 		Positions:   positionResponses,
+		Currency:    base,
+		Rates:       rates,
 		TotalValue:  totalValue,
 		TotalCost:   totalCost,
 		TotalPnL:    totalPnL,
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/tax"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaxService reports realized gains from a user's closed lots. Wash
+// sales are found across all of the user's portfolios, as the rule
+// applies to the taxpayer rather than one account.
+type TaxService struct {
+	repo *repository.PortfolioRepository
+}
+
+func NewTaxService(repo *repository.PortfolioRepository) *TaxService {
+	return &TaxService{repo: repo}
+}
+
+// RealizedGains reports the lots a user closed in a year, optionally in
+// one of their portfolios
+func (s *TaxService) RealizedGains(ctx context.Context, userID string, year int, portfolioID string) (*dto.RealizedGainsResponse, error) {
+	rows, err := s.report(ctx, userID, year, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.RealizedGainsResponse{
+		Year:   year,
+		Gains:  make([]dto.RealizedGainResponse, 0, len(rows)),
+		Totals: make([]dto.RealizedGainsTotals, 0),
+	}
+	for _, row := range rows {
+		response.Gains = append(response.Gains, dto.RealizedGainResponse{
+			LotID:       row.LotID,
+			PortfolioID: row.PortfolioID,
+			Symbol:      row.Symbol,
+			Currency:    row.Currency,
+			Quantity:    row.Quantity,
+			AcquiredAt:  row.Acquired.UTC().Format("2006-01-02"),
+			DisposedAt:  row.Disposed.UTC().Format("2006-01-02"),
+			Proceeds:    roundCents(row.Proceeds),
+			CostBasis:   roundCents(row.CostBasis),
+			Code:        row.Code,
+			Adjustment:  roundCents(row.Adjustment),
+			Gain:        roundCents(row.Gain),
+			Term:        string(row.Term),
+		})
+	}
+	for _, totals := range tax.Summarize(rows) {
+		response.Totals = append(response.Totals, dto.RealizedGainsTotals{
+			Term:       string(totals.Term),
+			Currency:   totals.Currency,
+			Proceeds:   roundCents(totals.Proceeds),
+			CostBasis:  roundCents(totals.CostBasis),
+			Adjustment: roundCents(totals.Adjustment),
+			Gain:       roundCents(totals.Gain),
+		})
+	}
+	return response, nil
+}
+
+// ExportRealizedGains is RealizedGains as Form 8949 CSV
+func (s *TaxService) ExportRealizedGains(ctx context.Context, userID string, year int, portfolioID string) ([]byte, error) {
+	rows, err := s.report(ctx, userID, year, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	return tax.CSV(rows)
+}
+
+// report builds the year's rows from every lot and disposal the user has,
+// since purchases in other years and portfolios can make a wash sale
+func (s *TaxService) report(ctx context.Context, userID string, year int, portfolioID string) ([]tax.Row, error) {
+	portfolios, err := s.repo.FindPortfoliosByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(portfolios))
+	owned := portfolioID == ""
+	for _, portfolio := range portfolios {
+		ids = append(ids, portfolio.ID)
+		if portfolio.ID.Hex() == portfolioID {
+			owned = true
+		}
+	}
+	if !owned {
+		return nil, ErrPortfolioNotFound
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	lots, err := s.repo.FindLotsByPortfolioIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	disposals, err := s.repo.FindDisposalsByPortfolioIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	taxLots := make([]tax.Lot, 0, len(lots))
+	for _, lot := range lots {
+		taxLots = append(taxLots, tax.Lot{
+			ID:       lot.ID.Hex(),
+			Symbol:   lot.Symbol,
+			Quantity: lot.Quantity,
+			Acquired: lot.PurchasedAt,
+		})
+	}
+	taxDisposals := make([]tax.Disposal, 0, len(disposals))
+	for _, disposal := range disposals {
+		taxDisposals = append(taxDisposals, tax.Disposal{
+			LotID:       disposal.LotID.Hex(),
+			PortfolioID: disposal.PortfolioID.Hex(),
+			Symbol:      disposal.Symbol,
+			Currency:    disposal.Currency,
+			Quantity:    disposal.Quantity,
+			Acquired:    disposal.AcquiredAt,
+			Disposed:    disposal.DisposedAt,
+			Proceeds:    disposal.Proceeds,
+			CostBasis:   disposal.CostBasis,
+		})
+	}
+
+	rows := tax.InYear(tax.Report(taxLots, taxDisposals), year)
+	if portfolioID == "" {
+		return rows, nil
+	}
+	var kept []tax.Row
+	for _, row := range rows {
+		if row.PortfolioID == portfolioID {
+			kept = append(kept, row)
+		}
+	}
+	return kept, nil
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,79 @@
+package tax
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+)
+
+// csvHeader follows the columns of IRS Form 8949, (a) to (h), after the
+// part the row belongs on, so tax software can import the file directly
+var csvHeader = []string{
+	"Part",
+	"Description of property",
+	"Date acquired",
+	"Date sold or disposed of",
+	"Proceeds",
+	"Cost or other basis",
+	"Code",
+	"Amount of adjustment",
+	"Gain or (loss)",
+	"Currency",
+}
+
+// formDate is the MM/DD/YYYY date the form uses
+const formDate = "01/02/2006"
+
+// CSV writes rows in Form 8949 layout: short-term rows (Part I) before
+// long-term ones (Part II), each in disposal order. Amounts are rounded
+// to cents.
+func CSV(rows []Row) ([]byte, error) {
+	sorted := append([]Row(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Term != sorted[j].Term {
+			return sorted[i].Term == ShortTerm
+		}
+		return sorted[i].Disposed.Before(sorted[j].Disposed)
+	})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, row := range sorted {
+		part := "I"
+		if row.Term == LongTerm {
+			part = "II"
+		}
+		adjustment := ""
+		if row.Adjustment != 0 {
+			adjustment = cents(row.Adjustment)
+		}
+		if err := w.Write([]string{
+			part,
+			strconv.FormatFloat(row.Quantity, 'f', -1, 64) + " sh " + row.Symbol,
+			row.Acquired.UTC().Format(formDate),
+			row.Disposed.UTC().Format(formDate),
+			cents(row.Proceeds),
+			cents(row.CostBasis),
+			row.Code,
+			adjustment,
+			cents(row.Gain),
+			row.Currency,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func cents(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	if s == "-0.00" {
+		return "0.00"
+	}
+	return s
+}
@@ -0,0 +1,247 @@
+// Package tax turns closed tax lots into a realized gains report: one row
+// per disposal with its holding term, and losses disallowed by the
+// wash-sale rule carried into the basis of the replacement shares.
+package tax
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// WashSaleWindow is how far either side of a loss sale a purchase of the
+// same security makes the loss a wash sale
+const WashSaleWindow = 30 * 24 * time.Hour
+
+// Term is a gain's holding period classification
+type Term string
+
+const (
+	ShortTerm Term = "SHORT" // Held one year or less
+	LongTerm  Term = "LONG"  // Held more than one year
+)
+
+// CodeWashSale marks a row whose loss was partly or wholly disallowed
+const CodeWashSale = "W"
+
+// Lot is a purchase: a candidate replacement for a wash sale
+type Lot struct {
+	ID       string
+	Symbol   string
+	Quantity float64
+	Acquired time.Time
+}
+
+// Disposal is the part of a lot a sale closed. Proceeds are net of the
+// sale's fees; CostBasis includes the purchase's.
+type Disposal struct {
+	LotID       string
+	PortfolioID string
+	Symbol      string
+	Currency    string
+	Quantity    float64
+	Acquired    time.Time
+	Disposed    time.Time
+	Proceeds    float64
+	CostBasis   float64
+}
+
+// Row is one line of the report. CostBasis includes wash-sale basis
+// carried in from earlier sales; Adjustment is the loss this row
+// disallows, which Gain adds back.
+type Row struct {
+	LotID       string
+	PortfolioID string
+	Symbol      string
+	Currency    string
+	Quantity    float64
+	Acquired    time.Time // Includes holding period carried in by a wash sale
+	Disposed    time.Time
+	Proceeds    float64
+	CostBasis   float64
+	Code        string
+	Adjustment  float64
+	Gain        float64
+	Term        Term
+}
+
+// TermOf classifies a holding period: long-term once held more than one
+// year
+func TermOf(acquired, disposed time.Time) Term {
+	if disposed.After(acquired.AddDate(1, 0, 0)) {
+		return LongTerm
+	}
+	return ShortTerm
+}
+
+// carried is wash-sale basis and holding period waiting on replacement
+// shares until they are sold
+type carried struct {
+	quantity float64
+	basis    float64
+	acquired time.Time
+}
+
+// Report builds rows for every disposal, in disposal order. A loss is a
+// wash sale to the extent shares of the same symbol were bought within
+// WashSaleWindow of the sale, other than the lot sold, and held at it.
+// Each replacement share absorbs one wash sale: the disallowed loss moves
+// into its basis and the sold shares' holding period into its own, both
+// applied when it is sold. Disposals of a lot use its carried basis
+// first.
+func Report(lots []Lot, disposals []Disposal) []Row {
+	sorted := append([]Disposal(nil), disposals...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Disposed.Before(sorted[j].Disposed) })
+
+	bySymbol := map[string][]Lot{}
+	for _, lot := range lots {
+		bySymbol[lot.Symbol] = append(bySymbol[lot.Symbol], lot)
+	}
+	for _, symbolLots := range bySymbol {
+		sort.SliceStable(symbolLots, func(i, j int) bool { return symbolLots[i].Acquired.Before(symbolLots[j].Acquired) })
+	}
+
+	disposed := map[string]float64{} // Lot quantity sold so far
+	used := map[string]float64{}     // Lot quantity already a replacement
+	carry := map[string][]carried{}  // Wash-sale adjustments by lot
+
+	var rows []Row
+	for _, d := range sorted {
+		if d.Quantity <= 0 {
+			continue
+		}
+		pieces := takeCarried(carry, d)
+		disposed[d.LotID] += d.Quantity
+
+		for _, row := range pieces {
+			if loss := row.Proceeds - row.CostBasis; loss < 0 {
+				washSale(&row, -loss, d, bySymbol[d.Symbol], disposed, used, carry)
+			}
+			row.Gain = row.Proceeds - row.CostBasis + row.Adjustment
+			row.Term = TermOf(row.Acquired, row.Disposed)
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// takeCarried splits a disposal into rows: shares carrying wash-sale
+// adjustments first, each with its own basis and holding period, then
+// the rest
+func takeCarried(carry map[string][]carried, d Disposal) []Row {
+	row := func(quantity, extraBasis float64, acquired time.Time) Row {
+		share := quantity / d.Quantity
+		return Row{
+			LotID:       d.LotID,
+			PortfolioID: d.PortfolioID,
+			Symbol:      d.Symbol,
+			Currency:    d.Currency,
+			Quantity:    quantity,
+			Acquired:    acquired,
+			Disposed:    d.Disposed,
+			Proceeds:    d.Proceeds * share,
+			CostBasis:   d.CostBasis*share + extraBasis,
+		}
+	}
+
+	var rows []Row
+	remaining := d.Quantity
+	adjustments := carry[d.LotID]
+	for len(adjustments) > 0 && remaining > 1e-9 {
+		adjustment := &adjustments[0]
+		quantity := math.Min(adjustment.quantity, remaining)
+		basis := adjustment.basis * quantity / adjustment.quantity
+		rows = append(rows, row(quantity, basis, adjustment.acquired))
+
+		adjustment.quantity -= quantity
+		adjustment.basis -= basis
+		remaining -= quantity
+		if adjustment.quantity <= 1e-9 {
+			adjustments = adjustments[1:]
+		}
+	}
+	carry[d.LotID] = adjustments
+
+	if remaining > 1e-9 {
+		rows = append(rows, row(remaining, 0, d.Acquired))
+	}
+	return rows
+}
+
+// washSale disallows as much of a row's loss as replacement shares cover
+// and carries it to them
+func washSale(row *Row, loss float64, d Disposal, candidates []Lot, disposed, used map[string]float64, carry map[string][]carried) {
+	need := row.Quantity
+	held := d.Disposed.Sub(row.Acquired)
+	for _, lot := range candidates {
+		if need <= 1e-9 {
+			return
+		}
+		if lot.ID == d.LotID || lot.Acquired.Before(d.Disposed.Add(-WashSaleWindow)) || lot.Acquired.After(d.Disposed.Add(WashSaleWindow)) {
+			continue
+		}
+		available := lot.Quantity - math.Max(used[lot.ID], disposed[lot.ID])
+		if available <= 1e-9 {
+			continue
+		}
+
+		quantity := math.Min(available, need)
+		disallowed := loss * quantity / row.Quantity
+		row.Code = CodeWashSale
+		row.Adjustment += disallowed
+		used[lot.ID] += quantity
+		need -= quantity
+		carry[lot.ID] = append(carry[lot.ID], carried{
+			quantity: quantity,
+			basis:    disallowed,
+			acquired: lot.Acquired.Add(-held),
+		})
+	}
+}
+
+// InYear keeps the rows disposed of in a calendar year, in UTC
+func InYear(rows []Row, year int) []Row {
+	var kept []Row
+	for _, row := range rows {
+		if row.Disposed.UTC().Year() == year {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
+
+// Totals sums rows of one term and currency
+type Totals struct {
+	Term       Term
+	Currency   string
+	Proceeds   float64
+	CostBasis  float64
+	Adjustment float64
+	Gain       float64
+}
+
+// Summarize totals rows by term, then currency: short-term first
+func Summarize(rows []Row) []Totals {
+	index := map[[2]string]int{}
+	var totals []Totals
+	for _, row := range rows {
+		key := [2]string{string(row.Term), row.Currency}
+		i, ok := index[key]
+		if !ok {
+			i = len(totals)
+			index[key] = i
+			totals = append(totals, Totals{Term: row.Term, Currency: row.Currency})
+		}
+		totals[i].Proceeds += row.Proceeds
+		totals[i].CostBasis += row.CostBasis
+		totals[i].Adjustment += row.Adjustment
+		totals[i].Gain += row.Gain
+	}
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].Term != totals[j].Term {
+			return totals[i].Term == ShortTerm
+		}
+		return totals[i].Currency < totals[j].Currency
+	})
+	return totals
+}
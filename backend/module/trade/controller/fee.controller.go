@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/fees"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FeeController struct {
+	feeService *service.FeeService
+}
+
+func NewFeeController(feeService *service.FeeService) *FeeController {
+	return &FeeController{
+		feeService: feeService,
+	}
+}
+
+// GetSchedule returns the fee plans and regulatory fees in force
+// GET /api/v1/fees/schedule
+func (ctrl *FeeController) GetSchedule(c *fiber.Ctx) error {
+	return common.Success(c, ctrl.feeService.GetSchedule(), "")
+}
+
+// SetAccountPlan assigns an account's fee plan
+// PUT /api/v1/fees/accounts/:id/plan
+func (ctrl *FeeController) SetAccountPlan(c *fiber.Ctx) error {
+	var req dto.FeePlanRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.feeService.SetAccountPlan(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, fees.ErrUnknownPlan):
+			return common.BadRequest(c, err.Error())
+		case errors.Is(err, service.ErrFeeAccountNotFound):
+			return common.NotFound(c, "Account not found")
+		}
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, result, "Fee plan updated")
+}
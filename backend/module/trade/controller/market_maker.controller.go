@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type MarketMakerController struct {
+	marketMakerService *service.MarketMakerService
+}
+
+func NewMarketMakerController(marketMakerService *service.MarketMakerService) *MarketMakerController {
+	return &MarketMakerController{
+		marketMakerService: marketMakerService,
+	}
+}
+
+// GetStatus lists quoting configs with live inventory
+// GET /api/v1/market-maker
+func (ctrl *MarketMakerController) GetStatus(c *fiber.Ctx) error {
+	return common.Success(c, ctrl.marketMakerService.GetStatus(), "")
+}
+
+// SetConfig creates or replaces a symbol's quoting parameters
+// PUT /api/v1/market-maker/:symbol
+func (ctrl *MarketMakerController) SetConfig(c *fiber.Ctx) error {
+	var req dto.MarketMakerConfigRequest
+	if validationErrors := utils.ParseAndValidate(c, &req); validationErrors != nil {
+		return common.ValidationError(c, validationErrors)
+	}
+
+	result, err := ctrl.marketMakerService.SetConfig(c.Context(), c.Params("symbol"), &req)
+	if err != nil {
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, result, "Market maker config saved")
+}
+
+// RemoveConfig stops quoting a symbol
+// DELETE /api/v1/market-maker/:symbol
+func (ctrl *MarketMakerController) RemoveConfig(c *fiber.Ctx) error {
+	if err := ctrl.marketMakerService.RemoveConfig(c.Context(), c.Params("symbol")); err != nil {
+		if errors.Is(err, service.ErrMarketMakerConfigNotFound) {
+			return common.NotFound(c, "Market maker config not found")
+		}
+		return common.InternalError(c, err.Error())
+	}
+	return common.Success(c, nil, "Market maker config removed")
+}
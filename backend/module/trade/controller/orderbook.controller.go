@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrderBookController struct {
+	orderBookService *service.OrderBookService
+}
+
+func NewOrderBookController(orderBookService *service.OrderBookService) *OrderBookController {
+	return &OrderBookController{
+		orderBookService: orderBookService,
+	}
+}
+
+// GetOrderBook returns aggregated price levels for a symbol
+// GET /api/v1/orderbook/:symbol?depth=N
+func (ctrl *OrderBookController) GetOrderBook(c *fiber.Ctx) error {
+	depth := c.QueryInt("depth", service.DefaultBookDepth)
+	if depth <= 0 {
+		return common.BadRequest(c, "depth must be positive")
+	}
+
+	return common.Success(c, ctrl.orderBookService.GetOrderBook(c.Params("symbol"), depth), "")
+}
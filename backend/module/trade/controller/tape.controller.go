@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/common"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TapeController struct {
+	tapeService *service.TapeService
+}
+
+func NewTapeController(tapeService *service.TapeService) *TapeController {
+	return &TapeController{
+		tapeService: tapeService,
+	}
+}
+
+// GetTape returns the public time & sales for a symbol
+// GET /api/v1/instruments/:symbol/trades?limit=N&before=printId
+func (ctrl *TapeController) GetTape(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", service.DefaultTapeLimit)
+	if limit <= 0 {
+		return common.BadRequest(c, "limit must be positive")
+	}
+
+	result, err := ctrl.tapeService.GetTape(c.Context(), c.Params("symbol"), c.Query("before"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			return common.BadRequest(c, "Invalid before cursor")
+		}
+		return common.InternalError(c, err.Error())
+	}
+
+	return common.Success(c, result, "")
+}
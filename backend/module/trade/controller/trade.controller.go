@@ -42,6 +42,12 @@ func (ctrl *TradeController) ExecuteTrade(c *fiber.Ctx) error {
 			return common.BadRequest(c, "Insufficient balance")
 		case errors.Is(err, service.ErrInsufficientShares):
 			return common.BadRequest(c, "Insufficient shares")
+		case errors.Is(err, service.ErrNoFXRate):
+			return common.BadRequest(c, "No exchange rate between the instrument and account currencies")
+		case errors.Is(err, service.ErrAccountFrozen):
+			return common.BadRequest(c, "Account is frozen")
+		case errors.Is(err, service.ErrAccountClosed):
+			return common.BadRequest(c, "Account is closed")
 		default:
 			return common.InternalError(c, err.Error())
 		}
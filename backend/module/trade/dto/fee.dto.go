@@ -0,0 +1,14 @@
+package dto
+
+type (
+	// FeePlanRequest assigns an account to a fee plan
+	FeePlanRequest struct {
+		Plan string `json:"plan" validate:"required"`
+	}
+
+	// FeePlanResponse is an account's fee plan
+	FeePlanResponse struct {
+		AccountID string `json:"accountId"`
+		Plan      string `json:"plan"`
+	}
+)
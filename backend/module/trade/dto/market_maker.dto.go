@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type (
+	// MarketMakerConfigRequest sets the quoting parameters for a symbol
+	MarketMakerConfigRequest struct {
+		Enabled         bool    `json:"enabled"`
+		SpreadBps       float64 `json:"spreadBps" validate:"required,gt=0,lte=1000"`
+		LevelSpacingBps float64 `json:"levelSpacingBps" validate:"gte=0,lte=1000"`
+		Levels          int     `json:"levels" validate:"required,min=1,max=20"`
+		Size            float64 `json:"size" validate:"required,gt=0"`
+		MaxInventory    float64 `json:"maxInventory" validate:"gte=0"` // 0 = unlimited
+		SkewBps         float64 `json:"skewBps" validate:"gte=0,lte=1000"`
+		TickSize        float64 `json:"tickSize" validate:"gte=0"` // 0 = 0.01
+	}
+
+	// MarketMakerResponse is a symbol's config with the bot's live state
+	MarketMakerResponse struct {
+		Symbol          string    `json:"symbol"`
+		Enabled         bool      `json:"enabled"`
+		SpreadBps       float64   `json:"spreadBps"`
+		LevelSpacingBps float64   `json:"levelSpacingBps"`
+		Levels          int       `json:"levels"`
+		Size            float64   `json:"size"`
+		MaxInventory    float64   `json:"maxInventory"`
+		SkewBps         float64   `json:"skewBps"`
+		TickSize        float64   `json:"tickSize"`
+		Inventory       float64   `json:"inventory"`      // Net position from fills, + long / - short
+		ReferencePrice  float64   `json:"referencePrice"` // Price the working quotes are built around
+		WorkingQuotes   int       `json:"workingQuotes"`
+		UpdatedAt       time.Time `json:"updatedAt"`
+	}
+)
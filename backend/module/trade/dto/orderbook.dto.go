@@ -0,0 +1,19 @@
+package dto
+
+type (
+	PriceLevelResponse struct {
+		Price    float64 `json:"price"`
+		Quantity float64 `json:"quantity"`
+		Orders   int     `json:"orders"`
+	}
+
+	// OrderBookResponse is an aggregated (level 2) view of a book.
+	// Sequence matches the book:SYMBOL WebSocket updates.
+	OrderBookResponse struct {
+		Symbol   string               `json:"symbol"`
+		Sequence uint64               `json:"sequence"`
+		Phase    string               `json:"phase"`
+		Bids     []PriceLevelResponse `json:"bids"`
+		Asks     []PriceLevelResponse `json:"asks"`
+	}
+)
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type (
+	// PrintResponse is one anonymized execution on the public tape
+	PrintResponse struct {
+		ID            string    `json:"id"`
+		Price         float64   `json:"price"`
+		Quantity      float64   `json:"quantity"`
+		AggressorSide string    `json:"aggressorSide,omitempty"` // BUY or SELL; empty for auction uncrosses
+		ExecutedAt    time.Time `json:"executedAt"`
+	}
+
+	// TapeResponse is a page of a symbol's time & sales, newest first.
+	// Pass Next as ?before= to fetch older prints.
+	TapeResponse struct {
+		Symbol string          `json:"symbol"`
+		Prints []PrintResponse `json:"prints"`
+		Next   string          `json:"next,omitempty"`
+	}
+)
@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/bricksocoolxd/bengi-investment-system/module/trade/fees"
+
 type (
 	ExecuteTradeRequest struct {
 		OrderID  string  `json:"orderId" validate:"required"`
@@ -22,6 +24,9 @@ type (
 		Commission   float64 `json:"commission"`
 		NetAmount    float64 `json:"netAmount"`
 		ExecutedAt   string  `json:"executedAt"`
+		SettleDate   string  `json:"settleDate,omitempty"`
+
+		Fees *fees.Breakdown `json:"fees,omitempty"`
 	}
 
 	TradeListResponse struct {
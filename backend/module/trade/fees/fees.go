@@ -0,0 +1,288 @@
+// Package fees prices what a fill costs beyond its trade value: the
+// house commission, set by the account's fee plan, instrument type and
+// 30-day volume, and regulatory fees passed through to the client.
+package fees
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VolumeWindow is the rolling period volume tiers look back over
+const VolumeWindow = 30 * 24 * time.Hour
+
+// DefaultPlan is the plan for accounts without one of their own
+const DefaultPlan = "STANDARD"
+
+var (
+	ErrNoDefaultPlan = errors.New("fee schedule needs a " + DefaultPlan + " plan")
+	ErrUnknownPlan   = errors.New("unknown fee plan")
+)
+
+// Tier lowers the commission rate once an account's 30-day traded value,
+// in its own currency, reaches MinVolume
+type Tier struct {
+	MinVolume float64 `json:"minVolume"`
+	Rate      float64 `json:"rate"`
+}
+
+// Schedule prices commission for one instrument type. Min and Max bound
+// an order's total commission across its fills; 0 leaves them open.
+type Schedule struct {
+	Rate  float64 `json:"rate"`
+	Min   float64 `json:"min,omitempty"`
+	Max   float64 `json:"max,omitempty"`
+	Tiers []Tier  `json:"tiers,omitempty"`
+}
+
+// RateAt returns the commission rate at a 30-day volume and the tier it
+// comes from (0 for the base rate, 1 for the first tier)
+func (s Schedule) RateAt(volume float64) (float64, int) {
+	rate, tier, reached := s.Rate, 0, -1.0
+	for i, t := range s.Tiers {
+		if volume >= t.MinVolume && t.MinVolume > reached {
+			rate, tier, reached = t.Rate, i+1, t.MinVolume
+		}
+	}
+	return rate, tier
+}
+
+// Plan is a set of schedules by instrument type. The "" schedule covers
+// types without their own.
+type Plan struct {
+	Name      string              `json:"name"`
+	Schedules map[string]Schedule `json:"schedules"`
+}
+
+func (p Plan) schedule(instrumentType string) Schedule {
+	for name, schedule := range p.Schedules {
+		if strings.EqualFold(name, instrumentType) {
+			return schedule
+		}
+	}
+	return p.Schedules[""]
+}
+
+// RegulatoryFee is an exchange or regulator charge passed through at
+// cost. It is a share of trade value plus an amount per unit, capped per
+// fill by Max.
+type RegulatoryFee struct {
+	Name            string   `json:"name"`
+	Rate            float64  `json:"rate,omitempty"`
+	PerUnit         float64  `json:"perUnit,omitempty"`
+	Max             float64  `json:"max,omitempty"`
+	SellOnly        bool     `json:"sellOnly,omitempty"`
+	InstrumentTypes []string `json:"instrumentTypes,omitempty"` // Empty applies to every type
+}
+
+func (f RegulatoryFee) appliesTo(instrumentType string, buy bool) bool {
+	if f.SellOnly && buy {
+		return false
+	}
+	if len(f.InstrumentTypes) == 0 {
+		return true
+	}
+	for _, t := range f.InstrumentTypes {
+		if strings.EqualFold(t, instrumentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Config is a complete fee schedule
+type Config struct {
+	Plans      []Plan          `json:"plans"`
+	Regulatory []RegulatoryFee `json:"regulatory,omitempty"`
+}
+
+// LoadFile reads a Config from a JSON file
+func LoadFile(path string) (Config, error) {
+	var config Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	err = json.Unmarshal(data, &config)
+	return config, err
+}
+
+// Charge is one named fee
+type Charge struct {
+	Name   string  `bson:"name" json:"name"`
+	Amount float64 `bson:"amount" json:"amount"`
+}
+
+// Breakdown itemizes a fill's fees in the instrument currency
+type Breakdown struct {
+	Plan       string   `bson:"plan" json:"plan"`
+	Rate       float64  `bson:"rate" json:"rate"` // Commission rate applied
+	Tier       int      `bson:"tier" json:"tier"` // Volume tier reached; 0 is the base rate
+	Commission float64  `bson:"commission" json:"commission"`
+	Regulatory []Charge `bson:"regulatory,omitempty" json:"regulatory,omitempty"`
+	Total      float64  `bson:"total" json:"total"`
+}
+
+// RegulatoryTotal sums the pass-through fees
+func (b *Breakdown) RegulatoryTotal() float64 {
+	total := 0.0
+	for _, charge := range b.Regulatory {
+		total += charge.Amount
+	}
+	return total
+}
+
+// Fill is what Quote needs to price a fill
+type Fill struct {
+	Plan           string
+	InstrumentType string
+	Buy            bool
+	Quantity       float64
+	Price          float64
+	Volume         float64 // Account's traded value over the VolumeWindow
+	OrderValue     float64 // Value the order had already filled
+	OrderCharged   float64 // Commission already charged on the order
+}
+
+// Engine quotes fees from the current schedule
+type Engine struct {
+	mu     sync.RWMutex
+	config Config
+	plans  map[string]Plan
+}
+
+// NewEngine returns an engine for config
+func NewEngine(config Config) (*Engine, error) {
+	e := &Engine{}
+	if err := e.Load(config); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+var (
+	defaultEngine *Engine
+	defaultOnce   sync.Once
+)
+
+// Default returns the shared engine, loaded with the built-in schedule
+func Default() *Engine {
+	defaultOnce.Do(func() {
+		defaultEngine, _ = NewEngine(Builtin())
+	})
+	return defaultEngine
+}
+
+// Load replaces the schedule. It must have a DefaultPlan.
+func (e *Engine) Load(config Config) error {
+	plans := make(map[string]Plan, len(config.Plans))
+	for _, plan := range config.Plans {
+		plans[strings.ToUpper(plan.Name)] = plan
+	}
+	if _, ok := plans[DefaultPlan]; !ok {
+		return ErrNoDefaultPlan
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config = config
+	e.plans = plans
+	return nil
+}
+
+// Config returns the current schedule
+func (e *Engine) Config() Config {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config
+}
+
+// HasPlan reports whether the schedule has a plan
+func (e *Engine) HasPlan(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, ok := e.plans[strings.ToUpper(name)]
+	return ok
+}
+
+// Quote prices a fill. Commission is charged on the order's cumulative
+// value, so its Min and Max hold across partial fills: this fill pays the
+// order's commission so far less what earlier fills were charged.
+func (e *Engine) Quote(fill Fill) *Breakdown {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	plan, ok := e.plans[strings.ToUpper(fill.Plan)]
+	if !ok {
+		plan = e.plans[DefaultPlan]
+	}
+	schedule := plan.schedule(fill.InstrumentType)
+	rate, tier := schedule.RateAt(fill.Volume)
+
+	value := fill.Quantity * fill.Price
+	orderCommission := (fill.OrderValue + value) * rate
+	if schedule.Min > 0 && orderCommission < schedule.Min {
+		orderCommission = schedule.Min
+	}
+	if schedule.Max > 0 && orderCommission > schedule.Max {
+		orderCommission = schedule.Max
+	}
+
+	breakdown := &Breakdown{
+		Plan:       plan.Name,
+		Rate:       rate,
+		Tier:       tier,
+		Commission: roundCents(math.Max(0, orderCommission-fill.OrderCharged)),
+	}
+	for _, fee := range e.config.Regulatory {
+		if !fee.appliesTo(fill.InstrumentType, fill.Buy) {
+			continue
+		}
+		amount := value*fee.Rate + fill.Quantity*fee.PerUnit
+		if fee.Max > 0 && amount > fee.Max {
+			amount = fee.Max
+		}
+		if amount = roundCents(amount); amount > 0 {
+			breakdown.Regulatory = append(breakdown.Regulatory, Charge{Name: fee.Name, Amount: amount})
+		}
+	}
+	breakdown.Total = roundCents(breakdown.Commission + breakdown.RegulatoryTotal())
+	return breakdown
+}
+
+// Builtin is the house fee schedule: 0.1% standard commission falling
+// with volume, a PRO plan with per-order bounds, and the US SEC and
+// FINRA fees on equity sales
+func Builtin() Config {
+	return Config{
+		Plans: []Plan{
+			{
+				Name: DefaultPlan,
+				Schedules: map[string]Schedule{
+					"":       {Rate: 0.001, Tiers: []Tier{{MinVolume: 100000, Rate: 0.0008}, {MinVolume: 1000000, Rate: 0.0005}}},
+					"Crypto": {Rate: 0.0015, Tiers: []Tier{{MinVolume: 100000, Rate: 0.0012}, {MinVolume: 1000000, Rate: 0.001}}},
+				},
+			},
+			{
+				Name: "PRO",
+				Schedules: map[string]Schedule{
+					"":       {Rate: 0.0005, Min: 1, Max: 50, Tiers: []Tier{{MinVolume: 1000000, Rate: 0.0003}}},
+					"Crypto": {Rate: 0.001, Min: 1, Tiers: []Tier{{MinVolume: 1000000, Rate: 0.0006}}},
+				},
+			},
+		},
+		Regulatory: []RegulatoryFee{
+			{Name: "SEC", Rate: 0.0000278, SellOnly: true, InstrumentTypes: []string{"Stock", "ETF"}},
+			{Name: "FINRA TAF", PerUnit: 0.000166, Max: 8.30, SellOnly: true, InstrumentTypes: []string{"Stock", "ETF"}},
+		},
+	}
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
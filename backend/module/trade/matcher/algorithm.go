@@ -0,0 +1,153 @@
+package matcher
+
+import "math"
+
+// MatchAlgorithm decides how an incoming order's quantity is shared among
+// the resting orders at the price level it trades against
+type MatchAlgorithm string
+
+const (
+	AlgorithmFIFO     MatchAlgorithm = "FIFO"      // Price-time priority: oldest order first
+	AlgorithmProRata  MatchAlgorithm = "PRO_RATA"  // In proportion to resting size, leftovers by time
+	AlgorithmTopOrder MatchAlgorithm = "TOP_ORDER" // The order that set the best price first, then FIFO
+)
+
+// AlgorithmConfig configures the matching algorithm of an order book
+type AlgorithmConfig struct {
+	Algorithm MatchAlgorithm
+	// LotSize rounds pro-rata shares down to whole lots; 0 rounds to the
+	// smallest quantity the engine trades
+	LotSize float64
+	// TopOrderMax caps what the top order takes from each incoming order
+	// before the rest of the level is served; 0 means no cap
+	TopOrderMax float64
+}
+
+// DefaultAlgorithmConfig is used for books without their own config
+var DefaultAlgorithmConfig = AlgorithmConfig{Algorithm: AlgorithmFIFO}
+
+// Allocation is the quantity an incoming order trades with one resting order
+type Allocation struct {
+	Order    *Order
+	Quantity float64
+}
+
+// Allocate splits quantity across level, the resting orders at one price
+// in time priority. Each order gets at most what it has available, and
+// the shares never add up to more than quantity.
+func (c AlgorithmConfig) Allocate(level []*Order, quantity float64) []Allocation {
+	switch c.Algorithm {
+	case AlgorithmProRata:
+		return allocateProRata(level, quantity, c.LotSize)
+	case AlgorithmTopOrder:
+		return allocateTopOrder(level, quantity, c.TopOrderMax)
+	default:
+		return allocateFIFO(level, quantity)
+	}
+}
+
+// allocateFIFO fills the oldest order first. Only the head of the queue
+// is allocated; the engine comes back for the next order, so
+// self-trade checks happen one resting order at a time.
+func allocateFIFO(level []*Order, quantity float64) []Allocation {
+	if len(level) == 0 || quantity <= 0 {
+		return nil
+	}
+	return []Allocation{{Order: level[0], Quantity: min(quantity, level[0].available())}}
+}
+
+// allocateProRata gives every order a share proportional to its remaining
+// size, rounded down to the lot size. What rounding leaves over goes to
+// the orders in time priority.
+func allocateProRata(level []*Order, quantity, lotSize float64) []Allocation {
+	total := 0.0
+	for _, order := range level {
+		total += order.available()
+	}
+	if total <= 0 || quantity <= 0 {
+		return nil
+	}
+
+	if lotSize <= 0 {
+		lotSize = dustQuantity
+	}
+
+	shares := make([]float64, len(level))
+	allocated := 0.0
+	for i, order := range level {
+		if quantity >= total {
+			shares[i] = order.available()
+		} else {
+			lots := math.Floor(quantity * order.available() / total / lotSize)
+			shares[i] = min(roundQuantity(lots*lotSize), order.available())
+		}
+		allocated += shares[i]
+	}
+
+	leftover := min(quantity, total) - allocated
+	for i, order := range level {
+		if leftover <= 0 {
+			break
+		}
+		extra := min(leftover, order.available()-shares[i])
+		if extra > 0 {
+			shares[i] += extra
+			leftover -= extra
+		}
+	}
+
+	allocations := make([]Allocation, 0, len(level))
+	for i, order := range level {
+		if shares[i] > dustQuantity {
+			allocations = append(allocations, Allocation{Order: order, Quantity: shares[i]})
+		}
+	}
+	return allocations
+}
+
+// roundQuantity strips float noise below the dust quantity
+func roundQuantity(qty float64) float64 {
+	const scale = 1 / dustQuantity
+	return math.Round(qty*scale) / scale
+}
+
+// allocateTopOrder serves the top order first, up to max, then the rest
+// of the level in time priority, and only then the top order's remainder.
+// Without a top order it is plain FIFO across the level.
+func allocateTopOrder(level []*Order, quantity, max float64) []Allocation {
+	allocations := make([]Allocation, 0, len(level))
+	remaining := quantity
+	give := func(order *Order, qty float64) {
+		if qty = min(qty, remaining); qty > 0 {
+			allocations = append(allocations, Allocation{Order: order, Quantity: qty})
+			remaining -= qty
+		}
+	}
+
+	var top *Order
+	topQty := 0.0
+	if len(level) > 0 && level[0].top {
+		top = level[0]
+		topQty = top.available()
+		if max > 0 {
+			topQty = min(topQty, max)
+		}
+		give(top, topQty)
+	}
+
+	for _, order := range level {
+		if order != top {
+			give(order, order.available())
+		}
+	}
+
+	if top != nil && len(allocations) > 0 {
+		give(top, top.available()-allocations[0].Quantity)
+		// Merge the top order's two shares into one fill
+		if last := allocations[len(allocations)-1]; len(allocations) > 1 && last.Order == top {
+			allocations[0].Quantity += last.Quantity
+			allocations = allocations[:len(allocations)-1]
+		}
+	}
+	return allocations
+}
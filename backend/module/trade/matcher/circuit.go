@@ -0,0 +1,209 @@
+package matcher
+
+import (
+	"math"
+	"time"
+)
+
+// BookPhase is the trading state of a single order book
+type BookPhase string
+
+const (
+	BookPhaseContinuous BookPhase = "CONTINUOUS" // Normal continuous matching
+	BookPhaseAuction    BookPhase = "AUCTION"    // Call auction: orders accumulate, indicative price published
+	BookPhaseHalted     BookPhase = "HALTED"     // Orders accumulate, nothing published until the reopening auction
+)
+
+// BreakerAction is the phase a book enters when its circuit breaker trips
+type BreakerAction string
+
+const (
+	BreakerActionAuction BreakerAction = "AUCTION"
+	BreakerActionHalt    BreakerAction = "HALT"
+)
+
+// CircuitBreakerConfig configures the volatility guard of an order book.
+// A ThresholdPct of 0 disables the breaker.
+type CircuitBreakerConfig struct {
+	ThresholdPct float64       // Max % move allowed within Window
+	Window       time.Duration // Rolling window of reference trades
+	Duration     time.Duration // How long the book stays in auction/halt
+	Action       BreakerAction
+}
+
+// DefaultCircuitBreakerConfig is used for books without their own config
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	ThresholdPct: 10,
+	Window:       5 * time.Minute,
+	Duration:     2 * time.Minute,
+	Action:       BreakerActionAuction,
+}
+
+// pricePoint is a trade price inside the rolling window
+type pricePoint struct {
+	price float64
+	at    time.Time
+}
+
+// CircuitBreaker tracks recent trade prices for a book and decides when a
+// match would move the price too far. Guarded by the owning book's lock.
+type CircuitBreaker struct {
+	config    CircuitBreakerConfig
+	history   []pricePoint
+	phase     BookPhase
+	until     time.Time
+	trips     int
+	lastTrip  time.Time
+	lastPrice float64
+}
+
+// NewCircuitBreaker creates a breaker in the continuous phase
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		config: config,
+		phase:  BookPhaseContinuous,
+	}
+}
+
+// Phase returns the current book phase
+func (cb *CircuitBreaker) Phase() BookPhase {
+	return cb.phase
+}
+
+// Until returns when the current auction or halt ends
+func (cb *CircuitBreaker) Until() time.Time {
+	return cb.until
+}
+
+// Trips returns how many times the breaker has tripped
+func (cb *CircuitBreaker) Trips() int {
+	return cb.trips
+}
+
+// LastPrice returns the last recorded trade price
+func (cb *CircuitBreaker) LastPrice() float64 {
+	return cb.lastPrice
+}
+
+// Breaches reports whether trading at price would move it more than the
+// threshold away from any trade inside the window
+func (cb *CircuitBreaker) Breaches(price float64, now time.Time) bool {
+	if cb.config.ThresholdPct <= 0 || price <= 0 {
+		return false
+	}
+	cb.prune(now)
+
+	limit := cb.config.ThresholdPct / 100
+	for _, point := range cb.history {
+		if math.Abs(price-point.price)/point.price > limit {
+			return true
+		}
+	}
+	return false
+}
+
+// Trip switches the book into the configured auction or halt phase
+func (cb *CircuitBreaker) Trip(now time.Time) {
+	cb.phase = BookPhaseAuction
+	if cb.config.Action == BreakerActionHalt {
+		cb.phase = BookPhaseHalted
+	}
+	cb.until = now.Add(cb.config.Duration)
+	cb.trips++
+	cb.lastTrip = now
+}
+
+// Expired reports whether the auction or halt is over and the book should uncross
+func (cb *CircuitBreaker) Expired(now time.Time) bool {
+	return cb.phase != BookPhaseContinuous && !now.Before(cb.until)
+}
+
+// Reopen returns the book to continuous trading with price as the new reference
+func (cb *CircuitBreaker) Reopen(price float64, now time.Time) {
+	cb.phase = BookPhaseContinuous
+	cb.until = time.Time{}
+	cb.history = cb.history[:0]
+	if price > 0 {
+		cb.Record(price, now)
+	}
+}
+
+// Record adds a trade price to the rolling window
+func (cb *CircuitBreaker) Record(price float64, now time.Time) {
+	cb.history = append(cb.history, pricePoint{price: price, at: now})
+	cb.lastPrice = price
+}
+
+// prune drops trades that have left the window
+func (cb *CircuitBreaker) prune(now time.Time) {
+	cutoff := now.Add(-cb.config.Window)
+	i := 0
+	for i < len(cb.history) && cb.history[i].at.Before(cutoff) {
+		i++
+	}
+	cb.history = cb.history[i:]
+}
+
+// Equilibrium finds the single price that maximises executable volume
+// between buys and sells. Ties go to the smallest imbalance, then to the
+// price closest to reference. Returns 0 volume if the book doesn't cross.
+func Equilibrium(buys, sells []*Order, reference float64) (price, volume float64) {
+	candidates := make(map[float64]bool)
+	for _, o := range buys {
+		if o.Type != "MARKET" {
+			candidates[o.Price] = true
+		}
+	}
+	for _, o := range sells {
+		if o.Type != "MARKET" {
+			candidates[o.Price] = true
+		}
+	}
+	// Market orders only: fall back on the reference price
+	if len(candidates) == 0 && reference > 0 {
+		candidates[reference] = true
+	}
+
+	bestImbalance := math.Inf(1)
+	for p := range candidates {
+		demand, supply := 0.0, 0.0
+		for _, o := range buys {
+			if o.Type == "MARKET" || o.Price >= p {
+				demand += o.Quantity - o.FilledQty
+			}
+		}
+		for _, o := range sells {
+			if o.Type == "MARKET" || o.Price <= p {
+				supply += o.Quantity - o.FilledQty
+			}
+		}
+
+		executable := math.Min(demand, supply)
+		imbalance := math.Abs(demand - supply)
+		if executable <= 0 {
+			continue
+		}
+
+		better := executable > volume ||
+			(executable == volume && imbalance < bestImbalance) ||
+			(executable == volume && imbalance == bestImbalance && closer(p, price, reference))
+		if better {
+			price, volume, bestImbalance = p, executable, imbalance
+		}
+	}
+
+	return price, volume
+}
+
+// closer reports whether a is nearer to reference than b; without a
+// reference the lower price wins so results are deterministic
+func closer(a, b, reference float64) bool {
+	if reference <= 0 {
+		return a < b
+	}
+	da, db := math.Abs(a-reference), math.Abs(b-reference)
+	if da != db {
+		return da < db
+	}
+	return a < b
+}
@@ -0,0 +1,125 @@
+package matcher
+
+import "math/rand"
+
+// maxSkipLevel bounds the skiplist height; 2^16 price levels per side is
+// far beyond any realistic book
+const maxSkipLevel = 16
+
+// levelNode is a skiplist node holding one price level
+type levelNode struct {
+	level *PriceLevel
+	next  []*levelNode
+}
+
+// levelList is a skiplist of price levels ordered best price first,
+// giving O(log n) insert/remove and O(1) access to the best level
+type levelList struct {
+	head   *levelNode
+	height int
+	better func(a, b float64) bool
+	rnd    *rand.Rand
+}
+
+func newLevelList(better func(a, b float64) bool) *levelList {
+	return &levelList{
+		head:   &levelNode{next: make([]*levelNode, maxSkipLevel)},
+		height: 1,
+		better: better,
+		rnd:    rand.New(rand.NewSource(1)),
+	}
+}
+
+// first returns the best price level, or nil if the side is empty
+func (l *levelList) first() *PriceLevel {
+	if node := l.head.next[0]; node != nil {
+		return node.level
+	}
+	return nil
+}
+
+// each calls fn for every level from best to worst until fn returns false
+func (l *levelList) each(fn func(level *PriceLevel) bool) {
+	for node := l.head.next[0]; node != nil; node = node.next[0] {
+		if !fn(node.level) {
+			return
+		}
+	}
+}
+
+// find returns the level at price, or nil
+func (l *levelList) find(price float64) *PriceLevel {
+	node := l.head
+	for i := l.height - 1; i >= 0; i-- {
+		for node.next[i] != nil && l.better(node.next[i].level.Price, price) {
+			node = node.next[i]
+		}
+	}
+	if next := node.next[0]; next != nil && next.level.Price == price {
+		return next.level
+	}
+	return nil
+}
+
+// search fills update with the last node before price on every lane
+func (l *levelList) search(price float64, update []*levelNode) *levelNode {
+	node := l.head
+	for i := l.height - 1; i >= 0; i-- {
+		for node.next[i] != nil && l.better(node.next[i].level.Price, price) {
+			node = node.next[i]
+		}
+		update[i] = node
+	}
+	return node.next[0]
+}
+
+// getOrInsert returns the level at price, creating it if needed
+func (l *levelList) getOrInsert(price float64) *PriceLevel {
+	update := make([]*levelNode, maxSkipLevel)
+	if candidate := l.search(price, update); candidate != nil && candidate.level.Price == price {
+		return candidate.level
+	}
+
+	height := l.randomHeight()
+	if height > l.height {
+		for i := l.height; i < height; i++ {
+			update[i] = l.head
+		}
+		l.height = height
+	}
+
+	node := &levelNode{
+		level: newPriceLevel(price),
+		next:  make([]*levelNode, height),
+	}
+	for i := 0; i < height; i++ {
+		node.next[i] = update[i].next[i]
+		update[i].next[i] = node
+	}
+	return node.level
+}
+
+// remove deletes the level at price
+func (l *levelList) remove(price float64) {
+	update := make([]*levelNode, maxSkipLevel)
+	node := l.search(price, update)
+	if node == nil || node.level.Price != price {
+		return
+	}
+
+	for i := 0; i < len(node.next); i++ {
+		update[i].next[i] = node.next[i]
+	}
+	for l.height > 1 && l.head.next[l.height-1] == nil {
+		l.height--
+	}
+}
+
+// randomHeight picks a node height with p = 1/2 per extra lane
+func (l *levelList) randomHeight() int {
+	height := 1
+	for height < maxSkipLevel && l.rnd.Intn(2) == 0 {
+		height++
+	}
+	return height
+}
@@ -1,9 +1,12 @@
 package matcher
 
 import (
+	"errors"
 	"log"
 	"sync"
 	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
 )
 
 // Match represents a successful order match
@@ -16,199 +19,633 @@ type Match struct {
 	BuyerID     string
 	SellerID    string
 	Timestamp   int64
+	// AggressorSide is the side of the order that took liquidity (BUY or
+	// SELL); empty for auction uncrosses, where neither side initiated
+	AggressorSide string
 }
 
-// MatchHandler is called when orders are matched
+// MatchHandler is called for every match before the book is filled. If
+// it fails the match doesn't happen: a *Rejection cancels the order it
+// names, and any other error pauses the book until its next command.
 type MatchHandler func(match *Match) error
 
-// Engine is the order matching engine
+// CancelReasonRejected marks an order cancelled because the match handler
+// refused to settle it
+const CancelReasonRejected = "REJECTED"
+
+// Rejection is returned by a MatchHandler that refuses a match because
+// one of its orders can't be settled, e.g. its account was frozen
+type Rejection struct {
+	OrderID string
+	Err     error
+}
+
+func (r *Rejection) Error() string {
+	return "order " + r.OrderID + " rejected: " + r.Err.Error()
+}
+
+func (r *Rejection) Unwrap() error {
+	return r.Err
+}
+
+// SessionFunc returns the current session phase for a symbol's exchange
+type SessionFunc func(symbol string) calendar.Phase
+
+// Engine is the order matching engine. Each symbol's book is owned by a
+// single goroutine that applies commands in arrival order, so matching
+// runs synchronously as orders are added and books need no shared locks.
 type Engine struct {
-	orderBooks   map[string]*OrderBook
-	mu           sync.RWMutex
-	matchHandler MatchHandler
-	running      bool
-	stopCh       chan struct{}
+	workers        map[string]*bookWorker
+	mu             sync.RWMutex
+	matchHandler   MatchHandler
+	bookHandler    BookHandler
+	cancelHandler  CancelHandler
+	sessionFunc    SessionFunc
+	defaultBreaker CircuitBreakerConfig
+	breakerConfigs map[string]CircuitBreakerConfig
+	defaultAlgo    AlgorithmConfig
+	algoConfigs    map[string]AlgorithmConfig
+	stopCh         chan struct{}
+	wg             sync.WaitGroup
 }
 
 // NewEngine creates a new matching engine
 func NewEngine(handler MatchHandler) *Engine {
 	return &Engine{
-		orderBooks:   make(map[string]*OrderBook),
-		matchHandler: handler,
-		stopCh:       make(chan struct{}),
+		workers:        make(map[string]*bookWorker),
+		matchHandler:   handler,
+		defaultBreaker: DefaultCircuitBreakerConfig,
+		breakerConfigs: make(map[string]CircuitBreakerConfig),
+		defaultAlgo:    DefaultAlgorithmConfig,
+		algoConfigs:    make(map[string]AlgorithmConfig),
+		stopCh:         make(chan struct{}),
 	}
 }
 
-// Start starts the matching engine
-func (e *Engine) Start() {
-	e.running = true
-	log.Println("[Matcher] Engine started")
+// SetMatchHandler replaces the handler called for every match
+func (e *Engine) SetMatchHandler(handler MatchHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.matchHandler = handler
+}
 
-	go func() {
-		ticker := time.NewTicker(100 * time.Millisecond)
-		defer ticker.Stop()
+// SetBookHandler sets the handler called with every change to a book's
+// aggregated levels. It runs on the book's goroutine and must not block.
+func (e *Engine) SetBookHandler(handler BookHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.bookHandler = handler
+}
 
-		for {
-			select {
-			case <-e.stopCh:
-				return
-			case <-ticker.C:
-				e.matchAllBooks()
-			}
-		}
-	}()
+// SetCancelHandler sets the handler called when the engine cancels order
+// quantity itself, e.g. to prevent a self-trade. It runs on the book's
+// goroutine and must not call back into the engine for the same symbol.
+func (e *Engine) SetCancelHandler(handler CancelHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cancelHandler = handler
 }
 
-// Stop stops the matching engine
-func (e *Engine) Stop() {
-	e.running = false
-	close(e.stopCh)
-	log.Println("[Matcher] Engine stopped")
+// SetSessionFunc sets the session lookup used to decide order eligibility.
+// Without one, every symbol is treated as being in its regular session.
+func (e *Engine) SetSessionFunc(fn SessionFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sessionFunc = fn
 }
 
-// AddOrder adds an order to the appropriate order book
-func (e *Engine) AddOrder(order *Order) {
+// SetDefaultCircuitBreaker sets the breaker config for symbols without their own.
+// Only books created afterwards are affected.
+func (e *Engine) SetDefaultCircuitBreaker(config CircuitBreakerConfig) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	e.defaultBreaker = config
+}
 
-	book, exists := e.orderBooks[order.Symbol]
-	if !exists {
-		book = NewOrderBook(order.Symbol)
-		e.orderBooks[order.Symbol] = book
+// SetCircuitBreaker sets the breaker config for a symbol, resetting its
+// breaker if the book already exists
+func (e *Engine) SetCircuitBreaker(symbol string, config CircuitBreakerConfig) {
+	e.mu.Lock()
+	e.breakerConfigs[symbol] = config
+	w, exists := e.workers[symbol]
+	e.mu.Unlock()
+
+	if exists {
+		e.do(w, func() {
+			w.book.breaker = NewCircuitBreaker(config)
+		})
+	}
+}
+
+// SetDefaultAlgorithm sets the matching algorithm for books created
+// without their own config
+func (e *Engine) SetDefaultAlgorithm(config AlgorithmConfig) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.defaultAlgo = config
+}
+
+// SetAlgorithm sets the matching algorithm for a symbol. It applies to
+// the next match, so it can be switched while the book is live.
+func (e *Engine) SetAlgorithm(symbol string, config AlgorithmConfig) {
+	e.mu.Lock()
+	e.algoConfigs[symbol] = config
+	w, exists := e.workers[symbol]
+	e.mu.Unlock()
+
+	if exists {
+		e.do(w, func() {
+			w.book.algorithm = config
+		})
+	}
+}
+
+// SetReferencePrice seeds a symbol's breaker window, e.g. with the
+// previous close, so the first trade is also checked
+func (e *Engine) SetReferencePrice(symbol string, price float64) {
+	w := e.worker(symbol)
+	e.do(w, func() {
+		w.book.breaker.Record(price, time.Now())
+	})
+}
+
+// Eligible returns nil if the order may match in its symbol's current
+// session, or the reason it may not
+func (e *Engine) Eligible(order *Order) error {
+	return calendar.CheckEligibility(e.sessionPhase(order.Symbol), order.Type, order.ExtendedHours)
+}
+
+// sessionPhase returns the session phase for a symbol
+func (e *Engine) sessionPhase(symbol string) calendar.Phase {
+	e.mu.RLock()
+	fn := e.sessionFunc
+	e.mu.RUnlock()
+
+	if fn == nil {
+		return calendar.PhaseRegular
 	}
+	return fn(symbol)
+}
 
-	book.AddOrder(order)
-	log.Printf("[Matcher] Added %s order for %s: %.2f @ %.2f", order.Side, order.Symbol, order.Quantity, order.Price)
+// Start starts the matching engine. Book goroutines are started lazily
+// as symbols receive their first command.
+func (e *Engine) Start() {
+	log.Println("[Matcher] Engine started")
+}
+
+// Stop stops every book goroutine and waits for them to exit
+func (e *Engine) Stop() {
+	close(e.stopCh)
+	e.wg.Wait()
+	log.Println("[Matcher] Engine stopped")
+}
+
+// AddOrder adds an order to its book and matches it before returning
+func (e *Engine) AddOrder(order *Order) {
+	w := e.worker(order.Symbol)
+	e.do(w, func() {
+		w.book.AddOrder(order)
+		log.Printf("[Matcher] Added %s order for %s: %.2f @ %.2f", order.Side, order.Symbol, order.Quantity, order.Price)
+		e.matchBook(w)
+	})
 }
 
 // CancelOrder removes an order from the book
 func (e *Engine) CancelOrder(symbol, orderID string) bool {
 	e.mu.RLock()
-	book, exists := e.orderBooks[symbol]
+	w, exists := e.workers[symbol]
 	e.mu.RUnlock()
 
 	if !exists {
 		return false
 	}
 
-	return book.RemoveOrder(orderID)
+	removed := false
+	e.do(w, func() {
+		removed = w.book.RemoveOrder(orderID)
+	})
+	return removed
 }
 
-// matchAllBooks runs matching on all order books
-func (e *Engine) matchAllBooks() {
+// Withdraw removes an order from the book and returns a copy of it as it
+// stood, or nil if it was no longer resting (e.g. fully filled)
+func (e *Engine) Withdraw(symbol, orderID string) *Order {
 	e.mu.RLock()
-	symbols := make([]string, 0, len(e.orderBooks))
-	for symbol := range e.orderBooks {
-		symbols = append(symbols, symbol)
-	}
+	w, exists := e.workers[symbol]
 	e.mu.RUnlock()
 
-	for _, symbol := range symbols {
-		e.matchBook(symbol)
+	if !exists {
+		return nil
 	}
+
+	var withdrawn *Order
+	e.do(w, func() {
+		if order, ok := w.book.GetOrder(orderID); ok {
+			copied := *order
+			withdrawn = &copied
+			w.book.RemoveOrder(orderID)
+		}
+	})
+	return withdrawn
 }
 
-// matchBook matches orders in a single order book
-func (e *Engine) matchBook(symbol string) {
+// Rematch re-runs matching on the given symbols, or on every book if none
+// are given. The order book service calls it at every session boundary,
+// since orders that were ineligible may now be able to match.
+func (e *Engine) Rematch(symbols ...string) {
 	e.mu.RLock()
-	book, exists := e.orderBooks[symbol]
+	var workers []*bookWorker
+	if len(symbols) == 0 {
+		for _, w := range e.workers {
+			workers = append(workers, w)
+		}
+	} else {
+		for _, symbol := range symbols {
+			if w, ok := e.workers[symbol]; ok {
+				workers = append(workers, w)
+			}
+		}
+	}
 	e.mu.RUnlock()
 
-	if !exists {
+	for _, w := range workers {
+		w := w
+		e.do(w, func() { e.matchBook(w) })
+	}
+}
+
+// matchBook matches orders in a single order book. Runs on the book's goroutine.
+func (e *Engine) matchBook(w *bookWorker) {
+	book := w.book
+	symbol := book.Symbol
+
+	phase := e.sessionPhase(symbol)
+	if phase == calendar.PhaseClosed {
 		return
 	}
 
-	book.mu.Lock()
-	defer book.mu.Unlock()
+	now := time.Now()
+	breaker := book.breaker
 
-	for len(book.BuyOrders) > 0 && len(book.SellOrders) > 0 {
-		buy := book.BuyOrders[0]
-		sell := book.SellOrders[0]
+	// During an auction or halt orders only accumulate; once it is over
+	// the book uncrosses at a single price and reopens
+	if breaker.Phase() != BookPhaseContinuous {
+		if breaker.Expired(now) {
+			e.uncross(book, phase, now)
+		}
+		return
+	}
 
-		// Check if orders can match
-		// For MARKET orders, always match
-		// For LIMIT orders, buy price must >= sell price
-		canMatch := false
-		matchPrice := 0.0
+	for {
+		// Orders not eligible for the current session keep their place
+		// in the book but are skipped over
+		eligible := func(o *Order) bool {
+			return calendar.CheckEligibility(phase, o.Type, o.ExtendedHours) == nil
+		}
+		buy := book.First("BUY", eligible)
+		sell := book.First("SELL", eligible)
+		if buy == nil || sell == nil {
+			break
+		}
 
-		if buy.Type == "MARKET" || sell.Type == "MARKET" {
-			canMatch = true
-			if sell.Type == "MARKET" {
-				matchPrice = buy.Price
-			} else {
-				matchPrice = sell.Price
-			}
-		} else if buy.Price >= sell.Price {
-			canMatch = true
-			// Use the earlier order's price (price-time priority)
-			if buy.Timestamp < sell.Timestamp {
-				matchPrice = buy.Price
-			} else {
-				matchPrice = sell.Price
+		// Two market orders have no price to trade at before the first
+		// trade, so one of them takes the other side's best limit order
+		// instead of holding up the whole book
+		if buy.Type == "MARKET" && sell.Type == "MARKET" && breaker.LastPrice() <= 0 {
+			buy, sell = limitCounterpart(book, buy, sell, eligible)
+			if buy == nil {
+				break
 			}
 		}
 
+		// The order that rested first sets the price; the other one is
+		// the aggressor that crossed the spread
+		resting, incoming := buy, sell
+		if sell.seq < buy.seq {
+			resting, incoming = sell, buy
+		}
+
+		matchPrice, canMatch := matchPrice(book, buy, sell, resting)
 		if !canMatch {
 			break
 		}
 
-		// Calculate match quantity
-		matchQty := min(buy.Quantity-buy.FilledQty, sell.Quantity-sell.FilledQty)
+		// A user never trades with themselves
+		if selfTrade(buy, sell) {
+			e.preventSelfTrade(book, buy, sell)
+			continue
+		}
 
-		// Create match
-		match := &Match{
-			BuyOrderID:  buy.ID,
-			SellOrderID: sell.ID,
-			Symbol:      symbol,
-			Price:       matchPrice,
-			Quantity:    matchQty,
-			BuyerID:     buy.UserID,
-			SellerID:    sell.UserID,
-			Timestamp:   time.Now().UnixMilli(),
+		if breaker.Breaches(matchPrice, now) {
+			breaker.Trip(now)
+			e.after(w, breaker.Until().Sub(now), func() { e.matchBook(w) })
+			log.Printf("[Matcher] Circuit breaker tripped on %s at %.2f, %s until %s",
+				symbol, matchPrice, breaker.Phase(), breaker.Until().Format(time.RFC3339))
+			break
 		}
 
-		// Update filled quantities
-		buy.FilledQty += matchQty
-		sell.FilledQty += matchQty
+		// Share the incoming quantity across the resting level. Orders of
+		// the incoming order's own user are left out; if they are reached
+		// first, self-trade prevention handles them on the next pass.
+		level := []*Order{resting}
+		if resting.Type != "MARKET" {
+			level = level[:0]
+			for _, order := range book.LevelOrders(resting.Side, resting.Price) {
+				if eligible(order) && !selfTrade(order, incoming) {
+					level = append(level, order)
+				}
+			}
+		}
 
-		// Remove fully filled orders
-		if buy.FilledQty >= buy.Quantity {
-			book.BuyOrders = book.BuyOrders[1:]
+		allocations := book.algorithm.Allocate(level, incoming.Remaining())
+		if len(allocations) == 0 {
+			break
 		}
-		if sell.FilledQty >= sell.Quantity {
-			book.SellOrders = book.SellOrders[1:]
+		for _, allocation := range allocations {
+			buyOrder, sellOrder := incoming, allocation.Order
+			if incoming.Side == "SELL" {
+				buyOrder, sellOrder = allocation.Order, incoming
+			}
+
+			match := &Match{
+				BuyOrderID:    buyOrder.ID,
+				SellOrderID:   sellOrder.ID,
+				Symbol:        symbol,
+				Price:         matchPrice,
+				Quantity:      allocation.Quantity,
+				BuyerID:       buyOrder.UserID,
+				SellerID:      sellOrder.UserID,
+				Timestamp:     time.Now().UnixMilli(),
+				AggressorSide: incoming.Side,
+			}
+
+			// Settle first; a match that can't be settled leaves the book
+			// as it was, less any order the handler rejected
+			if err := e.handleMatch(match); err != nil {
+				if !e.reject(book, err, buyOrder, sellOrder) {
+					return
+				}
+				break
+			}
+
+			// Update filled quantities; fully filled orders leave the book
+			book.Fill(buyOrder, allocation.Quantity)
+			book.Fill(sellOrder, allocation.Quantity)
+
+			breaker.Record(matchPrice, now)
 		}
+	}
+}
 
-		// Call match handler
-		if e.matchHandler != nil {
-			if err := e.matchHandler(match); err != nil {
-				log.Printf("[Matcher] Error handling match: %v", err)
+// matchPrice returns the price buy and sell trade at, and whether they
+// cross at all. The resting order's price always wins; a resting MARKET
+// order takes the incoming limit price, and two MARKET orders trade at
+// the last price if there is one.
+func matchPrice(book *OrderBook, buy, sell, resting *Order) (float64, bool) {
+	switch {
+	case buy.Type == "MARKET" && sell.Type == "MARKET":
+		last := book.breaker.LastPrice()
+		return last, last > 0
+	case buy.Type == "MARKET":
+		return sell.Price, true
+	case sell.Type == "MARKET":
+		return buy.Price, true
+	case buy.Price >= sell.Price:
+		return resting.Price, true
+	}
+	return 0, false
+}
+
+// limitCounterpart pairs one of two MARKET orders with the best eligible
+// LIMIT order on the opposite side, the older market order first. Returns
+// nil orders if neither side has a limit order to trade against.
+func limitCounterpart(book *OrderBook, buy, sell *Order, eligible func(*Order) bool) (*Order, *Order) {
+	limit := func(o *Order) bool {
+		return o.Type != "MARKET" && eligible(o)
+	}
+	limitBuy := book.First("BUY", limit)
+	limitSell := book.First("SELL", limit)
+
+	if limitSell != nil && (buy.seq < sell.seq || limitBuy == nil) {
+		return buy, limitSell
+	}
+	if limitBuy != nil {
+		return limitBuy, sell
+	}
+	return nil, nil
+}
+
+// uncross executes every crossing order at the auction's equilibrium
+// price, then returns the book to continuous trading. Runs on the book's
+// goroutine.
+func (e *Engine) uncross(book *OrderBook, phase calendar.Phase, now time.Time) {
+	buys := eligibleOrders(book.Orders("BUY"), phase)
+	sells := eligibleOrders(book.Orders("SELL"), phase)
+
+	price, volume := Equilibrium(buys, sells, book.breaker.LastPrice())
+	if volume > 0 {
+		buys = crossingOrders(buys, func(o *Order) bool { return o.Price >= price })
+		sells = crossingOrders(sells, func(o *Order) bool { return o.Price <= price })
+
+		i, j := 0, 0
+		for i < len(buys) && j < len(sells) {
+			buy, sell := buys[i], sells[j]
+			if selfTrade(buy, sell) {
+				e.preventSelfTrade(book, buy, sell)
+				if buy.Remaining() <= 0 {
+					i++
+				}
+				if sell.Remaining() <= 0 {
+					j++
+				}
+				continue
+			}
+
+			matchQty := min(buy.Quantity-buy.FilledQty, sell.Quantity-sell.FilledQty)
+
+			err := e.handleMatch(&Match{
+				BuyOrderID:  buy.ID,
+				SellOrderID: sell.ID,
+				Symbol:      book.Symbol,
+				Price:       price,
+				Quantity:    matchQty,
+				BuyerID:     buy.UserID,
+				SellerID:    sell.UserID,
+				Timestamp:   now.UnixMilli(),
+			})
+			if err != nil {
+				if !e.reject(book, err, buy, sell) {
+					break
+				}
+				if buy.Remaining() <= 0 {
+					i++
+				}
+				if sell.Remaining() <= 0 {
+					j++
+				}
+				continue
+			}
+
+			book.Fill(buy, matchQty)
+			book.Fill(sell, matchQty)
+
+			if buy.FilledQty >= buy.Quantity {
+				i++
+			}
+			if sell.FilledQty >= sell.Quantity {
+				j++
 			}
 		}
+	}
+
+	log.Printf("[Matcher] %s uncrossed %.4f @ %.2f, back to continuous trading", book.Symbol, volume, price)
+	book.breaker.Reopen(price, now)
+}
+
+// handleMatch passes a match to the match handler and returns its error
+func (e *Engine) handleMatch(match *Match) error {
+	e.mu.RLock()
+	handler := e.matchHandler
+	e.mu.RUnlock()
 
-		log.Printf("[Matcher] Matched: %s %.4f @ %.2f", symbol, matchQty, matchPrice)
+	if handler != nil {
+		if err := handler(match); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[Matcher] Matched: %s %.4f @ %.2f", match.Symbol, match.Quantity, match.Price)
+	return nil
+}
+
+// reject deals with a match the handler failed to settle. An order it
+// rejected is cancelled out of the book and true is returned so matching
+// carries on; any other failure returns false to leave the book as is.
+// Runs on the book's goroutine.
+func (e *Engine) reject(book *OrderBook, err error, orders ...*Order) bool {
+	var rejection *Rejection
+	if errors.As(err, &rejection) {
+		for _, order := range orders {
+			if order.ID == rejection.OrderID {
+				log.Printf("[Matcher] Cancelled %s on %s: %v", order.ID, book.Symbol, rejection.Err)
+				e.reduce(book, order, order.Remaining(), CancelReasonRejected)
+				return true
+			}
+		}
+	}
+
+	log.Printf("[Matcher] Error handling match on %s, matching paused: %v", book.Symbol, err)
+	return false
+}
+
+// Snapshot returns up to depth aggregated price levels per side for a
+// symbol. Symbols without a book return an empty snapshot.
+func (e *Engine) Snapshot(symbol string, depth int) *BookSnapshot {
+	e.mu.RLock()
+	w, exists := e.workers[symbol]
+	e.mu.RUnlock()
+
+	snapshot := &BookSnapshot{
+		Symbol: symbol,
+		Phase:  BookPhaseContinuous,
+		Bids:   []LevelDepth{},
+		Asks:   []LevelDepth{},
+	}
+	if exists {
+		e.do(w, func() {
+			snapshot = w.book.Snapshot(depth)
+		})
+	}
+	return snapshot
+}
+
+// publishChanges sends the book's pending level changes to the book
+// handler. Runs on the book's goroutine after every command.
+func (e *Engine) publishChanges(book *OrderBook) {
+	update := book.Changes()
+	if update == nil {
+		return
+	}
+
+	e.mu.RLock()
+	handler := e.bookHandler
+	e.mu.RUnlock()
+
+	if handler != nil {
+		handler(update)
 	}
 }
 
 // GetOrderBookStats returns stats for an order book
 func (e *Engine) GetOrderBookStats(symbol string) map[string]interface{} {
 	e.mu.RLock()
-	book, exists := e.orderBooks[symbol]
+	w, exists := e.workers[symbol]
 	e.mu.RUnlock()
 
 	if !exists {
 		return nil
 	}
 
+	var stats map[string]interface{}
+	e.do(w, func() {
+		stats = bookStats(w.book)
+	})
+	return stats
+}
+
+// bookStats builds the stats map for a book. Runs on the book's goroutine.
+func bookStats(book *OrderBook) map[string]interface{} {
 	bids, asks := book.GetDepth()
-	return map[string]interface{}{
-		"symbol":   symbol,
+	stats := map[string]interface{}{
+		"symbol":   book.Symbol,
 		"bestBid":  book.GetBestBid(),
 		"bestAsk":  book.GetBestAsk(),
 		"spread":   book.GetSpread(),
 		"bidDepth": bids,
 		"askDepth": asks,
 	}
+
+	breaker := book.breaker
+	stats["phase"] = string(breaker.Phase())
+	stats["lastPrice"] = breaker.LastPrice()
+	stats["circuitBreakerTrips"] = breaker.Trips()
+	if breaker.Phase() != BookPhaseContinuous {
+		stats["phaseEndsAt"] = breaker.Until().UnixMilli()
+	}
+	// Only auctions publish the indicative uncross; halts stay opaque
+	if breaker.Phase() == BookPhaseAuction {
+		price, volume := Equilibrium(book.Orders("BUY"), book.Orders("SELL"), breaker.LastPrice())
+		stats["indicativePrice"] = price
+		stats["indicativeVolume"] = volume
+	}
+
+	return stats
+}
+
+// eligibleOrders returns the orders that may match during phase
+func eligibleOrders(orders []*Order, phase calendar.Phase) []*Order {
+	var result []*Order
+	for _, order := range orders {
+		if calendar.CheckEligibility(phase, order.Type, order.ExtendedHours) == nil {
+			result = append(result, order)
+		}
+	}
+	return result
+}
+
+// crossingOrders returns the MARKET orders followed by the limit orders
+// that accept the auction price, keeping book priority within each group
+func crossingOrders(orders []*Order, accepts func(*Order) bool) []*Order {
+	var market, limit []*Order
+	for _, order := range orders {
+		switch {
+		case order.Type == "MARKET":
+			market = append(market, order)
+		case accepts(order):
+			limit = append(limit, order)
+		}
+	}
+	return append(market, limit...)
 }
 
 func min(a, b float64) float64 {
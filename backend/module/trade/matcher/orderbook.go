@@ -1,10 +1,15 @@
 package matcher
 
 import (
+	"container/list"
 	"sort"
-	"sync"
+	"time"
 )
 
+// dustQuantity is the smallest quantity the engine trades. Anything less
+// left on an order is float rounding, and the order counts as filled.
+const dustQuantity = 1e-9
+
 // Order represents an order in the order book
 type Order struct {
 	ID          string
@@ -18,83 +23,395 @@ type Order struct {
 	Timestamp   int64
 	PortfolioID string
 	AccountID   string
+	// ExtendedHours allows a LIMIT order to match pre-market and after-hours
+	ExtendedHours bool
+	// SelfTradeMode applies when this order is the newer side of a self-match
+	SelfTradeMode SelfTradeMode
+	// DisplayQuantity makes a LIMIT order an iceberg: only a slice this
+	// size rests visibly, refilled from the reserve as it trades. 0 shows
+	// the whole order.
+	DisplayQuantity float64
+	// Hidden keeps a LIMIT order out of depth entirely
+	Hidden bool
+
+	seq   uint64  // Arrival order in the book; lower rested first
+	top   bool    // Opened a new best price level on its side
+	slice float64 // Iceberg: what is left of the displayed slice
+}
+
+// Remaining returns the unfilled quantity
+func (o *Order) Remaining() float64 {
+	return o.Quantity - o.FilledQty
+}
+
+// iceberg reports whether only a slice of the order is displayed
+func (o *Order) iceberg() bool {
+	return o.DisplayQuantity > 0 && !o.Hidden
+}
+
+// available returns what the order can trade while resting: the current
+// slice for an iceberg, everything else otherwise
+func (o *Order) available() float64 {
+	if o.iceberg() {
+		return min(max(o.slice, 0), o.Remaining())
+	}
+	return o.Remaining()
+}
+
+// shown returns the quantity the order contributes to depth
+func (o *Order) shown() float64 {
+	if o.Hidden {
+		return 0
+	}
+	return o.available()
+}
+
+// PriceLevel is the FIFO queue of orders resting at one price
+type PriceLevel struct {
+	Price    float64
+	orders   *list.List // *Order, oldest first
+	quantity float64    // Sum of displayed quantity
+	visible  int        // Orders that are not hidden
 }
 
-// OrderBook manages buy and sell orders for a symbol
+func newPriceLevel(price float64) *PriceLevel {
+	return &PriceLevel{
+		Price:  price,
+		orders: list.New(),
+	}
+}
+
+// Quantity returns the displayed quantity at the level
+func (pl *PriceLevel) Quantity() float64 {
+	return pl.quantity
+}
+
+// Len returns the number of orders at the level
+func (pl *PriceLevel) Len() int {
+	return pl.orders.Len()
+}
+
+// LevelDepth is an aggregated price level
+type LevelDepth struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+	Orders   int     `json:"orders"`
+}
+
+// BookUpdate is an incremental change to a book's aggregated levels.
+// Each level carries the new total at its price; quantity 0 removes it.
+type BookUpdate struct {
+	Symbol   string
+	Sequence uint64
+	Bids     []LevelDepth
+	Asks     []LevelDepth
+}
+
+// BookSnapshot is the aggregated state of a book at a sequence number.
+// Updates with a sequence at or below it are already included.
+type BookSnapshot struct {
+	Symbol   string
+	Sequence uint64
+	Phase    BookPhase
+	Bids     []LevelDepth
+	Asks     []LevelDepth
+}
+
+// BookHandler is called with every change to a book
+type BookHandler func(update *BookUpdate)
+
+// bookSide holds one side of the book: MARKET orders ahead of every
+// limit price, then price levels from best to worst
+type bookSide struct {
+	market *list.List // *Order, oldest first
+	levels *levelList
+	count  int
+	dirty  map[float64]struct{} // Prices changed since the last update
+}
+
+func newBookSide(better func(a, b float64) bool) *bookSide {
+	return &bookSide{
+		market: list.New(),
+		levels: newLevelList(better),
+		dirty:  make(map[float64]struct{}),
+	}
+}
+
+// drain returns the current state of every dirty price, best first.
+// Removed levels are reported with zero quantity.
+func (s *bookSide) drain() []LevelDepth {
+	changes := make([]LevelDepth, 0, len(s.dirty))
+	for price := range s.dirty {
+		change := LevelDepth{Price: price}
+		if level := s.levels.find(price); level != nil {
+			change.Quantity = level.quantity
+			change.Orders = level.visible
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return s.levels.better(changes[i].Price, changes[j].Price)
+	})
+
+	s.dirty = make(map[float64]struct{})
+	return changes
+}
+
+// orderRef locates an order for O(1) removal
+type orderRef struct {
+	side  *bookSide
+	level *PriceLevel // nil for MARKET orders
+	elem  *list.Element
+}
+
+// OrderBook manages buy and sell orders for a symbol. It is not safe for
+// concurrent use; inside the engine it is owned by the symbol's goroutine.
 type OrderBook struct {
-	Symbol     string
-	BuyOrders  []*Order // Sorted by price DESC, then time ASC
-	SellOrders []*Order // Sorted by price ASC, then time ASC
-	mu         sync.RWMutex
+	Symbol    string
+	bids      *bookSide // Highest price first
+	asks      *bookSide // Lowest price first
+	index     map[string]*orderRef
+	breaker   *CircuitBreaker
+	algorithm AlgorithmConfig
+	sequence  uint64 // Incremented for every published change
+	arrivals  uint64 // Incremented for every order added
 }
 
 // NewOrderBook creates a new order book for a symbol
 func NewOrderBook(symbol string) *OrderBook {
 	return &OrderBook{
-		Symbol:     symbol,
-		BuyOrders:  make([]*Order, 0),
-		SellOrders: make([]*Order, 0),
+		Symbol:    symbol,
+		bids:      newBookSide(func(a, b float64) bool { return a > b }),
+		asks:      newBookSide(func(a, b float64) bool { return a < b }),
+		index:     make(map[string]*orderRef),
+		breaker:   NewCircuitBreaker(DefaultCircuitBreakerConfig),
+		algorithm: DefaultAlgorithmConfig,
+	}
+}
+
+// Phase returns the book's circuit breaker phase
+func (ob *OrderBook) Phase() BookPhase {
+	return ob.breaker.Phase()
+}
+
+// side returns the book side for BUY or SELL
+func (ob *OrderBook) side(side string) *bookSide {
+	if side == "BUY" {
+		return ob.bids
 	}
+	return ob.asks
 }
 
-// AddOrder adds an order to the book
+// AddOrder adds an order to the book. Orders queue by timestamp within
+// their price level, so late-arriving older orders keep time priority.
 func (ob *OrderBook) AddOrder(order *Order) {
-	ob.mu.Lock()
-	defer ob.mu.Unlock()
+	side := ob.side(order.Side)
+	ref := &orderRef{side: side}
 
-	if order.Side == "BUY" {
-		ob.BuyOrders = append(ob.BuyOrders, order)
-		ob.sortBuyOrders()
+	ob.arrivals++
+	order.seq = ob.arrivals
+
+	if order.Type == "MARKET" {
+		ref.elem = insertByTime(side.market, order)
 	} else {
-		ob.SellOrders = append(ob.SellOrders, order)
-		ob.sortSellOrders()
+		best := side.levels.first()
+		order.top = !order.Hidden && (best == nil || side.levels.better(order.Price, best.Price))
+		order.slice = min(order.DisplayQuantity, order.Remaining())
+		ref.level = side.levels.getOrInsert(order.Price)
+		ref.elem = insertByTime(ref.level.orders, order)
+		if !order.Hidden {
+			ref.level.quantity += order.shown()
+			ref.level.visible++
+			side.dirty[order.Price] = struct{}{}
+		}
 	}
+
+	side.count++
+	ob.index[order.ID] = ref
 }
 
 // RemoveOrder removes an order from the book
 func (ob *OrderBook) RemoveOrder(orderID string) bool {
-	ob.mu.Lock()
-	defer ob.mu.Unlock()
+	ref, exists := ob.index[orderID]
+	if !exists {
+		return false
+	}
 
-	// Check buy orders
-	for i, o := range ob.BuyOrders {
-		if o.ID == orderID {
-			ob.BuyOrders = append(ob.BuyOrders[:i], ob.BuyOrders[i+1:]...)
-			return true
+	order := ref.elem.Value.(*Order)
+	if ref.level == nil {
+		ref.side.market.Remove(ref.elem)
+	} else {
+		ref.level.orders.Remove(ref.elem)
+		if ref.level.orders.Len() == 0 {
+			ref.side.levels.remove(ref.level.Price)
+		}
+		if !order.Hidden {
+			ref.level.quantity -= order.shown()
+			ref.level.visible--
+			ref.side.dirty[order.Price] = struct{}{}
 		}
 	}
 
-	// Check sell orders
-	for i, o := range ob.SellOrders {
-		if o.ID == orderID {
-			ob.SellOrders = append(ob.SellOrders[:i], ob.SellOrders[i+1:]...)
-			return true
+	ref.side.count--
+	delete(ob.index, orderID)
+	return true
+}
+
+// Fill records qty executed against a resting order and removes it once
+// fully filled. An iceberg whose slice runs out is refilled from its
+// reserve and goes to the back of its level.
+func (ob *OrderBook) Fill(order *Order, qty float64) {
+	ob.change(order, func() {
+		order.FilledQty += qty
+		order.slice -= qty
+	})
+
+	if order.Remaining() <= dustQuantity {
+		ob.RemoveOrder(order.ID)
+		return
+	}
+	if order.iceberg() && order.slice <= dustQuantity {
+		ob.refill(order)
+	}
+}
+
+// Reduce cancels qty of an order's working quantity without trading it,
+// removing the order once nothing is left
+func (ob *OrderBook) Reduce(order *Order, qty float64) {
+	ob.change(order, func() {
+		order.Quantity -= qty
+	})
+
+	if order.Remaining() <= 0 {
+		ob.RemoveOrder(order.ID)
+	}
+}
+
+// refill shows the next slice of an iceberg. The new slice is timestamped
+// like a new arrival and queues behind everything already at the price,
+// so it loses time priority and orders that arrive later still queue
+// behind it. Its sequence is kept: it still rested before any order it is
+// trading against.
+func (ob *OrderBook) refill(order *Order) {
+	ob.change(order, func() {
+		order.slice = min(order.DisplayQuantity, order.Remaining())
+		order.top = false
+	})
+
+	ref, exists := ob.index[order.ID]
+	if !exists || ref.level == nil {
+		return
+	}
+	last := ref.level.orders.Back().Value.(*Order)
+	order.Timestamp = max(order.Timestamp, last.Timestamp, time.Now().UnixMilli())
+	ref.level.orders.MoveToBack(ref.elem)
+}
+
+// change applies fn to an order and moves its level's displayed quantity
+// by however much the order's displayed quantity changed
+func (ob *OrderBook) change(order *Order, fn func()) {
+	ref, exists := ob.index[order.ID]
+	if !exists || ref.level == nil || order.Hidden {
+		fn()
+		return
+	}
+
+	before := order.shown()
+	fn()
+	if after := order.shown(); after != before {
+		ref.level.quantity += after - before
+		ref.side.dirty[order.Price] = struct{}{}
+	}
+}
+
+// GetOrder returns a resting order by ID
+func (ob *OrderBook) GetOrder(orderID string) (*Order, bool) {
+	ref, exists := ob.index[orderID]
+	if !exists {
+		return nil, false
+	}
+	return ref.elem.Value.(*Order), true
+}
+
+// Each calls fn for every order on a side in priority order until fn
+// returns false
+func (ob *OrderBook) Each(side string, fn func(order *Order) bool) {
+	s := ob.side(side)
+	for e := s.market.Front(); e != nil; e = e.Next() {
+		if !fn(e.Value.(*Order)) {
+			return
 		}
 	}
 
-	return false
+	s.levels.each(func(level *PriceLevel) bool {
+		for e := level.orders.Front(); e != nil; e = e.Next() {
+			if !fn(e.Value.(*Order)) {
+				return false
+			}
+		}
+		return true
+	})
 }
 
-// GetBestBid returns the highest buy price
-func (ob *OrderBook) GetBestBid() float64 {
-	ob.mu.RLock()
-	defer ob.mu.RUnlock()
+// First returns the highest-priority order on a side accepted by match,
+// or nil
+func (ob *OrderBook) First(side string, match func(order *Order) bool) *Order {
+	var found *Order
+	ob.Each(side, func(order *Order) bool {
+		if match(order) {
+			found = order
+			return false
+		}
+		return true
+	})
+	return found
+}
 
-	if len(ob.BuyOrders) == 0 {
-		return 0
+// LevelOrders returns the orders resting at price on a side, oldest first
+func (ob *OrderBook) LevelOrders(side string, price float64) []*Order {
+	level := ob.side(side).levels.find(price)
+	if level == nil {
+		return nil
+	}
+	orders := make([]*Order, 0, level.orders.Len())
+	for e := level.orders.Front(); e != nil; e = e.Next() {
+		orders = append(orders, e.Value.(*Order))
 	}
-	return ob.BuyOrders[0].Price
+	return orders
+}
+
+// Orders returns every order on a side in priority order
+func (ob *OrderBook) Orders(side string) []*Order {
+	orders := make([]*Order, 0, ob.side(side).count)
+	ob.Each(side, func(order *Order) bool {
+		orders = append(orders, order)
+		return true
+	})
+	return orders
 }
 
-// GetBestAsk returns the lowest sell price
+// GetBestBid returns the highest displayed buy price
+func (ob *OrderBook) GetBestBid() float64 {
+	return bestShown(ob.bids)
+}
+
+// GetBestAsk returns the lowest displayed sell price
 func (ob *OrderBook) GetBestAsk() float64 {
-	ob.mu.RLock()
-	defer ob.mu.RUnlock()
+	return bestShown(ob.asks)
+}
 
-	if len(ob.SellOrders) == 0 {
-		return 0
-	}
-	return ob.SellOrders[0].Price
+// bestShown returns the best price with a displayed order, skipping
+// levels made up only of hidden orders
+func bestShown(side *bookSide) float64 {
+	price := 0.0
+	side.levels.each(func(level *PriceLevel) bool {
+		if level.visible > 0 {
+			price = level.Price
+			return false
+		}
+		return true
+	})
+	return price
 }
 
 // GetSpread returns the bid-ask spread
@@ -102,29 +419,69 @@ func (ob *OrderBook) GetSpread() float64 {
 	return ob.GetBestAsk() - ob.GetBestBid()
 }
 
-// GetDepth returns the order book depth
+// GetDepth returns the number of resting buy and sell orders
 func (ob *OrderBook) GetDepth() (bids, asks int) {
-	ob.mu.RLock()
-	defer ob.mu.RUnlock()
-	return len(ob.BuyOrders), len(ob.SellOrders)
+	return ob.bids.count, ob.asks.count
+}
+
+// GetLevels returns up to n aggregated price levels per side, best first.
+// n <= 0 returns every level. MARKET orders have no price and are excluded.
+func (ob *OrderBook) GetLevels(n int) (bids, asks []LevelDepth) {
+	return aggregate(ob.bids, n), aggregate(ob.asks, n)
 }
 
-// Sort buy orders: highest price first, then oldest first
-func (ob *OrderBook) sortBuyOrders() {
-	sort.Slice(ob.BuyOrders, func(i, j int) bool {
-		if ob.BuyOrders[i].Price != ob.BuyOrders[j].Price {
-			return ob.BuyOrders[i].Price > ob.BuyOrders[j].Price
+func aggregate(side *bookSide, n int) []LevelDepth {
+	levels := make([]LevelDepth, 0)
+	side.levels.each(func(level *PriceLevel) bool {
+		if level.visible == 0 {
+			return true
 		}
-		return ob.BuyOrders[i].Timestamp < ob.BuyOrders[j].Timestamp
+		levels = append(levels, LevelDepth{
+			Price:    level.Price,
+			Quantity: level.quantity,
+			Orders:   level.visible,
+		})
+		return n <= 0 || len(levels) < n
 	})
+	return levels
+}
+
+// Changes returns the levels touched since the last call as a sequenced
+// update, or nil if nothing changed
+func (ob *OrderBook) Changes() *BookUpdate {
+	if len(ob.bids.dirty) == 0 && len(ob.asks.dirty) == 0 {
+		return nil
+	}
+
+	ob.sequence++
+	return &BookUpdate{
+		Symbol:   ob.Symbol,
+		Sequence: ob.sequence,
+		Bids:     ob.bids.drain(),
+		Asks:     ob.asks.drain(),
+	}
 }
 
-// Sort sell orders: lowest price first, then oldest first
-func (ob *OrderBook) sortSellOrders() {
-	sort.Slice(ob.SellOrders, func(i, j int) bool {
-		if ob.SellOrders[i].Price != ob.SellOrders[j].Price {
-			return ob.SellOrders[i].Price < ob.SellOrders[j].Price
+// Snapshot returns up to depth aggregated levels per side, stamped with
+// the sequence of the last update
+func (ob *OrderBook) Snapshot(depth int) *BookSnapshot {
+	bids, asks := ob.GetLevels(depth)
+	return &BookSnapshot{
+		Symbol:   ob.Symbol,
+		Sequence: ob.sequence,
+		Phase:    ob.breaker.Phase(),
+		Bids:     bids,
+		Asks:     asks,
+	}
+}
+
+// insertByTime inserts order into queue keeping timestamps ascending.
+// Orders normally arrive in time order, so this is O(1) in practice.
+func insertByTime(queue *list.List, order *Order) *list.Element {
+	for e := queue.Back(); e != nil; e = e.Prev() {
+		if e.Value.(*Order).Timestamp <= order.Timestamp {
+			return queue.InsertAfter(order, e)
 		}
-		return ob.SellOrders[i].Timestamp < ob.SellOrders[j].Timestamp
-	})
+	}
+	return queue.PushFront(order)
 }
@@ -0,0 +1,89 @@
+package matcher
+
+import "log"
+
+// SelfTradeMode decides what happens when a user's buy would match their
+// own sell. The newest order's mode applies.
+type SelfTradeMode string
+
+const (
+	SelfTradeCancelNewest       SelfTradeMode = "CANCEL_NEWEST"        // Cancel the incoming order
+	SelfTradeCancelOldest       SelfTradeMode = "CANCEL_OLDEST"        // Cancel the resting order
+	SelfTradeCancelBoth         SelfTradeMode = "CANCEL_BOTH"          // Cancel both orders
+	SelfTradeDecrementAndCancel SelfTradeMode = "DECREMENT_AND_CANCEL" // Reduce both by the smaller size, cancelling whichever runs out
+)
+
+// DefaultSelfTradeMode applies to orders without a mode
+const DefaultSelfTradeMode = SelfTradeCancelNewest
+
+// CancelReasonSelfTrade marks quantity removed by self-trade prevention
+const CancelReasonSelfTrade = "SELF_TRADE"
+
+// Cancellation reports quantity the engine removed from an order without
+// trading it
+type Cancellation struct {
+	OrderID   string
+	UserID    string
+	Symbol    string
+	Quantity  float64 // Quantity cancelled
+	Remaining float64 // Quantity still working; 0 once the order left the book
+	Reason    string
+}
+
+// CancelHandler is called when the engine cancels order quantity
+type CancelHandler func(cancel *Cancellation)
+
+// selfTrade reports whether buy and sell belong to the same user
+func selfTrade(buy, sell *Order) bool {
+	return buy.UserID != "" && buy.UserID == sell.UserID
+}
+
+// preventSelfTrade applies the newest order's mode to a self-matching
+// pair. Runs on the book's goroutine; at least one order leaves the book.
+func (e *Engine) preventSelfTrade(book *OrderBook, buy, sell *Order) {
+	newest, oldest := sell, buy
+	if buy.seq > sell.seq {
+		newest, oldest = buy, sell
+	}
+
+	mode := newest.SelfTradeMode
+	if mode == "" {
+		mode = DefaultSelfTradeMode
+	}
+
+	switch mode {
+	case SelfTradeCancelOldest:
+		e.reduce(book, oldest, oldest.Remaining(), CancelReasonSelfTrade)
+	case SelfTradeCancelBoth:
+		e.reduce(book, newest, newest.Remaining(), CancelReasonSelfTrade)
+		e.reduce(book, oldest, oldest.Remaining(), CancelReasonSelfTrade)
+	case SelfTradeDecrementAndCancel:
+		qty := min(buy.Remaining(), sell.Remaining())
+		e.reduce(book, newest, qty, CancelReasonSelfTrade)
+		e.reduce(book, oldest, qty, CancelReasonSelfTrade)
+	default:
+		e.reduce(book, newest, newest.Remaining(), CancelReasonSelfTrade)
+	}
+
+	log.Printf("[Matcher] Prevented self-trade on %s for user %s (%s)", book.Symbol, buy.UserID, mode)
+}
+
+// reduce cancels qty of an order and reports it to the cancel handler
+func (e *Engine) reduce(book *OrderBook, order *Order, qty float64, reason string) {
+	book.Reduce(order, qty)
+
+	e.mu.RLock()
+	handler := e.cancelHandler
+	e.mu.RUnlock()
+
+	if handler != nil {
+		handler(&Cancellation{
+			OrderID:   order.ID,
+			UserID:    order.UserID,
+			Symbol:    order.Symbol,
+			Quantity:  qty,
+			Remaining: order.Remaining(),
+			Reason:    reason,
+		})
+	}
+}
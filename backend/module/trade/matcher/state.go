@@ -0,0 +1,91 @@
+package matcher
+
+import "time"
+
+// BookState is a book's resting orders plus the engine state the order
+// store doesn't hold. It is captured for snapshots and used to rebuild
+// books after a restart.
+type BookState struct {
+	Symbol    string
+	Sequence  uint64  // Last published book update
+	LastPrice float64 // Circuit breaker reference
+	Orders    []*Order
+}
+
+// State returns a copy of a book's state, or nil if the book doesn't exist
+func (e *Engine) State(symbol string) *BookState {
+	e.mu.RLock()
+	w, exists := e.workers[symbol]
+	e.mu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+	return e.captureState(w)
+}
+
+// States returns a copy of every book's state
+func (e *Engine) States() []*BookState {
+	e.mu.RLock()
+	workers := make([]*bookWorker, 0, len(e.workers))
+	for _, w := range e.workers {
+		workers = append(workers, w)
+	}
+	e.mu.RUnlock()
+
+	states := make([]*BookState, 0, len(workers))
+	for _, w := range workers {
+		if state := e.captureState(w); state != nil {
+			states = append(states, state)
+		}
+	}
+	return states
+}
+
+// captureState copies the book on its goroutine so the result can be
+// read freely. Returns nil if the engine stopped.
+func (e *Engine) captureState(w *bookWorker) *BookState {
+	var state *BookState
+	ok := e.do(w, func() {
+		book := w.book
+		state = &BookState{
+			Symbol:    book.Symbol,
+			Sequence:  book.sequence,
+			LastPrice: book.breaker.LastPrice(),
+		}
+		for _, side := range []string{"BUY", "SELL"} {
+			book.Each(side, func(order *Order) bool {
+				copied := *order
+				state.Orders = append(state.Orders, &copied)
+				return true
+			})
+		}
+	})
+	if !ok {
+		return nil
+	}
+	return state
+}
+
+// Restore loads a book's orders without matching them, and resumes its
+// update sequence and breaker reference so subscribers never see the
+// sequence go backwards. Orders already in the book are skipped. Call
+// Rematch afterwards to execute anything that crosses.
+func (e *Engine) Restore(state *BookState) {
+	w := e.worker(state.Symbol)
+	e.do(w, func() {
+		book := w.book
+		if state.Sequence > book.sequence {
+			book.sequence = state.Sequence
+		}
+		if state.LastPrice > 0 {
+			book.breaker.Record(state.LastPrice, time.Now())
+		}
+		for _, order := range state.Orders {
+			if _, exists := book.index[order.ID]; exists || order.Remaining() <= 0 {
+				continue
+			}
+			book.AddOrder(order)
+		}
+	})
+}
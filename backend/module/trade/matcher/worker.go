@@ -0,0 +1,108 @@
+package matcher
+
+import (
+	"sync"
+	"time"
+)
+
+// bookWorkerQueueSize is the command buffer of each book goroutine
+const bookWorkerQueueSize = 256
+
+// bookWorker owns one order book. Every read and write of the book is a
+// command run on the worker's goroutine, which makes it the single writer.
+type bookWorker struct {
+	book *OrderBook
+	cmds chan func()
+}
+
+var (
+	defaultEngine     *Engine
+	defaultEngineOnce sync.Once
+)
+
+// Default returns the engine shared by the order and trade modules
+func Default() *Engine {
+	defaultEngineOnce.Do(func() {
+		defaultEngine = NewEngine(nil)
+	})
+	return defaultEngine
+}
+
+// worker returns the worker for symbol, starting it if needed
+func (e *Engine) worker(symbol string) *bookWorker {
+	e.mu.RLock()
+	w, exists := e.workers[symbol]
+	e.mu.RUnlock()
+	if exists {
+		return w
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if w, exists := e.workers[symbol]; exists {
+		return w
+	}
+
+	book := NewOrderBook(symbol)
+	config, ok := e.breakerConfigs[symbol]
+	if !ok {
+		config = e.defaultBreaker
+	}
+	book.breaker = NewCircuitBreaker(config)
+	if algo, ok := e.algoConfigs[symbol]; ok {
+		book.algorithm = algo
+	} else {
+		book.algorithm = e.defaultAlgo
+	}
+
+	w = &bookWorker{
+		book: book,
+		cmds: make(chan func(), bookWorkerQueueSize),
+	}
+	e.workers[symbol] = w
+
+	e.wg.Add(1)
+	go e.run(w)
+	return w
+}
+
+// run applies commands to the worker's book until the engine stops
+func (e *Engine) run(w *bookWorker) {
+	defer e.wg.Done()
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		case cmd := <-w.cmds:
+			cmd()
+		}
+	}
+}
+
+// do runs fn on the worker's goroutine, publishes the book changes it
+// made, and waits for it to finish. fn must not call back into the
+// engine for the same symbol.
+// Returns false if the engine stopped before fn ran.
+func (e *Engine) do(w *bookWorker, fn func()) bool {
+	done := make(chan struct{})
+	select {
+	case w.cmds <- func() { fn(); e.publishChanges(w.book); close(done) }:
+	case <-e.stopCh:
+		return false
+	}
+
+	select {
+	case <-done:
+		return true
+	case <-e.stopCh:
+		return false
+	}
+}
+
+// after runs fn on the worker's goroutine once d has elapsed
+func (e *Engine) after(w *bookWorker, d time.Duration, fn func()) {
+	time.AfterFunc(d, func() {
+		e.do(w, fn)
+	})
+}
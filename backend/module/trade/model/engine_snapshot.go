@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EngineSnapshotCollection is the MongoDB collection name for matching
+// engine snapshots. It holds one document per symbol.
+const EngineSnapshotCollection = "engine_snapshots"
+
+// SnapshotOrder is a resting order as the engine last saw it
+type SnapshotOrder struct {
+	OrderID   string  `bson:"orderId" json:"orderId"`
+	Side      string  `bson:"side" json:"side"`
+	Price     float64 `bson:"price" json:"price"`
+	Remaining float64 `bson:"remaining" json:"remaining"`
+}
+
+// EngineSnapshot is the periodically persisted state of one order book.
+// Open orders in the order store remain the source of truth; the snapshot
+// carries what they can't (book sequence, breaker reference price). Its
+// orders are audit-only: recovery never rebuilds a book from them, it
+// only reports how far the books drifted since the snapshot was taken.
+type EngineSnapshot struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Symbol    string             `bson:"symbol" json:"symbol"`
+	Sequence  int64              `bson:"sequence" json:"sequence"`
+	LastPrice float64            `bson:"lastPrice" json:"lastPrice"`
+	Orders    []SnapshotOrder    `bson:"orders" json:"orders"`
+	TakenAt   time.Time          `bson:"takenAt" json:"takenAt"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MarketMakerConfigCollection is the MongoDB collection name for market maker settings.
+const MarketMakerConfigCollection = "market_maker_configs"
+
+// MarketMakerConfig sets how the synthetic market maker quotes one symbol.
+// Prices are quoted in basis points around the live reference price.
+type MarketMakerConfig struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Symbol          string             `bson:"symbol" json:"symbol"`
+	Enabled         bool               `bson:"enabled" json:"enabled"`
+	SpreadBps       float64            `bson:"spreadBps" json:"spreadBps"`             // Best bid to best ask
+	LevelSpacingBps float64            `bson:"levelSpacingBps" json:"levelSpacingBps"` // Gap between levels on a side
+	Levels          int                `bson:"levels" json:"levels"`                   // Depth quoted per side
+	Size            float64            `bson:"size" json:"size"`                       // Quantity per level
+	MaxInventory    float64            `bson:"maxInventory" json:"maxInventory"`       // Side stops quoting at +/- this position
+	SkewBps         float64            `bson:"skewBps" json:"skewBps"`                 // Quote shift at full inventory
+	TickSize        float64            `bson:"tickSize" json:"tickSize"`
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -3,6 +3,8 @@ package model
 import (
 	"time"
 
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/fees"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -45,6 +47,17 @@ type Trade struct {
 	NetAmount    float64            `bson:"netAmount" json:"netAmount"`   // Total +/- Commission based on side
 	ExecutedAt   time.Time          `bson:"executedAt" json:"executedAt"`
 	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+
+	// Currency the trade is priced in and, when the account holds another,
+	// the rate (spread included) its cash was converted at
+	Currency string  `bson:"currency,omitempty" json:"currency,omitempty"`
+	FXRate   float64 `bson:"fxRate,omitempty" json:"fxRate,omitempty"`
+
+	// SettleDate is when the trade's value clears (T+n for its instrument)
+	SettleDate time.Time `bson:"settleDate,omitempty" json:"settleDate,omitempty"`
+
+	// Fees itemizes Commission and the regulatory fees in NetAmount
+	Fees *fees.Breakdown `bson:"fees,omitempty" json:"fees,omitempty"`
 }
 
 // NewTrade creates a trade with calculated totals.
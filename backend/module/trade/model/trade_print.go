@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TradePrintCollection is the MongoDB collection name for the public tape.
+const TradePrintCollection = "trade_prints"
+
+// TradePrint is an anonymized execution on a symbol's public tape
+// (time & sales). One print is recorded per engine match.
+type TradePrint struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Symbol        string             `bson:"symbol" json:"symbol"`
+	Price         float64            `bson:"price" json:"price"`
+	Quantity      float64            `bson:"quantity" json:"quantity"`
+	AggressorSide string             `bson:"aggressorSide,omitempty" json:"aggressorSide,omitempty"` // BUY or SELL; empty for auction uncrosses
+	ExecutedAt    time.Time          `bson:"executedAt" json:"executedAt"`
+}
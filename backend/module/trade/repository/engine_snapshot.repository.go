@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EngineSnapshotRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEngineSnapshotRepository() *EngineSnapshotRepository {
+	return &EngineSnapshotRepository{
+		collection: database.GetCollection(model.EngineSnapshotCollection),
+	}
+}
+
+// Save replaces the symbol's snapshot
+func (r *EngineSnapshotRepository) Save(ctx context.Context, snapshot *model.EngineSnapshot) error {
+	if snapshot.TakenAt.IsZero() {
+		snapshot.TakenAt = time.Now()
+	}
+
+	_, err := r.collection.ReplaceOne(ctx,
+		bson.M{"symbol": snapshot.Symbol},
+		snapshot,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *EngineSnapshotRepository) FindAll(ctx context.Context) ([]model.EngineSnapshot, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var snapshots []model.EngineSnapshot
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
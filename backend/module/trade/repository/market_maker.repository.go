@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MarketMakerRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMarketMakerRepository() *MarketMakerRepository {
+	return &MarketMakerRepository{
+		collection: database.GetCollection(model.MarketMakerConfigCollection),
+	}
+}
+
+// Save replaces the symbol's config
+func (r *MarketMakerRepository) Save(ctx context.Context, config *model.MarketMakerConfig) error {
+	config.UpdatedAt = time.Now()
+
+	_, err := r.collection.ReplaceOne(ctx,
+		bson.M{"symbol": config.Symbol},
+		config,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *MarketMakerRepository) FindAll(ctx context.Context) ([]model.MarketMakerConfig, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var configs []model.MarketMakerConfig
+	if err := cursor.All(ctx, &configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// Delete removes the symbol's config, reporting whether one existed
+func (r *MarketMakerRepository) Delete(ctx context.Context, symbol string) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"symbol": symbol})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -37,6 +37,12 @@ func (r *TradeRepository) Create(ctx context.Context, trade *model.Trade) error
 	return nil
 }
 
+// Delete removes a trade that was recorded but never settled
+func (r *TradeRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
 func (r *TradeRepository) FindByOrderID(ctx context.Context, orderID string) ([]model.Trade, error) {
 	objectID, err := primitive.ObjectIDFromHex(orderID)
 	if err != nil {
@@ -103,6 +109,25 @@ func (r *TradeRepository) FindByUserID(ctx context.Context, userID string, filte
 	return trades, total, nil
 }
 
+// FindByAccountIDBefore returns an account's trades executed before a
+// time, oldest first
+func (r *TradeRepository) FindByAccountIDBefore(ctx context.Context, accountID primitive.ObjectID, before time.Time) ([]model.Trade, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "executedAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"accountId":  accountID,
+		"executedAt": bson.M{"$lt": before},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var trades []model.Trade
+	if err := cursor.All(ctx, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
 func (r *TradeRepository) FindByPortfolioID(ctx context.Context, portfolioID string, limit int) ([]model.Trade, error) {
 	objectID, err := primitive.ObjectIDFromHex(portfolioID)
 	if err != nil {
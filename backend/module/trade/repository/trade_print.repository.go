@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/model"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TradePrintRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTradePrintRepository() *TradePrintRepository {
+	return &TradePrintRepository{
+		collection: database.GetCollection(model.TradePrintCollection),
+	}
+}
+
+func (r *TradePrintRepository) Create(ctx context.Context, tradePrint *model.TradePrint) error {
+	if tradePrint.ExecutedAt.IsZero() {
+		tradePrint.ExecutedAt = time.Now()
+	}
+
+	result, err := r.collection.InsertOne(ctx, tradePrint)
+	if err != nil {
+		return err
+	}
+
+	tradePrint.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindBySymbol returns up to limit prints for a symbol, newest first.
+// A non-zero before pages back from that print ID.
+func (r *TradePrintRepository) FindBySymbol(ctx context.Context, symbol string, before primitive.ObjectID, limit int) ([]model.TradePrint, error) {
+	query := bson.M{"symbol": symbol}
+	if !before.IsZero() {
+		query["_id"] = bson.M{"$lt": before}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var prints []model.TradePrint
+	if err := cursor.All(ctx, &prints); err != nil {
+		return nil, err
+	}
+	return prints, nil
+}
+
+// LastBefore returns a symbol's last print before a time, or nil if it
+// has none
+func (r *TradePrintRepository) LastBefore(ctx context.Context, symbol string, before time.Time) (*model.TradePrint, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "executedAt", Value: -1}})
+	var tradePrint model.TradePrint
+	err := r.collection.FindOne(ctx, bson.M{
+		"symbol":     symbol,
+		"executedAt": bson.M{"$lt": before},
+	}, opts).Decode(&tradePrint)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tradePrint, nil
+}
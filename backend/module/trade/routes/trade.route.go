@@ -1,18 +1,38 @@
 package routes
 
 import (
+	"context"
+	"log"
+
 	accountRepo "github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	accountService "github.com/bricksocoolxd/bengi-investment-system/module/account/service"
 	authModel "github.com/bricksocoolxd/bengi-investment-system/module/auth/model"
+	instrumentRepo "github.com/bricksocoolxd/bengi-investment-system/module/instrument/repository"
 	orderRepo "github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
 	portfolioRepo "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/repository"
 	"github.com/bricksocoolxd/bengi-investment-system/module/trade/controller"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/fees"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
 	"github.com/bricksocoolxd/bengi-investment-system/module/trade/repository"
 	"github.com/bricksocoolxd/bengi-investment-system/module/trade/service"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/config"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/middleware"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
 	"github.com/gofiber/fiber/v2"
 )
 
 func RegisterRoutes(app *fiber.App) {
+	// Fee schedule: a configured file replaces the built-in plans
+	if path := config.AppConfig.FeeScheduleFile; path != "" {
+		schedule, err := fees.LoadFile(path)
+		if err == nil {
+			err = fees.Default().Load(schedule)
+		}
+		if err != nil {
+			log.Printf("[Fees] Keeping built-in schedule, %s: %v", path, err)
+		}
+	}
+
 	// Wire up dependencies
 	tradeRepo := repository.NewTradeRepository()
 	orderRepository := orderRepo.NewOrderRepository()
@@ -24,9 +44,71 @@ func RegisterRoutes(app *fiber.App) {
 		orderRepository,
 		accountRepository,
 		portfolioRepository,
+		accountService.NewSettlementService(
+			accountRepo.NewSettlementRepository(),
+			accountRepository,
+			accountService.NewLedgerService(accountRepo.NewLedgerRepository(), accountRepository),
+		),
 	)
 	ctrl := controller.NewTradeController(tradeSvc)
 
+	// Matching engine: fills settle through the trade service, move the
+	// market maker's inventory and print to the public tape; book changes
+	// stream to book:SYMBOL subscribers
+	engine := matcher.Default()
+	orderBookSvc := service.NewOrderBookService(engine, instrumentRepo.NewInstrumentRepository())
+	tapeSvc := service.NewTapeService(repository.NewTradePrintRepository())
+	marketMakerSvc := service.NewMarketMakerService(engine, repository.NewMarketMakerRepository())
+	engine.SetMatchHandler(func(match *matcher.Match) error {
+		if err := tradeSvc.HandleMatch(match); err != nil {
+			return err
+		}
+		marketMakerSvc.RecordFill(match)
+		// The match has settled, so the book fills whatever the tape does
+		if err := tapeSvc.Record(match); err != nil {
+			log.Printf("[Tape] Failed to record %s print: %v", match.Symbol, err)
+		}
+		return nil
+	})
+	engine.SetBookHandler(orderBookSvc.PublishUpdate)
+	engine.SetSessionFunc(orderBookSvc.SessionPhase)
+	engine.Start()
+	ws.RegisterSnapshot(ws.TopicBookPrefix, orderBookSvc.SnapshotMessage)
+	ws.RegisterSnapshot(ws.TopicTradesPrefix, tapeSvc.SnapshotMessage)
+
+	// Rebuild the books from open orders before accepting new ones, then
+	// keep snapshots of them
+	recoverySvc := service.NewEngineRecoveryService(
+		engine,
+		orderRepository,
+		repository.NewEngineSnapshotRepository(),
+		tradeSvc,
+	)
+	ctx := context.Background()
+	if _, err := recoverySvc.Recover(ctx); err != nil {
+		log.Printf("[Matcher] Recovery failed: %v", err)
+	}
+	go recoverySvc.StartPeriodicSnapshots(ctx, service.EngineSnapshotInterval)
+	go orderBookSvc.StartSessionRematch(ctx)
+	bookCtrl := controller.NewOrderBookController(orderBookSvc)
+
+	// Synthetic liquidity: the market maker's fills are not settled, so
+	// the trade service has to know its user before it quotes
+	if config.AppConfig.MarketMakerEnabled {
+		if userID, err := marketMakerSvc.Init(ctx); err != nil {
+			log.Printf("[MarketMaker] Disabled: %v", err)
+		} else {
+			tradeSvc.SetLiquidityProvider(userID)
+			go marketMakerSvc.Start(ctx, service.MarketMakerRefreshInterval)
+		}
+	}
+	marketMakerCtrl := controller.NewMarketMakerController(marketMakerSvc)
+
+	feeCtrl := controller.NewFeeController(service.NewFeeService(accountRepository, fees.Default()))
+
+	// Public order book depth
+	app.Get("/api/v1/orderbook/:symbol", bookCtrl.GetOrderBook)
+
 	// Protected routes
 	trades := app.Group("/api/v1/trades", middleware.AuthRequired())
 
@@ -40,6 +122,23 @@ func RegisterRoutes(app *fiber.App) {
 		ctrl.ExecuteTrade,
 	)
 
+	// Admin only - market maker configuration
+	marketMaker := app.Group("/api/v1/market-maker",
+		middleware.AuthRequired(),
+		middleware.RoleRequired(authModel.RoleAdmin),
+	)
+	marketMaker.Get("/", marketMakerCtrl.GetStatus)
+	marketMaker.Put("/:symbol", marketMakerCtrl.SetConfig)
+	marketMaker.Delete("/:symbol", marketMakerCtrl.RemoveConfig)
+
+	// Admin only - fee schedule and account fee plans
+	feeRoutes := app.Group("/api/v1/fees",
+		middleware.AuthRequired(),
+		middleware.RoleRequired(authModel.RoleAdmin),
+	)
+	feeRoutes.Get("/schedule", feeCtrl.GetSchedule)
+	feeRoutes.Put("/accounts/:id/plan", feeCtrl.SetAccountPlan)
+
 	// Order trades route
 	orders := app.Group("/api/v1/orders", middleware.AuthRequired())
 	orders.Get("/:id/trades", ctrl.GetTradesByOrderID)
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	accountRepo "github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/fees"
+)
+
+var ErrFeeAccountNotFound = errors.New("account not found")
+
+type FeeService struct {
+	accountRepository *accountRepo.AccountRepository
+	engine            *fees.Engine
+}
+
+func NewFeeService(accountRepository *accountRepo.AccountRepository, engine *fees.Engine) *FeeService {
+	return &FeeService{
+		accountRepository: accountRepository,
+		engine:            engine,
+	}
+}
+
+// GetSchedule returns the fee schedule in force
+func (s *FeeService) GetSchedule() fees.Config {
+	return s.engine.Config()
+}
+
+// SetAccountPlan moves an account onto a fee plan; later fills are
+// priced on it
+func (s *FeeService) SetAccountPlan(ctx context.Context, accountID string, req *dto.FeePlanRequest) (*dto.FeePlanResponse, error) {
+	plan := strings.ToUpper(strings.TrimSpace(req.Plan))
+	if !s.engine.HasPlan(plan) {
+		return nil, fees.ErrUnknownPlan
+	}
+
+	account, err := s.accountRepository.FindByID(ctx, accountID)
+	if err != nil || account == nil {
+		return nil, ErrFeeAccountNotFound
+	}
+	if err := s.accountRepository.UpdateField(ctx, account.ID, "feePlan", plan); err != nil {
+		return nil, err
+	}
+
+	return &dto.FeePlanResponse{
+		AccountID: account.ID.Hex(),
+		Plan:      plan,
+	}, nil
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	authModel "github.com/bricksocoolxd/bengi-investment-system/module/auth/model"
+	authRepo "github.com/bricksocoolxd/bengi-investment-system/module/auth/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+	tradeModel "github.com/bricksocoolxd/bengi-investment-system/module/trade/model"
+	tradeRepo "github.com/bricksocoolxd/bengi-investment-system/module/trade/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MarketMakerRefreshInterval is how often quotes are rebuilt
+	MarketMakerRefreshInterval = 2 * time.Second
+
+	// DefaultTickSize is used when a config sets none
+	DefaultTickSize = 0.01
+)
+
+var ErrMarketMakerConfigNotFound = errors.New("market maker config not found")
+
+// Quote is one price level the market maker wants working
+type Quote struct {
+	Side     string
+	Price    float64
+	Quantity float64
+}
+
+// BuildQuotes lays out a two-sided ladder around reference. The centre is
+// shifted against inventory by up to SkewBps so fills pull the position
+// back towards flat, and a side stops quoting once it would take the
+// position beyond MaxInventory. Bids round down and asks round up to the
+// tick, so the ladder never crosses itself.
+func BuildQuotes(config *tradeModel.MarketMakerConfig, reference, inventory float64) []Quote {
+	if reference <= 0 || config.Levels <= 0 || config.Size <= 0 {
+		return nil
+	}
+
+	tick := config.TickSize
+	if tick <= 0 {
+		tick = DefaultTickSize
+	}
+
+	center := reference
+	if config.MaxInventory > 0 {
+		ratio := math.Max(-1, math.Min(1, inventory/config.MaxInventory))
+		center = reference * (1 - ratio*config.SkewBps/10000)
+	}
+
+	quoteBids := config.MaxInventory <= 0 || inventory < config.MaxInventory
+	quoteAsks := config.MaxInventory <= 0 || inventory > -config.MaxInventory
+
+	half := config.SpreadBps / 2 / 10000
+	step := config.LevelSpacingBps / 10000
+
+	quotes := make([]Quote, 0, config.Levels*2)
+	for i := 0; i < config.Levels; i++ {
+		offset := half + float64(i)*step
+		if quoteBids {
+			if bid := math.Floor(center*(1-offset)/tick) * tick; bid > 0 {
+				quotes = append(quotes, Quote{Side: "BUY", Price: roundTick(bid), Quantity: config.Size})
+			}
+		}
+		if quoteAsks {
+			ask := math.Ceil(center*(1+offset)/tick) * tick
+			quotes = append(quotes, Quote{Side: "SELL", Price: roundTick(ask), Quantity: config.Size})
+		}
+	}
+	return quotes
+}
+
+// roundTick strips float noise left by tick arithmetic
+func roundTick(price float64) float64 {
+	return math.Round(price*1e8) / 1e8
+}
+
+// workingQuote is a quote resting in the engine. The book owns the order
+// itself; the market maker only remembers what it placed.
+type workingQuote struct {
+	orderID string
+}
+
+// marketMakerBook is the market maker's state for one symbol
+type marketMakerBook struct {
+	config    tradeModel.MarketMakerConfig
+	reference float64
+	working   []workingQuote
+}
+
+// MarketMakerService quotes synthetic two-sided liquidity into the engine
+// as a system user, so demo traders have someone to trade with. Its
+// orders live only in the engine; fills are tracked as inventory from
+// the engine's matches.
+type MarketMakerService struct {
+	engine     *matcher.Engine
+	repository *tradeRepo.MarketMakerRepository
+	userRepo   *authRepo.UserRepository
+	prices     func(symbol string) float64
+
+	mu    sync.Mutex
+	books map[string]*marketMakerBook
+	seq   uint64
+
+	// Fills arrive on book goroutines, possibly while mu is held waiting
+	// on the engine, so inventory has its own lock. userID is written
+	// under both locks and may be read under either.
+	fillMu    sync.Mutex
+	userID    string
+	inventory map[string]float64
+}
+
+func NewMarketMakerService(engine *matcher.Engine, repository *tradeRepo.MarketMakerRepository) *MarketMakerService {
+	return &MarketMakerService{
+		engine:     engine,
+		repository: repository,
+		userRepo:   authRepo.NewUserRepository(),
+		prices:     livePrice,
+		books:      make(map[string]*marketMakerBook),
+		inventory:  make(map[string]float64),
+	}
+}
+
+// livePrice returns the last trade price from the Finnhub stream
+func livePrice(symbol string) float64 {
+	if last := ws.GetPriceStream().GetLastPrice(symbol); last != nil {
+		return last.Price
+	}
+	return 0
+}
+
+// Init resolves the system user and loads saved configs. Returns the user
+// ID the market maker trades as.
+func (s *MarketMakerService) Init(ctx context.Context) (string, error) {
+	user, err := s.userRepo.FindByEmail(ctx, authModel.MarketMakerEmail)
+	if err != nil {
+		return "", fmt.Errorf("market maker user: %w", err)
+	}
+
+	configs, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fillMu.Lock()
+	s.userID = user.ID.Hex()
+	s.fillMu.Unlock()
+	for _, config := range configs {
+		s.books[config.Symbol] = &marketMakerBook{config: config}
+	}
+	s.subscribePrices()
+	return s.userID, nil
+}
+
+// Start requotes every configured symbol until ctx is done
+func (s *MarketMakerService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[MarketMaker] Stopping")
+			return
+		case <-ticker.C:
+			s.refreshAll()
+		}
+	}
+}
+
+// refreshAll pulls every symbol's quotes and places a fresh ladder
+func (s *MarketMakerService) refreshAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for symbol, book := range s.books {
+		s.refresh(book)
+		// Removed configs are kept until their quotes are pulled
+		if book.config.ID.IsZero() && len(book.working) == 0 {
+			delete(s.books, symbol)
+		}
+	}
+}
+
+// RecordFill books the market maker's side of a settled match as
+// inventory. Quotes that leave the book any other way, e.g. cancelled by
+// self-trade prevention or a halt, never change it. Runs on the book's
+// goroutine.
+func (s *MarketMakerService) RecordFill(match *matcher.Match) {
+	s.fillMu.Lock()
+	defer s.fillMu.Unlock()
+
+	if s.userID == "" {
+		return
+	}
+	if match.BuyerID == s.userID {
+		s.inventory[match.Symbol] += match.Quantity
+	}
+	if match.SellerID == s.userID {
+		s.inventory[match.Symbol] -= match.Quantity
+	}
+}
+
+// inventoryOf returns the market maker's position in symbol
+func (s *MarketMakerService) inventoryOf(symbol string) float64 {
+	s.fillMu.Lock()
+	defer s.fillMu.Unlock()
+	return s.inventory[symbol]
+}
+
+// refresh withdraws the symbol's working quotes and quotes again around
+// the latest price, skewed by the inventory their fills built up. Caller
+// holds mu.
+func (s *MarketMakerService) refresh(book *marketMakerBook) {
+	symbol := book.config.Symbol
+
+	for _, quote := range book.working {
+		s.engine.Withdraw(symbol, quote.orderID)
+	}
+	book.working = book.working[:0]
+
+	if !book.config.Enabled || book.config.ID.IsZero() {
+		return
+	}
+
+	book.reference = s.prices(symbol)
+	for _, quote := range BuildQuotes(&book.config, book.reference, s.inventoryOf(symbol)) {
+		s.seq++
+		orderID := fmt.Sprintf("mm-%s-%d", symbol, s.seq)
+		book.working = append(book.working, workingQuote{orderID: orderID})
+		s.engine.AddOrder(&matcher.Order{
+			ID:            orderID,
+			UserID:        s.userID,
+			Symbol:        symbol,
+			Side:          quote.Side,
+			Type:          "LIMIT",
+			Price:         quote.Price,
+			Quantity:      quote.Quantity,
+			Timestamp:     time.Now().UnixMilli(),
+			SelfTradeMode: matcher.SelfTradeCancelOldest,
+		})
+	}
+}
+
+// GetStatus returns every config with its live inventory and quotes
+func (s *MarketMakerService) GetStatus() []dto.MarketMakerResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]dto.MarketMakerResponse, 0, len(s.books))
+	for _, book := range s.books {
+		if book.config.ID.IsZero() {
+			continue
+		}
+		result = append(result, s.toMarketMakerResponse(book))
+	}
+	return result
+}
+
+// SetConfig saves a symbol's quoting parameters; they apply at the next refresh
+func (s *MarketMakerService) SetConfig(ctx context.Context, symbol string, req *dto.MarketMakerConfigRequest) (*dto.MarketMakerResponse, error) {
+	symbol = strings.ToUpper(symbol)
+	config := &tradeModel.MarketMakerConfig{
+		Symbol:          symbol,
+		Enabled:         req.Enabled,
+		SpreadBps:       req.SpreadBps,
+		LevelSpacingBps: req.LevelSpacingBps,
+		Levels:          req.Levels,
+		Size:            req.Size,
+		MaxInventory:    req.MaxInventory,
+		SkewBps:         req.SkewBps,
+		TickSize:        req.TickSize,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book, exists := s.books[symbol]
+	if exists && !book.config.ID.IsZero() {
+		config.ID = book.config.ID
+	} else {
+		config.ID = primitive.NewObjectID()
+	}
+	if err := s.repository.Save(ctx, config); err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		book = &marketMakerBook{}
+		s.books[symbol] = book
+	}
+	book.config = *config
+	s.subscribePrices()
+
+	response := s.toMarketMakerResponse(book)
+	return &response, nil
+}
+
+// RemoveConfig stops quoting a symbol; working quotes are pulled at the
+// next refresh
+func (s *MarketMakerService) RemoveConfig(ctx context.Context, symbol string) error {
+	symbol = strings.ToUpper(symbol)
+	deleted, err := s.repository.Delete(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrMarketMakerConfigNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if book, ok := s.books[symbol]; ok {
+		book.config.ID = primitive.NilObjectID
+	}
+	return nil
+}
+
+// subscribePrices makes sure the price stream watches every enabled
+// symbol. Caller holds mu.
+func (s *MarketMakerService) subscribePrices() {
+	symbols := make([]string, 0, len(s.books))
+	for symbol, book := range s.books {
+		if book.config.Enabled {
+			symbols = append(symbols, symbol)
+		}
+	}
+	ws.GetPriceStream().Subscribe(symbols...)
+}
+
+// Helper: Convert marketMakerBook to MarketMakerResponse
+func (s *MarketMakerService) toMarketMakerResponse(book *marketMakerBook) dto.MarketMakerResponse {
+	return dto.MarketMakerResponse{
+		Symbol:          book.config.Symbol,
+		Enabled:         book.config.Enabled,
+		SpreadBps:       book.config.SpreadBps,
+		LevelSpacingBps: book.config.LevelSpacingBps,
+		Levels:          book.config.Levels,
+		Size:            book.config.Size,
+		MaxInventory:    book.config.MaxInventory,
+		SkewBps:         book.config.SkewBps,
+		TickSize:        book.config.TickSize,
+		Inventory:       s.inventoryOf(book.config.Symbol),
+		ReferencePrice:  book.reference,
+		WorkingQuotes:   len(book.working),
+		UpdatedAt:       book.config.UpdatedAt,
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	instrumentRepo "github.com/bricksocoolxd/bengi-investment-system/module/instrument/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
+)
+
+const (
+	DefaultBookDepth = 20
+	MaxBookDepth     = 500
+)
+
+type OrderBookService struct {
+	engine         *matcher.Engine
+	instrumentRepo *instrumentRepo.InstrumentRepository
+	calendar       *calendar.Calendar
+	exchanges      sync.Map // symbol -> calendar exchange code
+}
+
+func NewOrderBookService(engine *matcher.Engine, instrumentRepository *instrumentRepo.InstrumentRepository) *OrderBookService {
+	return &OrderBookService{
+		engine:         engine,
+		instrumentRepo: instrumentRepository,
+		calendar:       calendar.Default(),
+	}
+}
+
+// GetOrderBook returns up to depth aggregated price levels per side
+func (s *OrderBookService) GetOrderBook(symbol string, depth int) *dto.OrderBookResponse {
+	if depth <= 0 {
+		depth = DefaultBookDepth
+	}
+	if depth > MaxBookDepth {
+		depth = MaxBookDepth
+	}
+
+	snapshot := s.engine.Snapshot(strings.ToUpper(symbol), depth)
+	return &dto.OrderBookResponse{
+		Symbol:   snapshot.Symbol,
+		Sequence: snapshot.Sequence,
+		Phase:    string(snapshot.Phase),
+		Bids:     toPriceLevels(snapshot.Bids),
+		Asks:     toPriceLevels(snapshot.Asks),
+	}
+}
+
+// SnapshotMessage builds the BOOK_SNAPSHOT sent to new book:SYMBOL subscribers
+func (s *OrderBookService) SnapshotMessage(topic string) *ws.Message {
+	symbol := strings.TrimPrefix(topic, ws.TopicBookPrefix)
+	snapshot := s.engine.Snapshot(symbol, MaxBookDepth)
+
+	return ws.NewMessage(ws.TypeBookSnapshot, topic, &ws.BookPayload{
+		Symbol:   snapshot.Symbol,
+		Sequence: snapshot.Sequence,
+		Bids:     toBookLevels(snapshot.Bids),
+		Asks:     toBookLevels(snapshot.Asks),
+	})
+}
+
+// PublishUpdate forwards an engine book update to book:SYMBOL subscribers
+func (s *OrderBookService) PublishUpdate(update *matcher.BookUpdate) {
+	ws.PublishBookUpdate(&ws.BookPayload{
+		Symbol:   update.Symbol,
+		Sequence: update.Sequence,
+		Bids:     toBookLevels(update.Bids),
+		Asks:     toBookLevels(update.Asks),
+	})
+}
+
+// SessionPhase returns the session phase of a symbol's exchange for the
+// engine. Exchanges are cached per symbol; unknown symbols trade as open.
+func (s *OrderBookService) SessionPhase(symbol string) calendar.Phase {
+	code, ok := s.exchanges.Load(symbol)
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		instrument, err := s.instrumentRepo.FindBySymbol(ctx, symbol)
+		if err != nil {
+			return calendar.PhaseRegular
+		}
+		code = s.calendar.Canonical(calendar.ExchangeForInstrument(instrument.Exchange, string(instrument.Type)))
+		s.exchanges.Store(symbol, code)
+	}
+
+	exchange, found := s.calendar.Exchange(code.(string))
+	if !found {
+		return calendar.PhaseRegular
+	}
+	return exchange.PhaseAt(time.Now())
+}
+
+// StartSessionRematch re-runs matching on an exchange's books at each of
+// its session boundaries, so orders waiting for an eligible session, e.g.
+// regular-hours orders placed overnight, match at the open without a new
+// order arriving. Blocks until ctx is cancelled.
+func (s *OrderBookService) StartSessionRematch(ctx context.Context) {
+	for {
+		next, codes := s.nextTransition(time.Now())
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if symbols := s.symbolsOn(codes); len(symbols) > 0 {
+			s.engine.Rematch(symbols...)
+		}
+	}
+}
+
+// nextTransition returns the earliest session boundary after now across
+// the calendar's exchanges, and the exchanges that change phase then
+func (s *OrderBookService) nextTransition(now time.Time) (time.Time, map[string]bool) {
+	var next time.Time
+	codes := make(map[string]bool)
+	for _, exchange := range s.calendar.Exchanges() {
+		at := exchange.NextTransition(now)
+		switch {
+		case at.IsZero():
+		case next.IsZero() || at.Before(next):
+			next = at
+			codes = map[string]bool{exchange.Code: true}
+		case at.Equal(next):
+			codes[exchange.Code] = true
+		}
+	}
+	return next, codes
+}
+
+// symbolsOn returns the symbols the engine has looked up on the given
+// exchanges. Every book that has matched is among them, since matching
+// looks up its session phase.
+func (s *OrderBookService) symbolsOn(codes map[string]bool) []string {
+	var symbols []string
+	s.exchanges.Range(func(symbol, code interface{}) bool {
+		if codes[code.(string)] {
+			symbols = append(symbols, symbol.(string))
+		}
+		return true
+	})
+	return symbols
+}
+
+func toPriceLevels(levels []matcher.LevelDepth) []dto.PriceLevelResponse {
+	result := make([]dto.PriceLevelResponse, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, dto.PriceLevelResponse{
+			Price:    level.Price,
+			Quantity: level.Quantity,
+			Orders:   level.Orders,
+		})
+	}
+	return result
+}
+
+func toBookLevels(levels []matcher.LevelDepth) []ws.BookLevel {
+	result := make([]ws.BookLevel, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, ws.BookLevel{
+			Price:    level.Price,
+			Quantity: level.Quantity,
+			Orders:   level.Orders,
+		})
+	}
+	return result
+}
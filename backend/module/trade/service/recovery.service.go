@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	orderModel "github.com/bricksocoolxd/bengi-investment-system/module/order/model"
+	orderRepo "github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
+	orderService "github.com/bricksocoolxd/bengi-investment-system/module/order/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+	tradeModel "github.com/bricksocoolxd/bengi-investment-system/module/trade/model"
+	tradeRepo "github.com/bricksocoolxd/bengi-investment-system/module/trade/repository"
+)
+
+// EngineSnapshotInterval is how often books are snapshotted
+const EngineSnapshotInterval = 30 * time.Second
+
+// RecoveryResult summarises an engine rebuild
+type RecoveryResult struct {
+	Books      int // Books restored
+	Orders     int // Resting orders loaded
+	Reconciled int // Persisted trades applied to orders that missed them
+	Drifted    int // Snapshot orders whose remaining quantity no longer matches
+}
+
+// EngineRecoveryService rebuilds the in-memory books from open orders on
+// startup and snapshots them periodically
+type EngineRecoveryService struct {
+	engine             *matcher.Engine
+	orderRepository    *orderRepo.OrderRepository
+	snapshotRepository *tradeRepo.EngineSnapshotRepository
+	tradeService       *TradeService
+}
+
+func NewEngineRecoveryService(
+	engine *matcher.Engine,
+	orderRepository *orderRepo.OrderRepository,
+	snapshotRepository *tradeRepo.EngineSnapshotRepository,
+	tradeService *TradeService,
+) *EngineRecoveryService {
+	return &EngineRecoveryService{
+		engine:             engine,
+		orderRepository:    orderRepository,
+		snapshotRepository: snapshotRepository,
+		tradeService:       tradeService,
+	}
+}
+
+// Recover reconciles open orders against their persisted trades, loads
+// the ones that still rest into the engine, then rematches every book.
+// Must run before the engine accepts new orders.
+//
+// Books are rebuilt from the order store alone, in arrival order
+// (createdAt, then _id), which gives every order its original time
+// priority and side. Snapshots only restore the book sequence and the
+// breaker reference; their order lists are audit-only, used to report
+// drift. An iceberg that had been refilled, and so sent to the back of
+// its level, gets its arrival priority back.
+func (s *EngineRecoveryService) Recover(ctx context.Context) (*RecoveryResult, error) {
+	snapshots, err := s.snapshotRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Suspended orders are held out of the book until their halt ends
+	orders, err := s.orderRepository.FindResting(ctx, nil, []orderModel.OrderStatus{
+		orderModel.OrderStatusPending,
+		orderModel.OrderStatusOpen,
+		orderModel.OrderStatusPartiallyFilled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecoveryResult{}
+	states := make(map[string]*matcher.BookState)
+	state := func(symbol string) *matcher.BookState {
+		if _, ok := states[symbol]; !ok {
+			states[symbol] = &matcher.BookState{Symbol: symbol}
+		}
+		return states[symbol]
+	}
+
+	for i := range orders {
+		order := &orders[i]
+		applied, err := s.tradeService.ReconcileOrder(ctx, order)
+		result.Reconciled += applied
+		if err != nil {
+			// Leave it out of the book rather than risk matching it twice
+			log.Printf("[Recovery] Skipping order %s: %v", order.ID.Hex(), err)
+			continue
+		}
+		if order.Status == orderModel.OrderStatusFilled || !orderService.RestsInBook(order) {
+			continue
+		}
+
+		book := state(order.Symbol)
+		book.Orders = append(book.Orders, orderService.ToEngineOrder(order))
+		result.Orders++
+	}
+
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		book := state(snapshot.Symbol)
+		book.Sequence = uint64(snapshot.Sequence)
+		book.LastPrice = snapshot.LastPrice
+		result.Drifted += drift(snapshot, book.Orders)
+	}
+
+	symbols := make([]string, 0, len(states))
+	for symbol, book := range states {
+		s.engine.Restore(book)
+		symbols = append(symbols, symbol)
+	}
+	result.Books = len(symbols)
+
+	// Orders that crossed when the process died match now
+	s.engine.Rematch(symbols...)
+
+	log.Printf("[Recovery] Restored %d orders across %d books, reconciled %d trades, %d orders drifted since the last snapshot",
+		result.Orders, result.Books, result.Reconciled, result.Drifted)
+	return result, nil
+}
+
+// SnapshotAll persists the state of every book
+func (s *EngineRecoveryService) SnapshotAll(ctx context.Context) error {
+	for _, state := range s.engine.States() {
+		snapshot := &tradeModel.EngineSnapshot{
+			Symbol:    state.Symbol,
+			Sequence:  int64(state.Sequence),
+			LastPrice: state.LastPrice,
+			Orders:    make([]tradeModel.SnapshotOrder, 0, len(state.Orders)),
+			TakenAt:   time.Now(),
+		}
+		for _, order := range state.Orders {
+			snapshot.Orders = append(snapshot.Orders, tradeModel.SnapshotOrder{
+				OrderID:   order.ID,
+				Side:      order.Side,
+				Price:     order.Price,
+				Remaining: order.Remaining(),
+			})
+		}
+		if err := s.snapshotRepository.Save(ctx, snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartPeriodicSnapshots snapshots the books every interval until ctx is done
+func (s *EngineRecoveryService) StartPeriodicSnapshots(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[Recovery] Stopping periodic snapshots")
+			return
+		case <-ticker.C:
+			if err := s.SnapshotAll(ctx); err != nil {
+				log.Printf("[Recovery] Snapshot failed: %v", err)
+			}
+		}
+	}
+}
+
+// drift counts snapshot orders that are gone or whose remaining quantity
+// changed by the time the book was rebuilt
+func drift(snapshot *tradeModel.EngineSnapshot, orders []*matcher.Order) int {
+	remaining := make(map[string]float64, len(orders))
+	for _, order := range orders {
+		remaining[order.ID] = order.Remaining()
+	}
+
+	drifted := 0
+	for _, order := range snapshot.Orders {
+		if qty, ok := remaining[order.OrderID]; !ok || qty != order.Remaining {
+			drifted++
+		}
+	}
+	return drifted
+}
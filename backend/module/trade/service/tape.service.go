@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+	tradeModel "github.com/bricksocoolxd/bengi-investment-system/module/trade/model"
+	tradeRepo "github.com/bricksocoolxd/bengi-investment-system/module/trade/repository"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DefaultTapeLimit  = 50
+	MaxTapeLimit      = 500
+	tapeSnapshotLimit = 50
+)
+
+var ErrInvalidCursor = errors.New("invalid tape cursor")
+
+// TapeService records anonymized prints for every engine match and
+// serves the public time & sales history
+type TapeService struct {
+	printRepository *tradeRepo.TradePrintRepository
+}
+
+func NewTapeService(printRepository *tradeRepo.TradePrintRepository) *TapeService {
+	return &TapeService{
+		printRepository: printRepository,
+	}
+}
+
+// Record persists a match as a print and publishes it to trades:SYMBOL.
+// Called on the symbol's engine goroutine, so prints stay in match order.
+func (s *TapeService) Record(match *matcher.Match) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tradePrint := &tradeModel.TradePrint{
+		Symbol:        match.Symbol,
+		Price:         match.Price,
+		Quantity:      match.Quantity,
+		AggressorSide: match.AggressorSide,
+		ExecutedAt:    time.UnixMilli(match.Timestamp),
+	}
+	if err := s.printRepository.Create(ctx, tradePrint); err != nil {
+		return err
+	}
+
+	ws.PublishTradePrint(toPrintPayload(tradePrint))
+	return nil
+}
+
+// GetTape returns up to limit prints for a symbol, newest first. before
+// is the ID of the oldest print already seen, for paging back.
+func (s *TapeService) GetTape(ctx context.Context, symbol, before string, limit int) (*dto.TapeResponse, error) {
+	if limit <= 0 {
+		limit = DefaultTapeLimit
+	}
+	if limit > MaxTapeLimit {
+		limit = MaxTapeLimit
+	}
+
+	var cursor primitive.ObjectID
+	if before != "" {
+		id, err := primitive.ObjectIDFromHex(before)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor = id
+	}
+
+	symbol = strings.ToUpper(symbol)
+	prints, err := s.printRepository.FindBySymbol(ctx, symbol, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PrintResponse, 0, len(prints))
+	for i := range prints {
+		responses = append(responses, s.toPrintResponse(&prints[i]))
+	}
+
+	result := &dto.TapeResponse{
+		Symbol: symbol,
+		Prints: responses,
+	}
+	if len(prints) == limit {
+		result.Next = prints[len(prints)-1].ID.Hex()
+	}
+	return result, nil
+}
+
+// SnapshotMessage builds the TAPE_SNAPSHOT of recent prints sent to new
+// trades:SYMBOL subscribers
+func (s *TapeService) SnapshotMessage(topic string) *ws.Message {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	symbol := strings.TrimPrefix(topic, ws.TopicTradesPrefix)
+	prints, err := s.printRepository.FindBySymbol(ctx, symbol, primitive.NilObjectID, tapeSnapshotLimit)
+	if err != nil {
+		return nil
+	}
+
+	payload := &ws.TapePayload{
+		Symbol: symbol,
+		Prints: make([]ws.PrintPayload, 0, len(prints)),
+	}
+	for i := range prints {
+		payload.Prints = append(payload.Prints, *toPrintPayload(&prints[i]))
+	}
+	return ws.NewMessage(ws.TypeTapeSnapshot, topic, payload)
+}
+
+// Helper: Convert TradePrint to PrintResponse
+func (s *TapeService) toPrintResponse(tradePrint *tradeModel.TradePrint) dto.PrintResponse {
+	return dto.PrintResponse{
+		ID:            tradePrint.ID.Hex(),
+		Price:         tradePrint.Price,
+		Quantity:      tradePrint.Quantity,
+		AggressorSide: tradePrint.AggressorSide,
+		ExecutedAt:    tradePrint.ExecutedAt,
+	}
+}
+
+func toPrintPayload(tradePrint *tradeModel.TradePrint) *ws.PrintPayload {
+	return &ws.PrintPayload{
+		ID:            tradePrint.ID.Hex(),
+		Symbol:        tradePrint.Symbol,
+		Price:         tradePrint.Price,
+		Quantity:      tradePrint.Quantity,
+		AggressorSide: tradePrint.AggressorSide,
+		Timestamp:     tradePrint.ExecutedAt.UnixMilli(),
+	}
+}
@@ -3,15 +3,25 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"sort"
 	"time"
 
 	accountModel "github.com/bricksocoolxd/bengi-investment-system/module/account/model"
 	accountRepo "github.com/bricksocoolxd/bengi-investment-system/module/account/repository"
+	accountService "github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	marketRepo "github.com/bricksocoolxd/bengi-investment-system/module/market/repository"
 	orderModel "github.com/bricksocoolxd/bengi-investment-system/module/order/model"
 	orderRepo "github.com/bricksocoolxd/bengi-investment-system/module/order/repository"
 	portfolioModel "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/model"
 	portfolioRepo "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/repository"
+	portfolioService "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/service"
 	"github.com/bricksocoolxd/bengi-investment-system/module/trade/dto"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/fees"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
 	tradeModel "github.com/bricksocoolxd/bengi-investment-system/module/trade/model"
 	tradeRepo "github.com/bricksocoolxd/bengi-investment-system/module/trade/repository"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
@@ -19,7 +29,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-const CommissionRate = 0.001 // 0.1% commission
+// fillTolerance absorbs float rounding when comparing filled quantities
+const fillTolerance = 1e-9
 
 var (
 	ErrTradeNotFound       = errors.New("trade not found")
@@ -29,6 +40,10 @@ var (
 	ErrInsufficientBalance = errors.New("insufficient balance")
 	ErrInsufficientShares  = errors.New("insufficient shares to sell")
 	ErrUnauthorized        = errors.New("unauthorized access")
+	ErrNoFXRate            = errors.New("no fx rate between the instrument and account currencies")
+	ErrTradingHalted       = errors.New("trading is halted")
+	ErrAccountFrozen       = accountService.ErrAccountFrozen
+	ErrAccountClosed       = accountService.ErrAccountClosed
 )
 
 type TradeService struct {
@@ -36,6 +51,13 @@ type TradeService struct {
 	orderRepository     *orderRepo.OrderRepository
 	accountRepository   *accountRepo.AccountRepository
 	portfolioRepository *portfolioRepo.PortfolioRepository
+	lots                *portfolioService.LotService
+	settlements         *accountService.SettlementService
+	haltRepository      *marketRepo.HaltRepository
+	calendar            *calendar.Calendar
+	rates               *fx.Cache
+	fees                *fees.Engine
+	liquidityProviderID string // Engine-only participant whose fills aren't settled
 }
 
 func NewTradeService(
@@ -43,61 +65,100 @@ func NewTradeService(
 	orderRepository *orderRepo.OrderRepository,
 	accountRepository *accountRepo.AccountRepository,
 	portfolioRepository *portfolioRepo.PortfolioRepository,
+	settlements *accountService.SettlementService,
 ) *TradeService {
 	return &TradeService{
 		tradeRepository:     tradeRepository,
 		orderRepository:     orderRepository,
 		accountRepository:   accountRepository,
 		portfolioRepository: portfolioRepository,
+		lots:                portfolioService.NewLotService(portfolioRepository),
+		settlements:         settlements,
+		haltRepository:      marketRepo.NewHaltRepository(),
+		calendar:            calendar.Default(),
+		rates:               fx.Default(),
+		fees:                fees.Default(),
 	}
 }
 
 // ExecuteTrade executes a trade for an order
 func (s *TradeService) ExecuteTrade(ctx context.Context, req *dto.ExecuteTradeRequest) (*dto.TradeResponse, error) {
+	order, trade, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tradeRepository.Create(ctx, trade); err != nil {
+		return nil, err
+	}
+
+	// 7-10. Apply the fill to the order, account and portfolio
+	if err := s.settle(ctx, order, trade); err != nil {
+		return nil, err
+	}
+
+	return s.toTradeResponse(trade), nil
+}
+
+// prepare checks that a fill can be executed against its order, account
+// and position, and builds its trade without writing anything
+func (s *TradeService) prepare(ctx context.Context, req *dto.ExecuteTradeRequest) (*orderModel.Order, *tradeModel.Trade, error) {
 	// 1. Get order
 	order, err := s.orderRepository.FindByID(ctx, req.OrderID)
 	if err != nil {
-		return nil, ErrOrderNotFound
+		return nil, nil, ErrOrderNotFound
 	}
 
 	// 2. Validate order can be executed (not already filled or cancelled)
 	if order.Status == orderModel.OrderStatusFilled ||
 		order.Status == orderModel.OrderStatusCancelled ||
 		order.Status == orderModel.OrderStatusRejected {
-		return nil, ErrOrderNotExecutable
+		return nil, nil, ErrOrderNotExecutable
 	}
 
-	// 3. Calculate trade values
-	total := req.Quantity * req.Price
-	commission := total * CommissionRate
-	var netAmount float64
+	// A halt can land after the order service accepted the order but
+	// before it reached the book, so it is refused here as it would have
+	// been at placement
+	if halt, _ := s.haltRepository.FindActiveFor(ctx, order.Symbol, order.Exchange); halt != nil {
+		return nil, nil, ErrTradingHalted
+	}
 
-	if order.Side == orderModel.OrderSideBuy {
-		netAmount = total + commission // Pay total + commission
-	} else {
-		netAmount = total - commission // Receive total - commission
+	// 3. Fix the FX rate into the account's currency and price the fees
+	account, err := s.accountRepository.FindByID(ctx, order.AccountID.Hex())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := accountService.CheckActive(account); err != nil {
+		return nil, nil, err
+	}
+	buy := order.Side == orderModel.OrderSideBuy
+	rate, err := s.rates.FillRate(order.Currency, account.Currency, buy)
+	if err != nil {
+		return nil, nil, ErrNoFXRate
 	}
 
-	// 4. Validate balance for BUY orders
-	if order.Side == orderModel.OrderSideBuy {
-		account, err := s.accountRepository.FindByID(ctx, order.AccountID.Hex())
-		if err != nil {
-			return nil, err
-		}
-		if account.Balance < netAmount {
-			return nil, ErrInsufficientBalance
-		}
+	total := req.Quantity * req.Price
+	breakdown := s.quoteFees(ctx, account, order, req.Quantity, req.Price)
+	netAmount := total - breakdown.Total // Receive total less fees
+	if buy {
+		netAmount = total + breakdown.Total // Pay total plus fees
+	}
+
+	// 4. Validate balance for BUY orders; a resting buy's own hold
+	// counts towards it
+	if buy && account.BuyingPower(time.Now())+order.ReservedFor(req.Quantity) < netAmount*rate {
+		return nil, nil, ErrInsufficientBalance
 	}
 
 	// 5. Validate shares for SELL orders
 	if order.Side == orderModel.OrderSideSell {
 		position, err := s.portfolioRepository.FindPositionByPortfolioAndSymbol(ctx, order.PortfolioID, order.Symbol)
 		if err != nil || position.Quantity < req.Quantity {
-			return nil, ErrInsufficientShares
+			return nil, nil, ErrInsufficientShares
 		}
 	}
 
-	// 6. Create trade record
+	// 6. Build the trade record
 	trade := &tradeModel.Trade{
 		OrderID:      order.ID,
 		UserID:       order.UserID,
@@ -109,57 +170,255 @@ func (s *TradeService) ExecuteTrade(ctx context.Context, req *dto.ExecuteTradeRe
 		Quantity:     req.Quantity,
 		Price:        req.Price,
 		Total:        total,
-		Commission:   commission,
+		Commission:   breakdown.Commission,
 		NetAmount:    netAmount,
 		ExecutedAt:   time.Now(),
+		Currency:     order.Currency,
+		Fees:         breakdown,
+	}
+	trade.SettleDate = s.calendar.SettlementDate(order.Exchange, trade.ExecutedAt, order.SettleDays)
+	if order.Currency != "" && order.Currency != account.Currency {
+		trade.FXRate = rate
 	}
 
-	if err := s.tradeRepository.Create(ctx, trade); err != nil {
-		return nil, err
+	return order, trade, nil
+}
+
+// settle applies a persisted trade to its order, account balance and
+// portfolio position, then notifies the user. The cash moves first, so a
+// fill that can't be paid leaves the order as it was. order is updated in
+// place.
+func (s *TradeService) settle(ctx context.Context, order *orderModel.Order, trade *tradeModel.Trade) error {
+	// 7. Post the fill to the ledger in the account's currency, which
+	// moves the account balance; its trade value settles on the trade's
+	// settlement date. A resting buy pays with the cash its order held.
+	// A trade retried by reconciliation may already have been posted.
+	posted, err := s.settlements.FillPosted(ctx, trade.ID)
+	if err != nil {
+		return err
+	}
+	fill := s.fill(order, trade)
+	if !posted {
+		if err := s.postFill(ctx, order, trade, fill); err != nil {
+			return err
+		}
 	}
 
-	// 7. Update order status
-	newFilledQty := order.FilledQty + req.Quantity
-	newAvgPrice := s.calculateAvgPrice(order.AvgFillPrice, order.FilledQty, req.Price, req.Quantity)
+	// 8. Update order status
+	newFilledQty := order.FilledQty + trade.Quantity
+	newAvgPrice := s.calculateAvgPrice(order.AvgFillPrice, order.FilledQty, trade.Price, trade.Quantity)
 
 	var newStatus orderModel.OrderStatus
-	if newFilledQty >= order.Quantity {
+	if newFilledQty+order.CancelledQty >= order.Quantity-fillTolerance {
 		newStatus = orderModel.OrderStatusFilled
 	} else {
 		newStatus = orderModel.OrderStatusPartiallyFilled
 	}
 
 	if err := s.orderRepository.UpdateFill(ctx, order.ID, newFilledQty, newAvgPrice, newStatus); err != nil {
-		return nil, err
+		return err
 	}
 
-	// 8. Update account balance
-	account, _ := s.accountRepository.FindByID(ctx, order.AccountID.Hex())
-	var newBalance float64
-	if order.Side == orderModel.OrderSideBuy {
-		newBalance = account.Balance - netAmount
-	} else {
-		newBalance = account.Balance + netAmount
-	}
-	s.accountRepository.UpdateBalance(ctx, account.ID, newBalance)
-
-	// 9. Create account transaction
-	s.accountRepository.CreateTransaction(ctx, &accountModel.Transaction{
-		AccountID:     account.ID,
-		Type:          accountModel.TransactionTypeTrade,
-		Amount:        netAmount,
-		BalanceBefore: account.Balance,
-		BalanceAfter:  newBalance,
-		Status:        accountModel.TransactionStatusCompleted,
-		Description:   string(trade.Side) + " " + trade.Symbol,
-	})
+	// 9. Add the fees to the order's totals
+	if err := s.orderRepository.AddFees(ctx, order.ID, fill.Commission, fill.RegulatoryFees); err != nil {
+		return err
+	}
 
 	// 10. Update portfolio position
 	s.updatePosition(ctx, trade, order.Side)
 
 	s.publishTradeEvents(trade, order, newFilledQty, newAvgPrice, newStatus)
 
-	return s.toTradeResponse(trade), nil
+	order.FilledQty = newFilledQty
+	order.AvgFillPrice = newAvgPrice
+	order.Status = newStatus
+	order.Commission += fill.Commission
+	order.RegulatoryFees += fill.RegulatoryFees
+	return nil
+}
+
+// fill describes a trade for booking against its account
+func (s *TradeService) fill(order *orderModel.Order, trade *tradeModel.Trade) *accountService.Fill {
+	rate := 1.0
+	if trade.FXRate > 0 {
+		rate = trade.FXRate
+	}
+
+	fill := &accountService.Fill{
+		Buy:           order.Side == orderModel.OrderSideBuy,
+		Symbol:        trade.Symbol,
+		Total:         trade.Total,
+		Commission:    trade.Commission,
+		Currency:      trade.Currency,
+		Rate:          rate,
+		SettleDate:    trade.SettleDate,
+		ReferenceType: "TRADE",
+		ReferenceID:   &trade.ID,
+		Description:   string(trade.Side) + " " + trade.Symbol,
+	}
+	if trade.Fees != nil {
+		fill.RegulatoryFees = trade.Fees.RegulatoryTotal()
+	}
+	return fill
+}
+
+// postFill pays for a fill out of its order's cash hold and books it in
+// the ledger, holding the cash again if it can't be booked
+func (s *TradeService) postFill(ctx context.Context, order *orderModel.Order, trade *tradeModel.Trade, fill *accountService.Fill) error {
+	account, err := s.accountRepository.FindByID(ctx, order.AccountID.Hex())
+	if err != nil {
+		return err
+	}
+	released, err := accountService.ReleaseOrderCash(ctx, s.orderRepository, s.accountRepository, order, order.ReservedFor(trade.Quantity))
+	if err != nil {
+		return err
+	}
+	account.ReservedCash -= released
+
+	if _, err := s.settlements.PostFill(ctx, account, fill); err != nil {
+		s.holdAgain(ctx, order, released)
+		return err
+	}
+	return nil
+}
+
+// holdAgain puts cash released for a fill that couldn't be paid back on
+// hold for its order
+func (s *TradeService) holdAgain(ctx context.Context, order *orderModel.Order, amount float64) {
+	if amount <= 0 {
+		return
+	}
+	if err := s.orderRepository.AddReservedCash(ctx, order.ID, amount); err != nil {
+		log.Printf("⚠️ Failed to hold cash for order %s again: %v", order.ID.Hex(), err)
+		return
+	}
+	if err := s.accountRepository.AddReservedCash(ctx, order.AccountID, amount); err != nil {
+		log.Printf("⚠️ Failed to hold cash for order %s again: %v", order.ID.Hex(), err)
+		return
+	}
+	order.ReservedCash += amount
+}
+
+// quoteFees prices a fill of order on its account's fee plan and 30-day
+// trading volume
+func (s *TradeService) quoteFees(ctx context.Context, account *accountModel.Account, order *orderModel.Order, quantity, price float64) *fees.Breakdown {
+	volume, err := s.accountRepository.TradingVolume(ctx, account.ID, time.Now().Add(-fees.VolumeWindow))
+	if err != nil {
+		log.Printf("⚠️ Failed to load trading volume for %s: %v", account.ID.Hex(), err)
+	}
+	return s.fees.Quote(fees.Fill{
+		Plan:           account.FeePlan,
+		InstrumentType: order.InstrumentType,
+		Buy:            order.Side == orderModel.OrderSideBuy,
+		Quantity:       quantity,
+		Price:          price,
+		Volume:         volume,
+		OrderValue:     order.FilledQty * order.AvgFillPrice,
+		OrderCharged:   order.Commission,
+	})
+}
+
+// SetLiquidityProvider marks the user the market maker quotes as. Its
+// orders exist only in the engine, so its side of a match is not settled.
+func (s *TradeService) SetLiquidityProvider(userID string) {
+	s.liquidityProviderID = userID
+}
+
+// matchFill is one side of an engine match, checked and ready to settle
+type matchFill struct {
+	order *orderModel.Order
+	trade *tradeModel.Trade
+}
+
+// HandleMatch settles an engine match by executing a trade for each side.
+// Both sides are checked before either is written, and a side that can't
+// be settled is rejected so the engine cancels it instead of filling the
+// book. Once both trades are recorded and the buy is paid for, the match
+// stands: a side that fails to apply after that is finished by
+// ReconcileOrder on recovery.
+func (s *TradeService) HandleMatch(match *matcher.Match) error {
+	ctx := context.Background()
+	sides := []struct{ orderID, userID string }{
+		{match.BuyOrderID, match.BuyerID},
+		{match.SellOrderID, match.SellerID},
+	}
+
+	var fills []matchFill
+	for _, side := range sides {
+		if s.liquidityProviderID != "" && side.userID == s.liquidityProviderID {
+			continue
+		}
+		order, trade, err := s.prepare(ctx, &dto.ExecuteTradeRequest{
+			OrderID:  side.orderID,
+			Price:    match.Price,
+			Quantity: match.Quantity,
+		})
+		if err != nil {
+			return &matcher.Rejection{OrderID: side.orderID, Err: err}
+		}
+		fills = append(fills, matchFill{order, trade})
+	}
+
+	for i, fill := range fills {
+		if err := s.tradeRepository.Create(ctx, fill.trade); err != nil {
+			s.discard(ctx, fills[:i])
+			return fmt.Errorf("record trade for order %s: %w", fill.order.ID.Hex(), err)
+		}
+	}
+
+	// Sides settle in order, buy first: paying for it is the step that can
+	// still be refused, and if it is nothing has moved yet
+	for i, fill := range fills {
+		err := s.settle(ctx, fill.order, fill.trade)
+		switch {
+		case err == nil:
+		case i == 0 && errors.Is(err, accountService.ErrInsufficientBalance):
+			s.discard(ctx, fills)
+			return &matcher.Rejection{OrderID: fill.order.ID.Hex(), Err: ErrInsufficientBalance}
+		default:
+			log.Printf("⚠️ Failed to settle order %s, left for reconciliation: %v", fill.order.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// discard deletes the trades recorded for a match that didn't happen
+func (s *TradeService) discard(ctx context.Context, fills []matchFill) {
+	for _, fill := range fills {
+		if err := s.tradeRepository.Delete(ctx, fill.trade.ID); err != nil {
+			log.Printf("⚠️ Failed to delete trade %s of a refused match: %v", fill.trade.ID.Hex(), err)
+		}
+	}
+}
+
+// ReconcileOrder settles trades that were persisted for an order but never
+// applied to it, e.g. when the process died between recording a trade and
+// updating the order. Trades are applied oldest first beyond the quantity
+// the order already reports filled. Returns how many trades were applied.
+func (s *TradeService) ReconcileOrder(ctx context.Context, order *orderModel.Order) (int, error) {
+	trades, err := s.tradeRepository.FindByOrderID(ctx, order.ID.Hex())
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].ExecutedAt.Before(trades[j].ExecutedAt)
+	})
+
+	acknowledged := 0.0
+	applied := 0
+	for i := range trades {
+		trade := &trades[i]
+		if acknowledged+trade.Quantity <= order.FilledQty+fillTolerance {
+			acknowledged += trade.Quantity
+			continue
+		}
+		if err := s.settle(ctx, order, trade); err != nil {
+			return applied, fmt.Errorf("reconcile trade %s: %w", trade.ID.Hex(), err)
+		}
+		acknowledged += trade.Quantity
+		applied++
+	}
+	return applied, nil
 }
 
 // GetTrades returns trades for a user with filtering
@@ -286,16 +545,7 @@ func (s *TradeService) updatePosition(ctx context.Context, trade *tradeModel.Tra
 			s.portfolioRepository.CreatePosition(ctx, newPosition)
 
 			// Create position lot
-			s.portfolioRepository.CreatePositionLot(ctx, &portfolioModel.PositionLot{
-				PortfolioID:  trade.PortfolioID,
-				PositionID:   newPosition.ID,
-				InstrumentID: trade.InstrumentID,
-				TradeID:      trade.ID,
-				Quantity:     trade.Quantity,
-				RemainingQty: trade.Quantity,
-				CostPerUnit:  trade.Price,
-				PurchasedAt:  trade.ExecutedAt,
-			})
+			s.openLot(ctx, trade, newPosition.ID)
 		} else {
 			// Update existing position
 			newQty := position.Quantity + trade.Quantity
@@ -309,36 +559,24 @@ func (s *TradeService) updatePosition(ctx context.Context, trade *tradeModel.Tra
 			})
 
 			// Add position lot
-			s.portfolioRepository.CreatePositionLot(ctx, &portfolioModel.PositionLot{
-				PortfolioID:  trade.PortfolioID,
-				PositionID:   position.ID,
-				InstrumentID: trade.InstrumentID,
-				TradeID:      trade.ID,
-				Quantity:     trade.Quantity,
-				RemainingQty: trade.Quantity,
-				CostPerUnit:  trade.Price,
-				PurchasedAt:  trade.ExecutedAt,
-			})
+			s.openLot(ctx, trade, position.ID)
 		}
 	} else {
 		// SELL - reduce position using FIFO
 		if position != nil {
-			remainingToSell := trade.Quantity
-			lots, _ := s.portfolioRepository.FindLotsByPositionID(ctx, position.ID)
-
-			for _, lot := range lots {
-				if remainingToSell <= 0 {
-					break
-				}
-
-				if lot.RemainingQty <= remainingToSell {
-					remainingToSell -= lot.RemainingQty
-					s.portfolioRepository.UpdatePositionLot(ctx, lot.ID, 0)
-				} else {
-					newRemaining := lot.RemainingQty - remainingToSell
-					s.portfolioRepository.UpdatePositionLot(ctx, lot.ID, newRemaining)
-					remainingToSell = 0
-				}
+			if _, err := s.lots.Close(ctx, &portfolioService.Sale{
+				PortfolioID: trade.PortfolioID,
+				PositionID:  position.ID,
+				TradeID:     trade.ID,
+				OrderID:     trade.OrderID,
+				Symbol:      trade.Symbol,
+				Currency:    trade.Currency,
+				Quantity:    trade.Quantity,
+				Price:       trade.Price,
+				Fees:        tradeFees(trade),
+				SoldAt:      trade.ExecutedAt,
+			}); err != nil {
+				log.Printf("⚠️ Failed to close lots for trade %s: %v", trade.ID.Hex(), err)
 			}
 
 			// Update position quantity
@@ -356,8 +594,36 @@ func (s *TradeService) updatePosition(ctx context.Context, trade *tradeModel.Tra
 	}
 }
 
+// openLot opens a tax lot for a buy
+func (s *TradeService) openLot(ctx context.Context, trade *tradeModel.Trade, positionID primitive.ObjectID) {
+	if err := s.lots.Open(ctx, &portfolioModel.PositionLot{
+		PortfolioID:  trade.PortfolioID,
+		PositionID:   positionID,
+		InstrumentID: trade.InstrumentID,
+		TradeID:      trade.ID,
+		OrderID:      trade.OrderID,
+		Symbol:       trade.Symbol,
+		Currency:     trade.Currency,
+		Quantity:     trade.Quantity,
+		CostPerUnit:  trade.Price,
+		Fees:         tradeFees(trade),
+		PurchasedAt:  trade.ExecutedAt,
+	}); err != nil {
+		log.Printf("⚠️ Failed to open lot for trade %s: %v", trade.ID.Hex(), err)
+	}
+}
+
+// tradeFees is everything a trade was charged: commission and
+// regulatory fees
+func tradeFees(trade *tradeModel.Trade) float64 {
+	if trade.Fees != nil {
+		return trade.Fees.Total
+	}
+	return trade.Commission
+}
+
 func (s *TradeService) toTradeResponse(trade *tradeModel.Trade) *dto.TradeResponse {
-	return &dto.TradeResponse{
+	response := &dto.TradeResponse{
 		ID:           trade.ID.Hex(),
 		OrderID:      trade.OrderID.Hex(),
 		UserID:       trade.UserID.Hex(),
@@ -372,7 +638,12 @@ func (s *TradeService) toTradeResponse(trade *tradeModel.Trade) *dto.TradeRespon
 		Commission:   trade.Commission,
 		NetAmount:    trade.NetAmount,
 		ExecutedAt:   trade.ExecutedAt.Format(time.RFC3339),
+		Fees:         trade.Fees,
+	}
+	if !trade.SettleDate.IsZero() {
+		response.SettleDate = trade.SettleDate.Format(time.RFC3339)
 	}
+	return response
 }
 
 // publishTradeEvents publishes WebSocket events for trade execution
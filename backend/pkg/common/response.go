@@ -36,6 +36,14 @@ func Error(c *fiber.Ctx, status int, message string) error {
 	})
 }
 
+// ErrorWithDetail returns a structured error body, e.g. {code, field, message}
+func ErrorWithDetail(c *fiber.Ctx, status int, detail interface{}) error {
+	return c.Status(status).JSON(Response{
+		Success: false,
+		Error:   detail,
+	})
+}
+
 // ValidationError returns validation errors (400)
 func ValidationError(c *fiber.Ctx, errors *utils.ValidationErrors) error {
 	return c.Status(fiber.StatusBadRequest).JSON(Response{
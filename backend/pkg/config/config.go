@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -42,8 +43,33 @@ type Config struct {
 	// Kafka messaging (optional)
 	KafkaBrokers string
 	KafkaGroupID string
+
+	// Synthetic liquidity for demo environments
+	MarketMakerEnabled bool
+
+	// FX conversion: rate source (static or market) and the spread
+	// charged on conversions at fill time, in basis points
+	FXProvider  string
+	FXSpreadBps float64
+
+	// Payments: provider for live deposits/withdrawals (none by default;
+	// "stub" for development), the secret its webhooks are signed with
+	// (none by default, which refuses every webhook; required in
+	// production), and the withdrawal amounts (single and per day) above
+	// which an admin must approve
+	PaymentProvider        string
+	PaymentWebhookSecret   string
+	WithdrawalReviewAmount float64
+	WithdrawalReviewDaily  float64
+
+	// Fees: JSON fee schedule replacing the built-in plans; empty keeps
+	// the built-in schedule
+	FeeScheduleFile string
 }
 
+// examplePlaceholder is the value .env.example ships for secrets.
+const examplePlaceholder = "change-this-in-production"
+
 // AppConfig is the global configuration instance.
 var AppConfig *Config
 
@@ -71,6 +97,24 @@ func LoadConfig() {
 
 		KafkaBrokers: getEnv("KAFKA_BROKERS", "localhost:9092"),
 		KafkaGroupID: getEnv("KAFKA_GROUP_ID", "bengi-investment"),
+
+		MarketMakerEnabled: getEnv("MARKET_MAKER_ENABLED", "false") == "true",
+
+		FXProvider:  getEnv("FX_PROVIDER", "static"),
+		FXSpreadBps: parseFloat(getEnv("FX_SPREAD_BPS", "25")),
+
+		PaymentProvider:        getEnv("PAYMENT_PROVIDER", ""),
+		PaymentWebhookSecret:   getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+		WithdrawalReviewAmount: parseFloat(getEnv("WITHDRAWAL_REVIEW_AMOUNT", "10000")),
+		WithdrawalReviewDaily:  parseFloat(getEnv("WITHDRAWAL_REVIEW_DAILY", "25000")),
+
+		FeeScheduleFile: getEnv("FEE_SCHEDULE_FILE", ""),
+	}
+
+	// Whoever knows the webhook secret can sign deposit callbacks, so
+	// production won't start without a secret of its own
+	if AppConfig.IsProduction() && (AppConfig.PaymentWebhookSecret == "" || AppConfig.PaymentWebhookSecret == examplePlaceholder) {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET must be set to a private value in production")
 	}
 }
 
@@ -92,6 +136,15 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// parseFloat parses a float string, defaults to 0 on error.
+func parseFloat(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
 // parseDuration parses a duration string, defaults to 24h on error.
 func parseDuration(s string) time.Duration {
 	d, err := time.ParseDuration(s)
@@ -0,0 +1,218 @@
+// Package pdf writes plain text documents as PDF 1.4. Text is set in the
+// standard Helvetica and Courier fonts every reader ships with, so no
+// font files are embedded and nothing outside the standard library is
+// needed.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+// Font is one of the standard fonts
+type Font string
+
+const (
+	Helvetica     Font = "Helvetica"
+	HelveticaBold Font = "Helvetica-Bold"
+	Courier       Font = "Courier"
+	CourierBold   Font = "Courier-Bold"
+)
+
+// fonts are the document's font resources, named F1, F2, ... in order
+var fonts = []Font{Helvetica, HelveticaBold, Courier, CourierBold}
+
+// A4 page size in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// CourierWidth is the width of text set in Courier, whose glyphs are all
+// 0.6 em wide
+func CourierWidth(text string, size float64) float64 {
+	return float64(len([]rune(text))) * size * 0.6
+}
+
+// Document is a PDF under construction. Coordinates are in points from
+// the page's top-left corner.
+type Document struct {
+	width, height float64
+	title         string
+	pages         []*bytes.Buffer
+	current       int
+}
+
+// New returns an empty document with pages of the given size
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height, current: -1}
+}
+
+// SetTitle sets the title readers show for the document
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// AddPage starts a new page and makes it current
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.current = len(d.pages) - 1
+}
+
+// SetPage makes an earlier page current, counting from 1
+func (d *Document) SetPage(n int) {
+	if n >= 1 && n <= len(d.pages) {
+		d.current = n - 1
+	}
+}
+
+// Pages returns how many pages the document has
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// Text writes text on the current page with its baseline at y
+func (d *Document) Text(x, y float64, font Font, size float64, text string) {
+	page := d.page()
+	fmt.Fprintf(page, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		fontResource(font), number(size), number(x), number(d.height-y), encode(text))
+}
+
+// Line draws a line on the current page
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	page := d.page()
+	fmt.Fprintf(page, "%s w %s %s m %s %s l S\n",
+		number(width), number(x1), number(d.height-y1), number(x2), number(d.height-y2))
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if d.current < 0 {
+		d.AddPage()
+	}
+	return d.pages[d.current]
+}
+
+// Bytes renders the document. A document without pages gets one blank
+// page, as readers reject an empty page tree.
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3 info, then the fonts, then a
+	// page and its content stream for each page
+	firstFont := 4
+	firstPage := firstFont + len(fonts)
+	objects := make([][]byte, 0, firstPage-1+2*len(d.pages))
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	objects = append(objects,
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))),
+		[]byte(fmt.Sprintf("<< /Title (%s) /Producer (bengi-investment-system) >>", encode(d.title))),
+	)
+
+	resources := make([]string, len(fonts))
+	for i, font := range fonts {
+		resources[i] = fmt.Sprintf("/F%d %d 0 R", i+1, firstFont+i)
+		objects = append(objects, []byte(fmt.Sprintf(
+			"<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font)))
+	}
+
+	for i, page := range d.pages {
+		objects = append(objects, []byte(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			number(d.width), number(d.height), strings.Join(resources, " "), firstPage+2*i+1)))
+		objects = append(objects, stream(page.Bytes()))
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n", i+1)
+		out.Write(object)
+		out.WriteString("\nendobj\n")
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// stream compresses page content into a stream object
+func stream(content []byte) []byte {
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write(content)
+	w.Close()
+
+	var object bytes.Buffer
+	fmt.Fprintf(&object, "<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+	object.Write(compressed.Bytes())
+	object.WriteString("\nendstream")
+	return object.Bytes()
+}
+
+func fontResource(font Font) string {
+	for i, f := range fonts {
+		if f == font {
+			return fmt.Sprintf("F%d", i+1)
+		}
+	}
+	return "F1"
+}
+
+// number formats a coordinate without exponent or trailing zeros
+func number(v float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", v), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// encode escapes text for a literal string in WinAnsiEncoding. Characters
+// the encoding lacks print as '?'.
+func encode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		c, ok := winAnsi(r)
+		if !ok {
+			c = '?'
+		}
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func winAnsi(r rune) (byte, bool) {
+	switch {
+	case r >= 0x20 && r <= 0x7e, r >= 0xa0 && r <= 0xff:
+		return byte(r), true
+	case r == '€':
+		return 0x80, true
+	case r == '–':
+		return 0x96, true
+	case r == '—':
+		return 0x97, true
+	case r == '•':
+		return 0x95, true
+	}
+	return 0, false
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	accountModel "github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	accountService "github.com/bricksocoolxd/bengi-investment-system/module/account/service"
 	authModel "github.com/bricksocoolxd/bengi-investment-system/module/auth/model"
 	portfolioModel "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/model"
 	"github.com/bricksocoolxd/bengi-investment-system/pkg/core/database"
@@ -66,6 +67,13 @@ func SeedTestPortfolio() {
 			log.Printf("❌ Failed to create demo account: %v", err)
 			return
 		}
+
+		// Journal the seeded balance so the account reconciles
+		opening := accountService.OpeningJournal(account.ID, account.Currency, account.Balance)
+		opening.CreatedAt = time.Now()
+		if _, err := db.Collection(accountModel.JournalCollection).InsertOne(ctx, opening); err != nil {
+			log.Printf("❌ Failed to journal demo account balance: %v", err)
+		}
 		log.Println("✅ Demo account created for test@test.com")
 	}
 
@@ -78,6 +78,10 @@ func RunSeeders() {
 		log.Printf("❌ Failed to seed roles: %v", err)
 	}
 
+	if err := SeedSystemUsers(ctx); err != nil {
+		log.Printf("❌ Failed to seed system users: %v", err)
+	}
+
 	if err := SeedInstruments(ctx); err != nil {
 		log.Printf("❌ Failed to seed instruments: %v", err)
 	}
@@ -0,0 +1,34 @@
+package seeder
+
+import (
+	"context"
+	"log"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/auth/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/auth/repository"
+)
+
+// SeedSystemUsers creates the internal users automated participants trade as.
+// They get a password that is not a valid hash, so nobody can log in as them.
+func SeedSystemUsers(ctx context.Context) error {
+	userRepo := repository.NewUserRepository()
+	roleRepo := repository.NewRoleRepository()
+
+	exists, err := userRepo.EmailExists(ctx, model.MarketMakerEmail)
+	if err != nil || exists {
+		return err
+	}
+
+	role, err := roleRepo.FindByName(ctx, model.RoleTrader)
+	if err != nil {
+		return err
+	}
+
+	user := model.NewUser(model.MarketMakerEmail, "!", "Market Maker", role.ID)
+	user.IsVerified = true
+	if err := userRepo.CreateUser(ctx, user); err != nil {
+		return err
+	}
+	log.Printf("✅ Created system user: %s", model.MarketMakerEmail)
+	return nil
+}
@@ -1,14 +1,28 @@
 package ws
 
-import "sync"
+import (
+	"log"
+	"sync"
+)
 
 type Subscriber func(msg *Message)
 
 type EventBus struct {
 	subscribers map[string]map[string]Subscriber
 	mu          sync.RWMutex
+	ordered     chan orderedMessage
+	orderedOnce sync.Once
 }
 
+// orderedMessage is a publish waiting in the ordered queue
+type orderedMessage struct {
+	topic string
+	msg   *Message
+}
+
+// orderedQueueSize buffers ordered publishes so publishers rarely block
+const orderedQueueSize = 1024
+
 var Bus *EventBus
 
 func InitBus() {
@@ -65,6 +79,43 @@ func (eb *EventBus) Publish(topic string, msg *Message) {
 	}
 }
 
+// PublishOrdered delivers messages to subscribers in publish order, for
+// sequenced feeds such as book updates. Publish fans out on separate
+// goroutines and can reorder messages.
+func (eb *EventBus) PublishOrdered(topic string, msg *Message) {
+	eb.orderedOnce.Do(func() {
+		eb.ordered = make(chan orderedMessage, orderedQueueSize)
+		go eb.dispatchOrdered()
+	})
+	eb.ordered <- orderedMessage{topic: topic, msg: msg}
+}
+
+// dispatchOrdered delivers ordered publishes one at a time
+func (eb *EventBus) dispatchOrdered() {
+	for item := range eb.ordered {
+		eb.mu.RLock()
+		subs := make([]Subscriber, 0, len(eb.subscribers[item.topic]))
+		for _, callback := range eb.subscribers[item.topic] {
+			subs = append(subs, callback)
+		}
+		eb.mu.RUnlock()
+
+		for _, callback := range subs {
+			deliver(callback, item.msg)
+		}
+	}
+}
+
+// deliver runs a subscriber, surviving sends to a closed client
+func deliver(callback Subscriber, msg *Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[WS] Dropped ordered message: %v", r)
+		}
+	}()
+	callback(msg)
+}
+
 func (eb *EventBus) PublishBytes(topic string, data []byte) {
 	msg, err := ParseMessage(data)
 	if err != nil {
@@ -113,6 +164,34 @@ func PublishOrderUpdate(userID string, payload *OrderPayload) {
 	Bus.Publish(topic, msg)
 }
 
+// PublishStatus publishes a trading status change for a symbol or exchange
+func PublishStatus(payload *StatusPayload) {
+	topic := TopicStatus(payload.Target)
+	msg := NewMessage(TypeStatusUpdate, topic, payload)
+	Bus.Publish(topic, msg)
+}
+
+// PublishBookUpdate publishes an incremental order book update in sequence order
+func PublishBookUpdate(payload *BookPayload) {
+	topic := TopicBook(payload.Symbol)
+	if !Bus.HasSubscribers(topic) {
+		return
+	}
+	msg := NewMessage(TypeBookUpdate, topic, payload)
+	Bus.PublishOrdered(topic, msg)
+}
+
+// PublishTradePrint publishes an execution to the symbol's public tape in
+// execution order
+func PublishTradePrint(payload *PrintPayload) {
+	topic := TopicTrades(payload.Symbol)
+	if !Bus.HasSubscribers(topic) {
+		return
+	}
+	msg := NewMessage(TypeTradePrint, topic, payload)
+	Bus.PublishOrdered(topic, msg)
+}
+
 // PublishTradeUpdate publishes a trade update to user
 func PublishTradeUpdate(userID string, payload *TradePayload) {
 	topic := TopicTrade(userID)
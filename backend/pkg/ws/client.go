@@ -125,6 +125,12 @@ func (c *Client) Subscribe(topic string) {
 
 	// Confirm subscription
 	c.Send <- NewMessage(TypeSubscribed, topic, nil).ToBytes()
+
+	// Sequenced feeds start with a snapshot. Updates can overtake it, so
+	// clients buffer until the snapshot and drop those at or below its sequence
+	if snapshot := snapshotFor(topic); snapshot != nil {
+		c.Send <- snapshot.ToBytes()
+	}
 	log.Printf("[WS] Client %s subscribed to %s", c.ID, topic)
 }
 
@@ -15,6 +15,11 @@ const (
 	TypePriceUpdate  = "PRICE_UPDATE"
 	TypeOrderUpdate  = "ORDER_UPDATE"
 	TypeTradeUpdate  = "TRADE_UPDATE"
+	TypeStatusUpdate = "STATUS_UPDATE"
+	TypeBookSnapshot = "BOOK_SNAPSHOT"
+	TypeBookUpdate   = "BOOK_UPDATE"
+	TypeTapeSnapshot = "TAPE_SNAPSHOT"
+	TypeTradePrint   = "TRADE_PRINT"
 	TypeError        = "ERROR"
 )
 
@@ -45,6 +50,9 @@ type OrderPayload struct {
 	Status    string  `json:"status"`
 	FilledQty float64 `json:"filledQty"`
 	AvgPrice  float64 `json:"avgPrice,omitempty"`
+	// CancelledQty and Reason are set when the engine cancels quantity itself
+	CancelledQty float64 `json:"cancelledQty,omitempty"`
+	Reason       string  `json:"reason,omitempty"`
 }
 
 // TradePayload for trade updates
@@ -58,6 +66,48 @@ type TradePayload struct {
 	Commission float64 `json:"commission"`
 }
 
+// StatusPayload for trading halt/resume updates
+type StatusPayload struct {
+	Scope  string `json:"scope"`  // SYMBOL or EXCHANGE
+	Target string `json:"target"` // Symbol or exchange code
+	Status string `json:"status"` // HALTED or TRADING
+	Reason string `json:"reason,omitempty"`
+}
+
+// BookLevel is an aggregated order book price level
+type BookLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"` // 0 in an update removes the level
+	Orders   int     `json:"orders"`
+}
+
+// BookPayload for order book snapshots and incremental updates.
+// Updates follow the snapshot's sequence with no gaps; a gap means the
+// client missed an update and should resubscribe for a new snapshot.
+type BookPayload struct {
+	Symbol   string      `json:"symbol"`
+	Sequence uint64      `json:"sequence"`
+	Bids     []BookLevel `json:"bids"`
+	Asks     []BookLevel `json:"asks"`
+}
+
+// PrintPayload is an anonymized execution on the public tape
+type PrintPayload struct {
+	ID            string  `json:"id"`
+	Symbol        string  `json:"symbol"`
+	Price         float64 `json:"price"`
+	Quantity      float64 `json:"quantity"`
+	AggressorSide string  `json:"aggressorSide,omitempty"` // BUY or SELL; empty for auction uncrosses
+	Timestamp     int64   `json:"timestamp"`               // Execution time, Unix ms
+}
+
+// TapePayload carries the most recent prints, newest first, sent to new
+// trades:SYMBOL subscribers
+type TapePayload struct {
+	Symbol string         `json:"symbol"`
+	Prints []PrintPayload `json:"prints"`
+}
+
 // ErrorPayload for error messages
 type ErrorPayload struct {
 	Code    string `json:"code"`
@@ -0,0 +1,35 @@
+package ws
+
+import (
+	"strings"
+	"sync"
+)
+
+// SnapshotFunc returns the message sent to a client right after it
+// subscribes to topic, or nil for none
+type SnapshotFunc func(topic string) *Message
+
+var (
+	snapshotProviders = make(map[string]SnapshotFunc)
+	snapshotMu        sync.RWMutex
+)
+
+// RegisterSnapshot sets the snapshot provider for topics with prefix
+func RegisterSnapshot(prefix string, fn SnapshotFunc) {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+	snapshotProviders[prefix] = fn
+}
+
+// snapshotFor builds the initial message for a new subscription
+func snapshotFor(topic string) *Message {
+	snapshotMu.RLock()
+	defer snapshotMu.RUnlock()
+
+	for prefix, fn := range snapshotProviders {
+		if strings.HasPrefix(topic, prefix) {
+			return fn(topic)
+		}
+	}
+	return nil
+}
@@ -8,6 +8,9 @@ const (
 	TopicOrderPrefix     = "order:"     // order:userId
 	TopicTradePrefix     = "trade:"     // trade:userId
 	TopicPortfolioPrefix = "portfolio:" // portfolio:userId
+	TopicStatusPrefix    = "status:"    // status:AAPL, status:NASDAQ
+	TopicBookPrefix      = "book:"      // book:AAPL
+	TopicTradesPrefix    = "trades:"    // trades:AAPL (public tape)
 )
 
 // Topic constructors
@@ -27,6 +30,18 @@ func TopicPortfolio(userID string) string {
 	return TopicPortfolioPrefix + userID
 }
 
+func TopicStatus(target string) string {
+	return TopicStatusPrefix + target
+}
+
+func TopicBook(symbol string) string {
+	return TopicBookPrefix + symbol
+}
+
+func TopicTrades(symbol string) string {
+	return TopicTradesPrefix + symbol
+}
+
 // ValidateTopic checks if topic is valid
 func ValidateTopic(topic string) bool {
 	if len(topic) < 3 {
@@ -38,6 +53,9 @@ func ValidateTopic(topic string) bool {
 		TopicOrderPrefix,
 		TopicTradePrefix,
 		TopicPortfolioPrefix,
+		TopicStatusPrefix,
+		TopicBookPrefix,
+		TopicTradesPrefix,
 	}
 	for _, prefix := range prefixes {
 		if len(topic) > len(prefix) && topic[:len(prefix)] == prefix {
@@ -49,10 +67,7 @@ func ValidateTopic(topic string) bool {
 
 // IsUserTopic checks if topic is user-specific (requires auth)
 func IsUserTopic(topic string) bool {
-	return len(topic) > len(TopicOrderPrefix) &&
-		(topic[:len(TopicOrderPrefix)] == TopicOrderPrefix ||
-			topic[:len(TopicTradePrefix)] == TopicTradePrefix ||
-			topic[:len(TopicPortfolioPrefix)] == TopicPortfolioPrefix)
+	return GetUserFromTopic(topic) != ""
 }
 
 // GetUserFromTopic extracts userID from user topic
@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
+)
+
+func newYork(t *testing.T) *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	return loc
+}
+
+func TestCalendar_USEquitySessions(t *testing.T) {
+	ny := newYork(t)
+	nasdaq, ok := calendar.Default().Exchange("NASDAQ")
+	if !ok {
+		t.Fatal("Expected NASDAQ to be registered")
+	}
+
+	cases := []struct {
+		name string
+		at   time.Time
+		want calendar.Phase
+	}{
+		{"pre-market", time.Date(2026, 3, 10, 8, 0, 0, 0, ny), calendar.PhasePreMarket},
+		{"regular open", time.Date(2026, 3, 10, 9, 30, 0, 0, ny), calendar.PhaseRegular},
+		{"after-hours", time.Date(2026, 3, 10, 16, 0, 0, 0, ny), calendar.PhaseAfterHours},
+		{"overnight", time.Date(2026, 3, 10, 21, 0, 0, 0, ny), calendar.PhaseClosed},
+		{"weekend", time.Date(2026, 3, 14, 12, 0, 0, 0, ny), calendar.PhaseClosed},
+		{"holiday", time.Date(2026, 12, 25, 12, 0, 0, 0, ny), calendar.PhaseClosed},
+		{"early close", time.Date(2026, 11, 27, 13, 30, 0, 0, ny), calendar.PhaseAfterHours},
+	}
+
+	for _, tc := range cases {
+		if got := nasdaq.PhaseAt(tc.at); got != tc.want {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCalendar_TimeZoneConversion(t *testing.T) {
+	newYork(t)
+
+	// 14:00 UTC on a March weekday is 10:00 EDT
+	status, err := calendar.Default().Status("NYSE", time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !status.IsOpen() {
+		t.Errorf("Expected NYSE to be open, got %s", status.Phase)
+	}
+}
+
+func TestCalendar_NextOpenSkipsHolidayWeekend(t *testing.T) {
+	ny := newYork(t)
+	nyse, _ := calendar.Default().Exchange("NYSE")
+
+	// Good Friday 2026, the next open is Monday April 6
+	next := nyse.NextOpen(time.Date(2026, 4, 3, 12, 0, 0, 0, ny))
+	want := time.Date(2026, 4, 6, 9, 30, 0, 0, ny)
+	if !next.Equal(want) {
+		t.Errorf("Expected next open %v, got %v", want, next)
+	}
+}
+
+func TestCalendar_NextTransition(t *testing.T) {
+	ny := newYork(t)
+	nyse, _ := calendar.Default().Exchange("NYSE")
+
+	cases := []struct {
+		name     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"pre-market to open", time.Date(2026, 3, 10, 8, 0, 0, 0, ny), time.Date(2026, 3, 10, 9, 30, 0, 0, ny)},
+		{"at the open", time.Date(2026, 3, 10, 9, 30, 0, 0, ny), time.Date(2026, 3, 10, 16, 0, 0, 0, ny)},
+		{"early close", time.Date(2026, 11, 27, 12, 0, 0, 0, ny), time.Date(2026, 11, 27, 13, 0, 0, 0, ny)},
+		{"over the weekend", time.Date(2026, 3, 13, 21, 0, 0, 0, ny), time.Date(2026, 3, 16, 4, 0, 0, 0, ny)},
+	}
+	for _, tc := range cases {
+		if got := nyse.NextTransition(tc.from); !got.Equal(tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestCalendar_CryptoAlwaysOpen(t *testing.T) {
+	status, err := calendar.Default().Status("Crypto", time.Date(2026, 12, 25, 3, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !status.IsOpen() {
+		t.Errorf("Expected crypto to be open, got %s", status.Phase)
+	}
+}
+
+func TestCalendar_UnknownExchange(t *testing.T) {
+	if _, err := calendar.Default().Status("MOON", time.Now()); err != calendar.ErrUnknownExchange {
+		t.Errorf("Expected ErrUnknownExchange, got %v", err)
+	}
+}
+
+func TestCalendar_CheckEligibility(t *testing.T) {
+	cases := []struct {
+		phase         calendar.Phase
+		orderType     string
+		extendedHours bool
+		want          error
+	}{
+		{calendar.PhaseRegular, "MARKET", false, nil},
+		{calendar.PhasePreMarket, "LIMIT", false, calendar.ErrExtendedHoursRequired},
+		{calendar.PhasePreMarket, "LIMIT", true, nil},
+		{calendar.PhaseAfterHours, "MARKET", true, calendar.ErrExtendedHoursLimitOnly},
+		{calendar.PhaseClosed, "LIMIT", true, calendar.ErrSessionClosed},
+	}
+
+	for _, tc := range cases {
+		if got := calendar.CheckEligibility(tc.phase, tc.orderType, tc.extendedHours); got != tc.want {
+			t.Errorf("%s %s extendedHours=%v: expected %v, got %v", tc.phase, tc.orderType, tc.extendedHours, tc.want, got)
+		}
+	}
+}
+
+func TestCalendar_Canonical(t *testing.T) {
+	cases := map[string]string{
+		"nasdaq":   calendar.ExchangeNASDAQ,
+		"ARCA":     calendar.ExchangeNYSE,
+		"coinbase": calendar.ExchangeCrypto,
+		" lse ":    "LSE",
+	}
+
+	for code, want := range cases {
+		if got := calendar.Default().Canonical(code); got != want {
+			t.Errorf("%q: expected %s, got %s", code, want, got)
+		}
+	}
+}
+
+func TestCalendar_USHolidaysFromRules(t *testing.T) {
+	ny := newYork(t)
+	nyse, _ := calendar.Default().Exchange("NYSE")
+
+	holidays := map[string]string{
+		// Published NYSE closures
+		"2025-01-09": "National Day of Mourning",
+		"2025-04-18": "Good Friday",
+		"2025-05-26": "Memorial Day",
+		"2026-07-03": "Independence Day (observed)",
+		"2026-11-26": "Thanksgiving Day",
+		"2027-03-26": "Good Friday",
+		"2027-06-18": "Juneteenth (observed)",
+		"2027-12-24": "Christmas Day (observed)",
+		// Years no table covered
+		"2028-01-17": "Martin Luther King Jr. Day",
+		"2028-04-14": "Good Friday",
+		"2028-05-29": "Memorial Day",
+		"2030-01-01": "New Year's Day",
+		"2033-12-26": "Christmas Day (observed)",
+	}
+	for date, name := range holidays {
+		day, _ := time.ParseInLocation("2006-01-02", date, ny)
+		if got := nyse.HolidayAt(day.Add(12 * time.Hour)); got != name {
+			t.Errorf("%s: expected %q, got %q", date, name, got)
+		}
+	}
+
+	// A Saturday New Year's Day isn't observed on the Friday before
+	if got := nyse.HolidayAt(time.Date(2027, 12, 31, 12, 0, 0, 0, ny)); got != "" {
+		t.Errorf("Expected no closure on 2027-12-31, got %q", got)
+	}
+	// Juneteenth only closes markets from 2022
+	if got := nyse.HolidayAt(time.Date(2021, 6, 18, 12, 0, 0, 0, ny)); got != "" {
+		t.Errorf("Expected no Juneteenth closure in 2021, got %q", got)
+	}
+
+	earlyCloses := map[string]bool{
+		"2025-07-03": true,
+		"2025-11-28": true,
+		"2025-12-24": true,
+		"2026-07-02": false, // July 4 is a Saturday
+		"2027-12-23": false, // Christmas is observed on the 24th
+		"2028-07-03": true,
+		"2028-11-24": true,
+	}
+	for date, want := range earlyCloses {
+		day, _ := time.ParseInLocation("2006-01-02", date, ny)
+		if got := nyse.IsEarlyClose(day.Add(12 * time.Hour)); got != want {
+			t.Errorf("%s: expected early close %v, got %v", date, want, got)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+)
+
+func TestEquilibrium_MaximisesVolume(t *testing.T) {
+	buys := []*matcher.Order{
+		{ID: "b1", Side: "BUY", Type: "LIMIT", Price: 101, Quantity: 10},
+		{ID: "b2", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 5},
+	}
+	sells := []*matcher.Order{
+		{ID: "s1", Side: "SELL", Type: "LIMIT", Price: 99, Quantity: 8},
+		{ID: "s2", Side: "SELL", Type: "LIMIT", Price: 100.5, Quantity: 10},
+	}
+
+	// 101 and 100.5 both execute 10 with the same imbalance; 100.5 is
+	// closer to the reference
+	price, volume := matcher.Equilibrium(buys, sells, 100)
+	if price != 100.5 || volume != 10 {
+		t.Errorf("Expected 10 @ 100.5, got %f @ %f", volume, price)
+	}
+
+	if _, volume := matcher.Equilibrium(buys[:1], []*matcher.Order{{Side: "SELL", Type: "LIMIT", Price: 105, Quantity: 1}}, 100); volume != 0 {
+		t.Errorf("Expected no volume for an uncrossed book, got %f", volume)
+	}
+}
+
+func TestCircuitBreaker_Breaches(t *testing.T) {
+	now := time.Now()
+	cb := matcher.NewCircuitBreaker(matcher.CircuitBreakerConfig{ThresholdPct: 5, Window: time.Minute, Duration: time.Minute})
+	cb.Record(100, now.Add(-2*time.Minute)) // Outside the window
+	cb.Record(102, now)
+
+	if cb.Breaches(106, now) {
+		t.Error("Expected 106 to be within 5% of 102")
+	}
+	if !cb.Breaches(108, now) {
+		t.Error("Expected 108 to breach 5% of 102")
+	}
+
+	cb.Trip(now)
+	if cb.Phase() != matcher.BookPhaseAuction || cb.Trips() != 1 {
+		t.Errorf("Expected AUCTION after trip, got %s", cb.Phase())
+	}
+	if !cb.Expired(now.Add(time.Minute)) {
+		t.Error("Expected auction to expire after its duration")
+	}
+}
+
+func TestMatchEngine_CircuitBreakerAuction(t *testing.T) {
+	var mu sync.Mutex
+	matches := make([]*matcher.Match, 0)
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		mu.Lock()
+		defer mu.Unlock()
+		matches = append(matches, match)
+		return nil
+	})
+	engine.SetCircuitBreaker("XYZ", matcher.CircuitBreakerConfig{
+		ThresholdPct: 5,
+		Window:       time.Minute,
+		Duration:     400 * time.Millisecond,
+		Action:       matcher.BreakerActionAuction,
+	})
+	engine.SetReferencePrice("XYZ", 100)
+
+	engine.Start()
+	defer engine.Stop()
+
+	now := time.Now().UnixMilli()
+	engine.AddOrder(&matcher.Order{ID: "buy-1", UserID: "buyer-1", Symbol: "XYZ", Side: "BUY", Type: "LIMIT", Price: 120, Quantity: 10, Timestamp: now})
+	engine.AddOrder(&matcher.Order{ID: "sell-1", UserID: "seller-1", Symbol: "XYZ", Side: "SELL", Type: "LIMIT", Price: 118, Quantity: 10, Timestamp: now + 1})
+
+	time.Sleep(200 * time.Millisecond)
+
+	stats := engine.GetOrderBookStats("XYZ")
+	if stats["phase"] != string(matcher.BookPhaseAuction) {
+		t.Fatalf("Expected AUCTION, got %v", stats["phase"])
+	}
+	if stats["indicativePrice"] != 118.0 {
+		t.Errorf("Expected indicative price 118, got %v", stats["indicativePrice"])
+	}
+	mu.Lock()
+	if len(matches) != 0 {
+		t.Errorf("Expected no matches during the auction, got %d", len(matches))
+	}
+	mu.Unlock()
+
+	time.Sleep(500 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(matches) != 1 || matches[0].Price != 118 || matches[0].Quantity != 10 {
+		t.Fatalf("Expected one uncross of 10 @ 118, got %+v", matches)
+	}
+	if phase := engine.GetOrderBookStats("XYZ")["phase"]; phase != string(matcher.BookPhaseContinuous) {
+		t.Errorf("Expected CONTINUOUS after uncross, got %v", phase)
+	}
+}
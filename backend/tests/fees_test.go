@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/fees"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newFeeEngine(t *testing.T) *fees.Engine {
+	t.Helper()
+	engine, err := fees.NewEngine(fees.Builtin())
+	if err != nil {
+		t.Fatalf("Failed to load the built-in schedule: %v", err)
+	}
+	return engine
+}
+
+func TestFees_VolumeTiers(t *testing.T) {
+	schedule := fees.Schedule{
+		Rate:  0.001,
+		Tiers: []fees.Tier{{MinVolume: 1000000, Rate: 0.0005}, {MinVolume: 100000, Rate: 0.0008}},
+	}
+
+	cases := []struct {
+		volume float64
+		rate   float64
+		tier   int
+	}{
+		{0, 0.001, 0},
+		{99999, 0.001, 0},
+		{100000, 0.0008, 2},
+		{5000000, 0.0005, 1},
+	}
+	for _, tc := range cases {
+		rate, tier := schedule.RateAt(tc.volume)
+		if rate != tc.rate || tier != tc.tier {
+			t.Errorf("Volume %v: expected rate %v tier %d, got %v tier %d", tc.volume, tc.rate, tc.tier, rate, tier)
+		}
+	}
+}
+
+func TestFees_StandardCommission(t *testing.T) {
+	engine := newFeeEngine(t)
+
+	quote := engine.Quote(fees.Fill{InstrumentType: "Stock", Buy: true, Quantity: 10, Price: 150})
+	if quote.Plan != fees.DefaultPlan || quote.Commission != 1.5 || quote.Total != 1.5 {
+		t.Errorf("Expected 0.1%% standard commission of 1.50, got %+v", quote)
+	}
+	if len(quote.Regulatory) != 0 {
+		t.Errorf("Expected no regulatory fees on a buy, got %+v", quote.Regulatory)
+	}
+
+	tiered := engine.Quote(fees.Fill{InstrumentType: "Stock", Buy: true, Quantity: 10, Price: 150, Volume: 250000})
+	if tiered.Tier != 1 || tiered.Commission != 1.2 {
+		t.Errorf("Expected the first volume tier at 0.08%%, got %+v", tiered)
+	}
+
+	crypto := engine.Quote(fees.Fill{InstrumentType: "crypto", Buy: true, Quantity: 1, Price: 1000})
+	if crypto.Commission != 1.5 {
+		t.Errorf("Expected the crypto schedule to match case-insensitively, got %+v", crypto)
+	}
+
+	unknown := engine.Quote(fees.Fill{Plan: "GOLD", InstrumentType: "Stock", Buy: true, Quantity: 10, Price: 150})
+	if unknown.Plan != fees.DefaultPlan || unknown.Commission != 1.5 {
+		t.Errorf("Expected an unknown plan to fall back to %s, got %+v", fees.DefaultPlan, unknown)
+	}
+}
+
+func TestFees_MinMaxAcrossPartialFills(t *testing.T) {
+	engine := newFeeEngine(t)
+
+	// PRO charges 0.05% with a 1.00 minimum per order: the first small
+	// fill pays the minimum, the next pays nothing until it is exceeded
+	first := engine.Quote(fees.Fill{Plan: "pro", InstrumentType: "Stock", Buy: true, Quantity: 10, Price: 10})
+	if first.Commission != 1 {
+		t.Fatalf("Expected the 1.00 minimum, got %v", first.Commission)
+	}
+	second := engine.Quote(fees.Fill{Plan: "PRO", InstrumentType: "Stock", Buy: true, Quantity: 10, Price: 10,
+		OrderValue: 100, OrderCharged: first.Commission})
+	if second.Commission != 0 {
+		t.Errorf("Expected the minimum to cover the second fill, got %v", second.Commission)
+	}
+	third := engine.Quote(fees.Fill{Plan: "PRO", InstrumentType: "Stock", Buy: true, Quantity: 3000, Price: 10,
+		OrderValue: 200, OrderCharged: 1})
+	if math.Abs(third.Commission-(30200*0.0005-1)) > 1e-9 {
+		t.Errorf("Expected the order's commission less what was charged, got %v", third.Commission)
+	}
+
+	// And a 50.00 maximum: once reached, later fills are free
+	big := engine.Quote(fees.Fill{Plan: "PRO", InstrumentType: "Stock", Buy: true, Quantity: 1000, Price: 200})
+	if big.Commission != 50 {
+		t.Errorf("Expected the 50.00 maximum, got %v", big.Commission)
+	}
+	after := engine.Quote(fees.Fill{Plan: "PRO", InstrumentType: "Stock", Buy: true, Quantity: 1000, Price: 200,
+		OrderValue: 200000, OrderCharged: 50})
+	if after.Commission != 0 {
+		t.Errorf("Expected nothing after the maximum, got %v", after.Commission)
+	}
+}
+
+func TestFees_Regulatory(t *testing.T) {
+	engine := newFeeEngine(t)
+
+	sell := engine.Quote(fees.Fill{InstrumentType: "Stock", Quantity: 1000, Price: 100})
+	charges := map[string]float64{}
+	for _, charge := range sell.Regulatory {
+		charges[charge.Name] = charge.Amount
+	}
+	if charges["SEC"] != 2.78 || charges["FINRA TAF"] != 0.17 {
+		t.Errorf("Expected SEC 2.78 and FINRA TAF 0.17, got %+v", sell.Regulatory)
+	}
+	if sell.Total != roundTo(100+2.78+0.17) {
+		t.Errorf("Expected commission plus regulatory fees, got %v", sell.Total)
+	}
+
+	capped := engine.Quote(fees.Fill{InstrumentType: "ETF", Quantity: 100000, Price: 1})
+	for _, charge := range capped.Regulatory {
+		if charge.Name == "FINRA TAF" && charge.Amount != 8.30 {
+			t.Errorf("Expected FINRA TAF capped at 8.30, got %v", charge.Amount)
+		}
+	}
+
+	crypto := engine.Quote(fees.Fill{InstrumentType: "Crypto", Quantity: 10, Price: 1000})
+	if len(crypto.Regulatory) != 0 {
+		t.Errorf("Expected no equity fees on crypto, got %+v", crypto.Regulatory)
+	}
+}
+
+func TestFees_Load(t *testing.T) {
+	engine := newFeeEngine(t)
+
+	err := engine.Load(fees.Config{Plans: []fees.Plan{{Name: "PRO"}}})
+	if !errors.Is(err, fees.ErrNoDefaultPlan) {
+		t.Errorf("Expected ErrNoDefaultPlan, got %v", err)
+	}
+	if !engine.HasPlan("PRO") || !engine.HasPlan("standard") {
+		t.Error("Expected a rejected schedule to keep the current one")
+	}
+
+	flat := fees.Config{Plans: []fees.Plan{{
+		Name:      "standard",
+		Schedules: map[string]fees.Schedule{"": {Rate: 0.002}},
+	}}}
+	if err := engine.Load(flat); err != nil {
+		t.Fatalf("Failed to load schedule: %v", err)
+	}
+	if engine.HasPlan("PRO") {
+		t.Error("Expected loading to replace the plans")
+	}
+	if quote := engine.Quote(fees.Fill{Plan: "PRO", InstrumentType: "Stock", Quantity: 10, Price: 100}); quote.Commission != 2 || len(quote.Regulatory) != 0 {
+		t.Errorf("Expected the loaded schedule to price fills, got %+v", quote)
+	}
+}
+
+func TestFees_Journal(t *testing.T) {
+	accountID := primitive.NewObjectID()
+
+	entry := service.FeeJournal(accountID, "USD", 1.5, 0.25, "Fees: SELL AAPL")
+	if !entry.Balanced() {
+		t.Fatalf("Expected a balanced fee entry, got %+v", entry.Lines)
+	}
+	if entry.Type != model.JournalTypeFee {
+		t.Errorf("Expected a FEE entry, got %s", entry.Type)
+	}
+	if delta := entry.CashDelta(accountID); math.Abs(delta+1.75) > 1e-9 {
+		t.Errorf("Expected cash to fall by 1.75, got %v", delta)
+	}
+
+	commissionOnly := service.FeeJournal(accountID, "USD", 1.5, 0, "Fees: BUY AAPL")
+	if len(commissionOnly.Lines) != 2 || !commissionOnly.Balanced() {
+		t.Errorf("Expected no regulatory line without regulatory fees, got %+v", commissionOnly.Lines)
+	}
+}
+
+func roundTo(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// countingProvider quotes a fixed rate and counts how often it's asked
+type countingProvider struct {
+	mu    sync.Mutex
+	rate  float64
+	calls int
+}
+
+func (p *countingProvider) Rate(base, quote string) (float64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return p.rate, nil
+}
+
+func TestFXCache_ReusesRatesWithinTTL(t *testing.T) {
+	source := &countingProvider{rate: 35}
+	cache := fx.NewCache(source, time.Hour)
+
+	var fetched []string
+	cache.OnFetch(func(base, quote string, rate float64, at time.Time) {
+		fetched = append(fetched, base+"/"+quote)
+	})
+
+	for i := 0; i < 3; i++ {
+		if rate, err := cache.Rate("usd", "thb"); err != nil || rate != 35 {
+			t.Fatalf("Expected 35, got %v (%v)", rate, err)
+		}
+	}
+	if source.calls != 1 {
+		t.Errorf("Expected one fetch within the TTL, got %d", source.calls)
+	}
+	if len(fetched) != 1 || fetched[0] != "USD/THB" {
+		t.Errorf("Expected the fetch to be reported once as USD/THB, got %v", fetched)
+	}
+
+	// A new source drops the cache
+	cache.SetSource(&countingProvider{rate: 36})
+	if rate, _ := cache.Rate("USD", "THB"); rate != 36 {
+		t.Errorf("Expected the new source's rate 36, got %v", rate)
+	}
+}
+
+func TestFXCache_ExpiredRatesAreRefetched(t *testing.T) {
+	source := &countingProvider{rate: 35}
+	cache := fx.NewCache(source, time.Nanosecond)
+
+	cache.Rate("USD", "THB")
+	time.Sleep(time.Millisecond)
+	cache.Rate("USD", "THB")
+
+	if source.calls != 2 {
+		t.Errorf("Expected a refetch after the TTL, got %d fetches", source.calls)
+	}
+}
+
+func TestFXCache_FillRateChargesSpread(t *testing.T) {
+	cache := fx.NewCache(fx.NewStaticProvider(map[string]float64{"THB": 35}), time.Hour)
+	cache.SetSpread(100)
+
+	buy, err := cache.FillRate("USD", "THB", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sell, _ := cache.FillRate("USD", "THB", false)
+	if math.Abs(buy-35.35) > 1e-9 || math.Abs(sell-34.65) > 1e-9 {
+		t.Errorf("Expected 35.35 buy / 34.65 sell, got %v / %v", buy, sell)
+	}
+
+	// Same or unknown instrument currency is not converted
+	if rate, _ := cache.FillRate("THB", "THB", true); rate != 1 {
+		t.Errorf("Expected 1 for the same currency, got %v", rate)
+	}
+	if rate, _ := cache.FillRate("", "THB", true); rate != 1 {
+		t.Errorf("Expected 1 for an unknown instrument currency, got %v", rate)
+	}
+}
+
+func TestFillJournals_SameCurrency(t *testing.T) {
+	accountID := primitive.NewObjectID()
+	entries, cash := service.FillJournals(accountID, true, 1000, 1, "USD", "USD", 1, "BUY AAPL")
+
+	if cash != -1001 {
+		t.Errorf("Expected cash -1001, got %v", cash)
+	}
+	for _, entry := range entries {
+		if !entry.Balanced() {
+			t.Errorf("Expected a balanced %s entry, got %+v", entry.Type, entry.Lines)
+		}
+		for _, line := range entry.Lines {
+			if line.Ledger == model.LedgerFXConversion {
+				t.Errorf("Expected no FX lines without conversion, got %+v", line)
+			}
+		}
+	}
+}
+
+func TestFillJournals_ConvertsIntoAccountCurrency(t *testing.T) {
+	accountID := primitive.NewObjectID()
+
+	cases := []struct {
+		name string
+		buy  bool
+		rate float64
+		want float64
+	}{
+		{"buy", true, 35.35, -35385.35},  // (1000 + 1) * 35.35
+		{"sell", false, 34.65, 34615.35}, // (1000 - 1) * 34.65
+	}
+	for _, tc := range cases {
+		entries, cash := service.FillJournals(accountID, tc.buy, 1000, 1, "USD", "THB", tc.rate, "")
+		if math.Abs(cash-tc.want) > 1e-9 {
+			t.Errorf("%s: expected cash %v THB, got %v", tc.name, tc.want, cash)
+		}
+
+		delta := 0.0
+		pending := make(map[string]float64)
+		for _, entry := range entries {
+			if !entry.Balanced() {
+				t.Errorf("%s: expected a balanced %s entry, got %+v", tc.name, entry.Type, entry.Lines)
+			}
+			delta += entry.CashDelta(accountID)
+			for _, line := range entry.Lines {
+				if line.Ledger == model.LedgerUserCash && line.Currency != "THB" {
+					t.Errorf("%s: expected user cash in THB, got %s", tc.name, line.Currency)
+				}
+				if line.Ledger == model.LedgerPendingSettlement {
+					pending[line.Currency] += line.Credit - line.Debit
+				}
+			}
+		}
+		if math.Abs(delta-tc.want) > 1e-9 {
+			t.Errorf("%s: expected the journals to move cash by %v, got %v", tc.name, tc.want, delta)
+		}
+		if math.Abs(pending["USD"]) > 1e-9 || len(pending) != 1 {
+			t.Errorf("%s: expected USD pending settlement to clear, got %v", tc.name, pending)
+		}
+	}
+}
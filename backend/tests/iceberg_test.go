@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+)
+
+func TestOrderBook_IcebergShowsSlice(t *testing.T) {
+	book := matcher.NewOrderBook("AAPL")
+	book.AddOrder(&matcher.Order{ID: "ice", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 50, DisplayQuantity: 10, Timestamp: 1})
+	book.AddOrder(&matcher.Order{ID: "plain", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: 2})
+
+	_, asks := book.GetLevels(0)
+	if len(asks) != 1 || asks[0].Quantity != 15 || asks[0].Orders != 2 {
+		t.Fatalf("Expected 15 shown across 2 orders, got %+v", asks)
+	}
+
+	update := book.Changes()
+	if update == nil || len(update.Asks) != 1 || update.Asks[0].Quantity != 15 {
+		t.Fatalf("Expected update with 15 shown, got %+v", update)
+	}
+}
+
+func TestOrderBook_IcebergRefillLosesPriority(t *testing.T) {
+	book := matcher.NewOrderBook("AAPL")
+	ice := &matcher.Order{ID: "ice", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 25, DisplayQuantity: 10, Timestamp: 1}
+	book.AddOrder(ice)
+	book.AddOrder(&matcher.Order{ID: "plain", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: 2})
+
+	book.Fill(ice, 10)
+
+	// The refilled slice queues behind the plain order
+	orders := book.Orders("SELL")
+	if len(orders) != 2 || orders[0].ID != "plain" || orders[1].ID != "ice" {
+		t.Fatalf("Expected plain ahead of refilled iceberg, got %v", orders)
+	}
+	if _, asks := book.GetLevels(0); asks[0].Quantity != 15 {
+		t.Errorf("Expected 15 shown after refill, got %v", asks[0].Quantity)
+	}
+
+	// The last slice is smaller than the display size
+	book.Fill(ice, 10)
+	if _, asks := book.GetLevels(0); asks[0].Quantity != 10 {
+		t.Errorf("Expected 5 + 5 shown, got %v", asks[0].Quantity)
+	}
+}
+
+func TestOrderBook_IcebergRefillIsStampedAsNewArrival(t *testing.T) {
+	book := matcher.NewOrderBook("AAPL")
+	ice := &matcher.Order{ID: "ice", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 25, DisplayQuantity: 10, Timestamp: 1}
+	book.AddOrder(ice)
+	book.AddOrder(&matcher.Order{ID: "plain", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: 2})
+
+	book.Fill(ice, 10)
+
+	// An order placed before the refill but reaching the book after it,
+	// e.g. one restored on recovery, still queues ahead of the new slice
+	book.AddOrder(&matcher.Order{ID: "older", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: 3})
+	book.AddOrder(&matcher.Order{ID: "later", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: ice.Timestamp + 1})
+
+	orders := book.Orders("SELL")
+	var ids []string
+	for _, order := range orders {
+		ids = append(ids, order.ID)
+	}
+	if want := "plain older ice later"; strings.Join(ids, " ") != want {
+		t.Fatalf("Expected %s, got %v", want, ids)
+	}
+}
+
+func TestMatchEngine_IcebergTradesThroughReserve(t *testing.T) {
+	engine, matches := algorithmEngine(t, matcher.DefaultAlgorithmConfig)
+
+	engine.AddOrder(&matcher.Order{ID: "ice", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 30, DisplayQuantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "plain", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 10, Timestamp: 2})
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 25, Timestamp: 3})
+
+	// 10 from the first slice, then plain, then 5 from the refill
+	want := []struct {
+		id  string
+		qty float64
+	}{{"ice", 10}, {"plain", 10}, {"ice", 5}}
+	if len(*matches) != len(want) {
+		t.Fatalf("Expected %d matches, got %d", len(want), len(*matches))
+	}
+	for i, w := range want {
+		if match := (*matches)[i]; match.SellOrderID != w.id || match.Quantity != w.qty {
+			t.Errorf("Match %d: expected %s x %v, got %s x %v", i, w.id, w.qty, match.SellOrderID, match.Quantity)
+		}
+	}
+
+	snapshot := engine.Snapshot("AAPL", 0)
+	if len(snapshot.Asks) != 1 || snapshot.Asks[0].Quantity != 5 {
+		t.Errorf("Expected 5 of the slice still shown, got %+v", snapshot.Asks)
+	}
+}
+
+func TestMatchEngine_HiddenOrderNeverShown(t *testing.T) {
+	engine, matches := algorithmEngine(t, matcher.DefaultAlgorithmConfig)
+	var updates []*matcher.BookUpdate
+	engine.SetBookHandler(func(update *matcher.BookUpdate) {
+		updates = append(updates, update)
+	})
+
+	engine.AddOrder(&matcher.Order{ID: "hidden", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 101, Quantity: 20, Hidden: true, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "shown", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: 2})
+
+	snapshot := engine.Snapshot("AAPL", 0)
+	if len(snapshot.Bids) != 1 || snapshot.Bids[0].Price != 100 {
+		t.Fatalf("Expected only the 100 bid shown, got %+v", snapshot.Bids)
+	}
+	for _, update := range updates {
+		for _, level := range update.Bids {
+			if level.Price == 101 {
+				t.Errorf("Hidden price leaked in update %+v", update)
+			}
+		}
+	}
+
+	// The hidden bid still trades at its price
+	engine.AddOrder(&matcher.Order{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 8, Timestamp: 3})
+	if len(*matches) != 1 || (*matches)[0].BuyOrderID != "hidden" || (*matches)[0].Price != 101 {
+		t.Fatalf("Expected sell to trade with hidden bid at 101, got %+v", *matches)
+	}
+}
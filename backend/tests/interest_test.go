@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/interest"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInterest_TieredAnnual(t *testing.T) {
+	rates := interest.Rates{
+		Credit: []interest.Tier{{UpTo: 10000, Rate: 0.01}, {UpTo: 100000, Rate: 0.02}, {Rate: 0.03}},
+		Debit:  0.08,
+	}
+
+	cases := []struct {
+		name    string
+		balance float64
+		want    float64
+	}{
+		{"zero", 0, 0},
+		{"inside the first tier", 5000, 50},
+		{"at a tier boundary", 10000, 100},
+		{"across two tiers", 50000, 100 + 800},
+		{"across every tier", 150000, 100 + 1800 + 1500},
+		{"margin debit", -1000, -80},
+	}
+	for _, tc := range cases {
+		if got := rates.Annual(tc.balance); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	if got := rates.Daily(36500); math.Abs(got-(100+26500*0.02)/365) > 1e-12 {
+		t.Errorf("Expected a 365-day accrual, got %v", got)
+	}
+	if got := rates.Effective(50000); math.Abs(got-0.018) > 1e-12 {
+		t.Errorf("Expected a blended 1.8%%, got %v", got)
+	}
+}
+
+func TestInterest_ScheduleLookup(t *testing.T) {
+	schedule := interest.NewSchedule()
+	live := interest.Rates{Credit: []interest.Tier{{Rate: 0.02}}, Debit: 0.08}
+	schedule.Set("USD", "", live)
+	schedule.Set("USD", "DEMO", interest.Rates{})
+
+	if rates, ok := schedule.Lookup("usd", "LIVE"); !ok || rates.Debit != 0.08 {
+		t.Errorf("Expected live USD to use the currency rates, got %+v", rates)
+	}
+	if rates, ok := schedule.Lookup("USD", "DEMO"); !ok || rates.Annual(1000) != 0 {
+		t.Errorf("Expected demo USD to earn nothing, got %+v", rates)
+	}
+	if _, ok := schedule.Lookup("JPY", ""); ok {
+		t.Error("Expected no rates for an unlisted currency")
+	}
+
+	if rates, ok := interest.Default().Lookup("USD", ""); !ok || rates.Annual(1000) <= 0 {
+		t.Error("Expected built-in USD rates")
+	}
+}
+
+func TestInterestJournal_CreditAndCharge(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	earned := service.InterestJournal(id, "USD", 12.34, "")
+	charged := service.InterestJournal(id, "USD", -5.5, "")
+	for _, entry := range []*model.JournalEntry{earned, charged} {
+		if !entry.Balanced() {
+			t.Fatalf("Expected a balanced entry, got %+v", entry.Lines)
+		}
+	}
+
+	if got := earned.CashDelta(id); got != 12.34 {
+		t.Errorf("Expected interest to credit 12.34, got %v", got)
+	}
+	if got := charged.CashDelta(id); got != -5.5 {
+		t.Errorf("Expected margin interest to debit 5.5, got %v", got)
+	}
+}
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ledgerBalances sums credits minus debits per book across entries
+func ledgerBalances(entries ...*model.JournalEntry) map[model.LedgerAccount]float64 {
+	balances := make(map[model.LedgerAccount]float64)
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			balances[line.Ledger] += line.Credit - line.Debit
+		}
+	}
+	return balances
+}
+
+func TestJournal_BuildersBalance(t *testing.T) {
+	accountID := primitive.NewObjectID()
+	entries := map[string]*model.JournalEntry{
+		"opening":      service.OpeningJournal(accountID, "USD", 1000),
+		"deposit":      service.DepositJournal(accountID, "USD", 250, "Deposit"),
+		"withdraw":     service.WithdrawJournal(accountID, "USD", 100, "Withdraw"),
+		"adjust up":    service.AdjustmentJournal(accountID, "USD", 40, "Reset"),
+		"adjust down":  service.AdjustmentJournal(accountID, "USD", -40, "Reset"),
+		"buy":          service.TradeJournal(accountID, true, 1000, 1, "USD", "BUY AAPL"),
+		"sell":         service.TradeJournal(accountID, false, 1000, 1, "USD", "SELL AAPL"),
+		"settle buy":   service.SettlementJournal(accountID, true, 1000, "USD", "BUY AAPL"),
+		"settle sell":  service.SettlementJournal(accountID, false, 1000, "USD", "SELL AAPL"),
+		"free trade":   service.TradeJournal(accountID, true, 500, 0, "USD", "BUY AAPL"),
+		"odd fraction": service.TradeJournal(accountID, false, 0.1+0.2, 0.0003, "USD", "SELL AAPL"),
+	}
+
+	for name, entry := range entries {
+		if !entry.Balanced() {
+			t.Errorf("%s: expected a balanced entry, got %+v", name, entry.Lines)
+		}
+	}
+}
+
+func TestJournal_CashDeltas(t *testing.T) {
+	accountID := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+
+	cases := []struct {
+		name  string
+		entry *model.JournalEntry
+		want  float64
+	}{
+		{"deposit", service.DepositJournal(accountID, "USD", 250, ""), 250},
+		{"withdraw", service.WithdrawJournal(accountID, "USD", 100, ""), -100},
+		{"adjust down", service.AdjustmentJournal(accountID, "USD", -40, ""), -40},
+		{"buy pays total plus commission", service.TradeJournal(accountID, true, 1000, 1, "USD", ""), -1001},
+		{"sell receives total less commission", service.TradeJournal(accountID, false, 1000, 1, "USD", ""), 999},
+		{"settlement leaves cash alone", service.SettlementJournal(accountID, true, 1000, "USD", ""), 0},
+	}
+
+	for _, tc := range cases {
+		if got := tc.entry.CashDelta(accountID); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%s: expected cash delta %v, got %v", tc.name, tc.want, got)
+		}
+		if got := tc.entry.CashDelta(other); got != 0 {
+			t.Errorf("%s: expected no effect on another account, got %v", tc.name, got)
+		}
+	}
+}
+
+func TestJournal_TradeRoundTripClearsPendingSettlement(t *testing.T) {
+	accountID := primitive.NewObjectID()
+	balances := ledgerBalances(
+		service.TradeJournal(accountID, true, 1000, 1, "USD", ""),
+		service.SettlementJournal(accountID, true, 1000, "USD", ""),
+		service.TradeJournal(accountID, false, 1200, 1.2, "USD", ""),
+		service.SettlementJournal(accountID, false, 1200, "USD", ""),
+	)
+
+	if math.Abs(balances[model.LedgerPendingSettlement]) > 1e-9 {
+		t.Errorf("Expected pending settlement to clear, got %v", balances[model.LedgerPendingSettlement])
+	}
+	if math.Abs(balances[model.LedgerFeeRevenue]-2.2) > 1e-9 {
+		t.Errorf("Expected fee revenue 2.2, got %v", balances[model.LedgerFeeRevenue])
+	}
+	if math.Abs(balances[model.LedgerUserCash]-197.8) > 1e-9 {
+		t.Errorf("Expected user cash +197.8, got %v", balances[model.LedgerUserCash])
+	}
+
+	total := 0.0
+	for _, balance := range balances {
+		total += balance
+	}
+	if math.Abs(total) > 1e-9 {
+		t.Errorf("Expected the books to sum to zero, got %v", total)
+	}
+}
+
+func TestJournal_FreeTradeOmitsFeeLine(t *testing.T) {
+	entry := service.TradeJournal(primitive.NewObjectID(), true, 500, 0, "USD", "")
+	for _, line := range entry.Lines {
+		if line.Ledger == model.LedgerFeeRevenue {
+			t.Errorf("Expected no fee line for a free trade, got %+v", line)
+		}
+	}
+}
+
+func TestJournal_RejectsUnbalancedEntries(t *testing.T) {
+	accountID := primitive.NewObjectID()
+	cases := map[string]*model.JournalEntry{
+		"empty": {Type: model.JournalTypeAdjustment},
+		"one-sided": {Lines: []model.JournalLine{
+			{Ledger: model.LedgerUserCash, AccountID: &accountID, Credit: 10},
+		}},
+		"mismatched": {Lines: []model.JournalLine{
+			{Ledger: model.LedgerClearing, Debit: 10},
+			{Ledger: model.LedgerUserCash, AccountID: &accountID, Credit: 9},
+		}},
+		"two-sided line": {Lines: []model.JournalLine{
+			{Ledger: model.LedgerClearing, Debit: 10, Credit: 10},
+		}},
+		"negative": {Lines: []model.JournalLine{
+			{Ledger: model.LedgerClearing, Debit: -10},
+			{Ledger: model.LedgerUserCash, AccountID: &accountID, Credit: -10},
+		}},
+	}
+
+	for name, entry := range cases {
+		if entry.Balanced() {
+			t.Errorf("%s: expected the entry to be rejected", name)
+		}
+	}
+}
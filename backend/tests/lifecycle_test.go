@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	orderService "github.com/bricksocoolxd/bengi-investment-system/module/order/service"
+	tradeService "github.com/bricksocoolxd/bengi-investment-system/module/trade/service"
+)
+
+func TestLifecycle_CheckActive(t *testing.T) {
+	cases := []struct {
+		status model.AccountStatus
+		want   error
+	}{
+		{model.AccountStatusActive, nil},
+		{"", nil}, // Accounts created before statuses were kept
+		{model.AccountStatusFrozen, service.ErrAccountFrozen},
+		{model.AccountStatusClosed, service.ErrAccountClosed},
+		{model.AccountStatusClosing, service.ErrAccountClosed},
+	}
+	for _, tc := range cases {
+		if err := service.CheckActive(&model.Account{Status: tc.status}); !errors.Is(err, tc.want) {
+			t.Errorf("%q: expected %v, got %v", tc.status, tc.want, err)
+		}
+	}
+}
+
+func TestLifecycle_OrderAndTradeErrorsMatchAccountErrors(t *testing.T) {
+	frozen := service.CheckActive(&model.Account{Status: model.AccountStatusFrozen})
+	closed := service.CheckActive(&model.Account{Status: model.AccountStatusClosed})
+
+	if !errors.Is(frozen, orderService.ErrAccountFrozen) || !errors.Is(frozen, tradeService.ErrAccountFrozen) {
+		t.Error("Expected order and trade services to report a frozen account as ErrAccountFrozen")
+	}
+	if !errors.Is(closed, orderService.ErrAccountClosed) || !errors.Is(closed, tradeService.ErrAccountClosed) {
+		t.Error("Expected order and trade services to report a closed account as ErrAccountClosed")
+	}
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/service"
+)
+
+func marketMakerConfig() *model.MarketMakerConfig {
+	return &model.MarketMakerConfig{
+		Symbol:          "AAPL",
+		Enabled:         true,
+		SpreadBps:       20,
+		LevelSpacingBps: 10,
+		Levels:          2,
+		Size:            5,
+		MaxInventory:    100,
+		SkewBps:         50,
+	}
+}
+
+// quotePrices splits quotes into bid and ask prices, best first
+func quotePrices(quotes []service.Quote) (bids, asks []float64) {
+	for _, quote := range quotes {
+		if quote.Side == "BUY" {
+			bids = append(bids, quote.Price)
+		} else {
+			asks = append(asks, quote.Price)
+		}
+	}
+	return bids, asks
+}
+
+func TestMarketMaker_LaddersAroundReference(t *testing.T) {
+	quotes := service.BuildQuotes(marketMakerConfig(), 100, 0)
+	bids, asks := quotePrices(quotes)
+
+	// 10bps either side, then 10bps per level
+	wantBids := []float64{99.90, 99.80}
+	wantAsks := []float64{100.10, 100.20}
+	if len(bids) != 2 || len(asks) != 2 {
+		t.Fatalf("Expected 2 levels a side, got %d bids %d asks", len(bids), len(asks))
+	}
+	for i := range wantBids {
+		if bids[i] != wantBids[i] {
+			t.Errorf("Bid %d: expected %.2f, got %v", i, wantBids[i], bids[i])
+		}
+		if asks[i] != wantAsks[i] {
+			t.Errorf("Ask %d: expected %.2f, got %v", i, wantAsks[i], asks[i])
+		}
+	}
+	for _, quote := range quotes {
+		if quote.Quantity != 5 {
+			t.Errorf("Expected size 5, got %v", quote.Quantity)
+		}
+	}
+}
+
+func TestMarketMaker_SkewsAgainstInventory(t *testing.T) {
+	config := marketMakerConfig()
+	config.Levels = 1
+
+	flatBids, flatAsks := quotePrices(service.BuildQuotes(config, 100, 0))
+	longBids, longAsks := quotePrices(service.BuildQuotes(config, 100, 50))
+	shortBids, shortAsks := quotePrices(service.BuildQuotes(config, 100, -50))
+
+	// Long: quote lower to sell down; short: quote higher to buy back
+	if !(longBids[0] < flatBids[0] && longAsks[0] < flatAsks[0]) {
+		t.Errorf("Long inventory should lower quotes: flat %v/%v long %v/%v", flatBids, flatAsks, longBids, longAsks)
+	}
+	if !(shortBids[0] > flatBids[0] && shortAsks[0] > flatAsks[0]) {
+		t.Errorf("Short inventory should raise quotes: flat %v/%v short %v/%v", flatBids, flatAsks, shortBids, shortAsks)
+	}
+	// Half the limit skews by half of 50bps
+	if longBids[0] != 99.65 {
+		t.Errorf("Expected skewed bid 99.65, got %v", longBids[0])
+	}
+}
+
+func TestMarketMaker_InventoryLimitStopsOneSide(t *testing.T) {
+	config := marketMakerConfig()
+
+	bids, asks := quotePrices(service.BuildQuotes(config, 100, 100))
+	if len(bids) != 0 || len(asks) != 2 {
+		t.Errorf("At max long expected asks only, got %d bids %d asks", len(bids), len(asks))
+	}
+
+	bids, asks = quotePrices(service.BuildQuotes(config, 100, -100))
+	if len(bids) != 2 || len(asks) != 0 {
+		t.Errorf("At max short expected bids only, got %d bids %d asks", len(bids), len(asks))
+	}
+
+	config.MaxInventory = 0
+	bids, asks = quotePrices(service.BuildQuotes(config, 100, 1000))
+	if len(bids) != 2 || len(asks) != 2 {
+		t.Errorf("Unlimited inventory should quote both sides, got %d bids %d asks", len(bids), len(asks))
+	}
+}
+
+func TestMarketMaker_RoundsToTick(t *testing.T) {
+	config := marketMakerConfig()
+	config.Levels = 1
+	config.TickSize = 0.25
+
+	bids, asks := quotePrices(service.BuildQuotes(config, 100.13, 0))
+	if bids[0] != 100 || asks[0] != 100.25 {
+		t.Errorf("Expected 100.00 / 100.25, got %v / %v", bids[0], asks[0])
+	}
+
+	if quotes := service.BuildQuotes(config, 0, 0); len(quotes) != 0 {
+		t.Errorf("Expected no quotes without a reference price, got %d", len(quotes))
+	}
+}
+
+func TestMatchEngine_Withdraw(t *testing.T) {
+	engine := matcher.NewEngine(func(match *matcher.Match) error { return nil })
+	engine.Start()
+	defer engine.Stop()
+
+	engine.AddOrder(&matcher.Order{ID: "mm-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "mm-2", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 151, Quantity: 10, Timestamp: 2})
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "MARKET", Quantity: 14, Timestamp: 3})
+
+	// Fully filled orders are gone
+	if withdrawn := engine.Withdraw("AAPL", "mm-1"); withdrawn != nil {
+		t.Errorf("Expected nil for filled order, got %+v", withdrawn)
+	}
+
+	withdrawn := engine.Withdraw("AAPL", "mm-2")
+	if withdrawn == nil {
+		t.Fatal("Expected partially filled order to be withdrawn")
+	}
+	if withdrawn.FilledQty != 4 {
+		t.Errorf("Expected filled 4, got %v", withdrawn.FilledQty)
+	}
+	if snapshot := engine.Snapshot("AAPL", 0); len(snapshot.Asks) != 0 {
+		t.Errorf("Expected empty asks after withdraw, got %d", len(snapshot.Asks))
+	}
+	if engine.Withdraw("MSFT", "mm-3") != nil {
+		t.Error("Expected nil for unknown book")
+	}
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+)
+
+// newBenchEngine returns a started engine without circuit breakers
+func newBenchEngine(b *testing.B, handler matcher.MatchHandler) *matcher.Engine {
+	engine := matcher.NewEngine(handler)
+	engine.SetDefaultCircuitBreaker(matcher.CircuitBreakerConfig{})
+	engine.Start()
+	b.Cleanup(engine.Stop)
+	return engine
+}
+
+// BenchmarkEngine_AddCancel measures resting an order and cancelling it
+func BenchmarkEngine_AddCancel(b *testing.B) {
+	engine := newBenchEngine(b, nil)
+
+	// Background liquidity that never crosses
+	for i := 0; i < 100; i++ {
+		engine.AddOrder(&matcher.Order{ID: fmt.Sprintf("bid-%d", i), Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100 - float64(i)*0.01, Quantity: 10, Timestamp: int64(i)})
+		engine.AddOrder(&matcher.Order{ID: fmt.Sprintf("ask-%d", i), Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 101 + float64(i)*0.01, Quantity: 10, Timestamp: int64(i)})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		id := fmt.Sprintf("o-%d", i)
+		engine.AddOrder(&matcher.Order{ID: id, Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 99.5, Quantity: 1, Timestamp: int64(1000 + i)})
+		engine.CancelOrder("AAPL", id)
+	}
+}
+
+// BenchmarkEngine_MatchPairs measures a resting sell followed by a crossing buy
+func BenchmarkEngine_MatchPairs(b *testing.B) {
+	var matches int64
+	engine := newBenchEngine(b, func(match *matcher.Match) error {
+		atomic.AddInt64(&matches, 1)
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ts := int64(2 * i)
+		engine.AddOrder(&matcher.Order{ID: fmt.Sprintf("s-%d", i), Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 1, Timestamp: ts})
+		engine.AddOrder(&matcher.Order{ID: fmt.Sprintf("b-%d", i), Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 1, Timestamp: ts + 1})
+	}
+	b.StopTimer()
+
+	if atomic.LoadInt64(&matches) != int64(b.N) {
+		b.Fatalf("Expected %d matches, got %d", b.N, matches)
+	}
+}
+
+// BenchmarkEngine_ParallelSymbols measures matching across independent books
+func BenchmarkEngine_ParallelSymbols(b *testing.B) {
+	engine := newBenchEngine(b, nil)
+	symbols := []string{"AAPL", "MSFT", "GOOGL", "AMZN", "NVDA", "META", "TSLA", "NFLX"}
+
+	var next int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		symbol := symbols[atomic.AddInt64(&next, 1)%int64(len(symbols))]
+		i := 0
+		for pb.Next() {
+			ts := int64(2 * i)
+			engine.AddOrder(&matcher.Order{ID: fmt.Sprintf("%s-s-%d", symbol, i), Symbol: symbol, Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 1, Timestamp: ts})
+			engine.AddOrder(&matcher.Order{ID: fmt.Sprintf("%s-b-%d", symbol, i), Symbol: symbol, Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 1, Timestamp: ts + 1})
+			i++
+		}
+	})
+}
+
+// BenchmarkOrderBook_AddCancelDeep measures insert and cancel against a
+// book with many resting price levels
+func BenchmarkOrderBook_AddCancelDeep(b *testing.B) {
+	book := matcher.NewOrderBook("AAPL")
+	for i := 0; i < 10000; i++ {
+		book.AddOrder(&matcher.Order{ID: fmt.Sprintf("bid-%d", i), Side: "BUY", Type: "LIMIT", Price: 50 + float64(i)*0.01, Quantity: 1, Timestamp: int64(i)})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		id := fmt.Sprintf("o-%d", i)
+		book.AddOrder(&matcher.Order{ID: id, Side: "BUY", Type: "LIMIT", Price: 75.005, Quantity: 1, Timestamp: int64(20000 + i)})
+		book.RemoveOrder(id)
+	}
+}
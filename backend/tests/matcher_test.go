@@ -1,9 +1,12 @@
 package tests
 
 import (
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
 	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
 )
 
@@ -146,3 +149,351 @@ func TestMatchEngine_NoMatchWhenPricesDontCross(t *testing.T) {
 		t.Errorf("Expected 0 matches (prices don't cross), got %d", len(matches))
 	}
 }
+
+func TestMatchEngine_MarketOrdersWithoutLastPrice(t *testing.T) {
+	var mu sync.Mutex
+	matches := make([]*matcher.Match, 0)
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		mu.Lock()
+		defer mu.Unlock()
+		matches = append(matches, match)
+		return nil
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	count := func() int {
+		time.Sleep(200 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		return len(matches)
+	}
+
+	// Before the first trade two market orders have no price to trade at
+	engine.AddOrder(&matcher.Order{ID: "market-buy", UserID: "buyer-1", Symbol: "AAPL", Side: "BUY", Type: "MARKET", Quantity: 5, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "market-sell", UserID: "seller-1", Symbol: "AAPL", Side: "SELL", Type: "MARKET", Quantity: 5, Timestamp: 2})
+	if n := count(); n != 0 {
+		t.Fatalf("Expected no match without a last price, got %d", n)
+	}
+
+	// A limit order behind them still trades, against the older market order
+	engine.AddOrder(&matcher.Order{ID: "limit-sell", UserID: "seller-2", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: 3})
+	if n := count(); n != 1 {
+		t.Fatalf("Expected 1 match, got %d", n)
+	}
+	if match := matches[0]; match.BuyOrderID != "market-buy" || match.SellOrderID != "limit-sell" || match.Price != 100 {
+		t.Errorf("Expected market-buy x limit-sell @ 100, got %s x %s @ %v", match.BuyOrderID, match.SellOrderID, match.Price)
+	}
+
+	// With a last price two market orders trade at it
+	engine.AddOrder(&matcher.Order{ID: "market-buy-2", UserID: "buyer-2", Symbol: "AAPL", Side: "BUY", Type: "MARKET", Quantity: 5, Timestamp: 4})
+	if n := count(); n != 2 {
+		t.Fatalf("Expected 2 matches, got %d", n)
+	}
+	if match := matches[1]; match.SellOrderID != "market-sell" || match.Price != 100 {
+		t.Errorf("Expected market-sell @ 100, got %s @ %v", match.SellOrderID, match.Price)
+	}
+}
+
+func TestMatchEngine_ExtendedHoursEligibility(t *testing.T) {
+	var mu sync.Mutex
+	matches := make([]*matcher.Match, 0)
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		mu.Lock()
+		defer mu.Unlock()
+		matches = append(matches, match)
+		return nil
+	})
+	engine.SetSessionFunc(func(symbol string) calendar.Phase {
+		return calendar.PhasePreMarket
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	// Best-priced buy has not opted in, so it must be skipped
+	engine.AddOrder(&matcher.Order{ID: "buy-regular", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 151.00, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "buy-ext", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150.00, Quantity: 10, Timestamp: 2, ExtendedHours: true})
+	engine.AddOrder(&matcher.Order{ID: "sell-ext", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 149.00, Quantity: 10, Timestamp: 3, ExtendedHours: true})
+
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	if matches[0].BuyOrderID != "buy-ext" {
+		t.Errorf("Expected extended-hours buy to match, got %s", matches[0].BuyOrderID)
+	}
+
+	err := engine.Eligible(&matcher.Order{Symbol: "AAPL", Type: "MARKET", ExtendedHours: true})
+	if err != calendar.ErrExtendedHoursLimitOnly {
+		t.Errorf("Expected ErrExtendedHoursLimitOnly, got %v", err)
+	}
+}
+
+func TestMatchEngine_MatchesSynchronously(t *testing.T) {
+	matches := make([]*matcher.Match, 0)
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		matches = append(matches, match)
+		return nil
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 4, Timestamp: 2})
+	engine.AddOrder(&matcher.Order{ID: "sell-2", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 6, Timestamp: 3})
+
+	// No waiting: AddOrder returns after the book has matched
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+	if matches[0].SellOrderID != "sell-1" || matches[1].SellOrderID != "sell-2" {
+		t.Errorf("Expected matches in arrival order, got %s then %s", matches[0].SellOrderID, matches[1].SellOrderID)
+	}
+	if stats := engine.GetOrderBookStats("AAPL"); stats["bidDepth"] != 0 || stats["askDepth"] != 0 {
+		t.Errorf("Expected empty book, got %v", stats)
+	}
+}
+
+func TestMatchEngine_RejectedMatchCancelsOrder(t *testing.T) {
+	var cancels []*matcher.Cancellation
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		if match.BuyOrderID == "buy-1" {
+			return &matcher.Rejection{OrderID: "buy-1", Err: errors.New("insufficient balance")}
+		}
+		return nil
+	})
+	engine.SetCancelHandler(func(cancel *matcher.Cancellation) {
+		cancels = append(cancels, cancel)
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	engine.AddOrder(&matcher.Order{ID: "buy-1", UserID: "a", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 151, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "buy-2", UserID: "b", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 2})
+	engine.AddOrder(&matcher.Order{ID: "sell-1", UserID: "c", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 3})
+
+	if len(cancels) != 1 || cancels[0].OrderID != "buy-1" || cancels[0].Reason != matcher.CancelReasonRejected || cancels[0].Remaining != 0 {
+		t.Fatalf("Expected buy-1 cancelled as rejected, got %+v", cancels)
+	}
+	// The sell kept its full quantity and traded with the next buy
+	if stats := engine.GetOrderBookStats("AAPL"); stats["bidDepth"] != 0 || stats["askDepth"] != 0 {
+		t.Errorf("Expected the sell to fill against buy-2, got %v", stats)
+	}
+}
+
+func TestMatchEngine_FailedMatchLeavesBook(t *testing.T) {
+	fail := true
+	matches := 0
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		if fail {
+			return errors.New("database unavailable")
+		}
+		matches++
+		return nil
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	engine.AddOrder(&matcher.Order{ID: "buy-1", UserID: "a", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "sell-1", UserID: "b", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 2})
+	if stats := engine.GetOrderBookStats("AAPL"); stats["bidDepth"] != 1 || stats["askDepth"] != 1 {
+		t.Fatalf("Expected both orders still resting, got %v", stats)
+	}
+
+	fail = false
+	engine.Rematch("AAPL")
+	if matches != 1 {
+		t.Errorf("Expected the match to go through once settlement works, got %d", matches)
+	}
+}
+
+func TestMatchEngine_Rematch(t *testing.T) {
+	var mu sync.Mutex
+	phase := calendar.PhaseClosed
+	matches := 0
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		matches++
+		return nil
+	})
+	engine.SetSessionFunc(func(symbol string) calendar.Phase {
+		mu.Lock()
+		defer mu.Unlock()
+		return phase
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 2})
+	if matches != 0 {
+		t.Fatalf("Expected no matches while closed, got %d", matches)
+	}
+
+	mu.Lock()
+	phase = calendar.PhaseRegular
+	mu.Unlock()
+
+	engine.Rematch("AAPL")
+	if matches != 1 {
+		t.Errorf("Expected 1 match after rematch, got %d", matches)
+	}
+}
+
+func TestMatchEngine_BookUpdatesRebuildSnapshot(t *testing.T) {
+	var updates []*matcher.BookUpdate
+
+	engine := matcher.NewEngine(nil)
+	engine.SetBookHandler(func(update *matcher.BookUpdate) {
+		updates = append(updates, update)
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	engine.AddOrder(&matcher.Order{ID: "b1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "b2", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: 2})
+	engine.AddOrder(&matcher.Order{ID: "a1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 101, Quantity: 3, Timestamp: 3})
+	engine.AddOrder(&matcher.Order{ID: "s1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 12, Timestamp: 4})
+	engine.CancelOrder("AAPL", "a1")
+
+	// Replay the deltas into a local book
+	bids := make(map[float64]float64)
+	asks := make(map[float64]float64)
+	for i, update := range updates {
+		if update.Sequence != uint64(i+1) {
+			t.Fatalf("Expected sequence %d, got %d", i+1, update.Sequence)
+		}
+		for _, level := range update.Bids {
+			bids[level.Price] = level.Quantity
+		}
+		for _, level := range update.Asks {
+			asks[level.Price] = level.Quantity
+		}
+	}
+
+	snapshot := engine.Snapshot("AAPL", 0)
+	if snapshot.Sequence != uint64(len(updates)) {
+		t.Errorf("Expected snapshot sequence %d, got %d", len(updates), snapshot.Sequence)
+	}
+	if len(snapshot.Bids) != 1 || snapshot.Bids[0].Price != 100 || snapshot.Bids[0].Quantity != 3 {
+		t.Fatalf("Expected 3 left at 100, got %+v", snapshot.Bids)
+	}
+	if bids[100] != 3 || asks[101] != 0 || asks[100] != 0 {
+		t.Errorf("Replayed book differs from snapshot: bids=%v asks=%v", bids, asks)
+	}
+}
+
+func TestMatchEngine_AggressorSide(t *testing.T) {
+	matches := make([]*matcher.Match, 0)
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		matches = append(matches, match)
+		return nil
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	// Incoming sell hits a resting bid
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 5, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 5, Timestamp: 2})
+	// Incoming buy lifts a resting offer
+	engine.AddOrder(&matcher.Order{ID: "sell-2", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 151, Quantity: 5, Timestamp: 3})
+	engine.AddOrder(&matcher.Order{ID: "buy-2", Symbol: "AAPL", Side: "BUY", Type: "MARKET", Quantity: 5, Timestamp: 4})
+
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+	if matches[0].AggressorSide != "SELL" {
+		t.Errorf("Expected SELL aggressor, got %q", matches[0].AggressorSide)
+	}
+	if matches[1].AggressorSide != "BUY" {
+		t.Errorf("Expected BUY aggressor, got %q", matches[1].AggressorSide)
+	}
+}
+
+func TestMatchEngine_RestoreRebuildsBook(t *testing.T) {
+	original := matcher.NewEngine(nil)
+	original.Start()
+	original.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 149, Quantity: 10, Timestamp: 1})
+	original.AddOrder(&matcher.Order{ID: "buy-2", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 149, Quantity: 5, Timestamp: 2})
+	original.AddOrder(&matcher.Order{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 151, Quantity: 7, FilledQty: 2, Timestamp: 3})
+	state := original.State("AAPL")
+	want := original.Snapshot("AAPL", 0)
+	original.Stop()
+
+	if state == nil || len(state.Orders) != 3 {
+		t.Fatalf("Expected 3 orders in state, got %+v", state)
+	}
+
+	restored := matcher.NewEngine(nil)
+	restored.Start()
+	defer restored.Stop()
+
+	// Restore out of order: time priority comes from the timestamps
+	restored.Restore(&matcher.BookState{
+		Symbol:   "AAPL",
+		Sequence: state.Sequence,
+		Orders:   []*matcher.Order{state.Orders[2], state.Orders[1], state.Orders[0]},
+	})
+
+	got := restored.Snapshot("AAPL", 0)
+	if len(got.Bids) != 1 || got.Bids[0] != want.Bids[0] {
+		t.Errorf("Expected bids %v, got %v", want.Bids, got.Bids)
+	}
+	if len(got.Asks) != 1 || got.Asks[0].Quantity != 5 {
+		t.Errorf("Expected 5 remaining on the ask, got %v", got.Asks)
+	}
+	if got.Sequence <= want.Sequence {
+		t.Errorf("Expected sequence to continue past %d, got %d", want.Sequence, got.Sequence)
+	}
+	if ids := restored.State("AAPL").Orders; ids[0].ID != "buy-1" || ids[1].ID != "buy-2" {
+		t.Errorf("Expected buy-1 ahead of buy-2, got %s, %s", ids[0].ID, ids[1].ID)
+	}
+}
+
+func TestMatchEngine_RestoreThenRematch(t *testing.T) {
+	matches := make([]*matcher.Match, 0)
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		matches = append(matches, match)
+		return nil
+	})
+
+	engine.Start()
+	defer engine.Stop()
+
+	// Crossing orders left unmatched when the process died
+	engine.Restore(&matcher.BookState{
+		Symbol: "AAPL",
+		Orders: []*matcher.Order{
+			{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 1},
+			{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 2},
+			{ID: "sell-2", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 10, FilledQty: 10, Timestamp: 3},
+		},
+	})
+	if len(matches) != 0 {
+		t.Fatalf("Expected Restore not to match, got %d matches", len(matches))
+	}
+
+	engine.Rematch("AAPL")
+	if len(matches) != 1 || matches[0].SellOrderID != "sell-1" {
+		t.Fatalf("Expected sell-1 to match after rematch, got %+v", matches)
+	}
+	if stats := engine.GetOrderBookStats("AAPL"); stats["bidDepth"] != 0 || stats["askDepth"] != 0 {
+		t.Errorf("Expected fully filled orders to be skipped, got %v", stats)
+	}
+}
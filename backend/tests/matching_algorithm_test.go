@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+)
+
+// algorithmEngine returns a started engine using config for every book,
+// with the circuit breaker off, recording every match
+func algorithmEngine(t *testing.T, config matcher.AlgorithmConfig) (*matcher.Engine, *[]*matcher.Match) {
+	t.Helper()
+	matches := make([]*matcher.Match, 0)
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		matches = append(matches, match)
+		return nil
+	})
+	engine.SetDefaultCircuitBreaker(matcher.CircuitBreakerConfig{})
+	engine.SetDefaultAlgorithm(config)
+	engine.Start()
+	t.Cleanup(engine.Stop)
+	return engine, &matches
+}
+
+// filledBy sums matched quantity per resting sell order
+func filledBy(matches []*matcher.Match) map[string]float64 {
+	filled := make(map[string]float64)
+	for _, match := range matches {
+		filled[match.SellOrderID] += match.Quantity
+	}
+	return filled
+}
+
+func TestMatchEngine_RestingPriceWins(t *testing.T) {
+	engine, matches := algorithmEngine(t, matcher.DefaultAlgorithmConfig)
+
+	// The incoming buy carries an older timestamp than the resting sell,
+	// which must not hand it the buy's price
+	engine.AddOrder(&matcher.Order{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 5, Timestamp: 10})
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 105, Quantity: 5, Timestamp: 1})
+
+	if len(*matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(*matches))
+	}
+	if match := (*matches)[0]; match.Price != 100 || match.AggressorSide != "BUY" {
+		t.Errorf("Expected BUY aggressor at 100, got %s at %.2f", match.AggressorSide, match.Price)
+	}
+}
+
+func TestMatchEngine_ProRata(t *testing.T) {
+	engine, matches := algorithmEngine(t, matcher.AlgorithmConfig{Algorithm: matcher.AlgorithmProRata})
+
+	engine.AddOrder(&matcher.Order{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "sell-2", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 30, Timestamp: 2})
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "MARKET", Quantity: 20, Timestamp: 3})
+
+	filled := filledBy(*matches)
+	if filled["sell-1"] != 5 || filled["sell-2"] != 15 {
+		t.Errorf("Expected 5/15 split, got %v", filled)
+	}
+}
+
+func TestMatchEngine_ProRataLeftoverByTime(t *testing.T) {
+	engine, matches := algorithmEngine(t, matcher.AlgorithmConfig{Algorithm: matcher.AlgorithmProRata, LotSize: 1})
+
+	for i := 1; i <= 3; i++ {
+		engine.AddOrder(&matcher.Order{ID: fmt.Sprintf("sell-%d", i), Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 1, Timestamp: int64(i)})
+	}
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 2, Timestamp: 4})
+
+	// A third of a lot each rounds to nothing, so time priority decides
+	filled := filledBy(*matches)
+	if filled["sell-1"] != 1 || filled["sell-2"] != 1 || filled["sell-3"] != 0 {
+		t.Errorf("Expected sell-1 and sell-2 filled, got %v", filled)
+	}
+}
+
+func TestMatchEngine_TopOrder(t *testing.T) {
+	engine, matches := algorithmEngine(t, matcher.AlgorithmConfig{Algorithm: matcher.AlgorithmTopOrder, TopOrderMax: 4})
+
+	engine.AddOrder(&matcher.Order{ID: "sell-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 101, Quantity: 10, Timestamp: 1})
+	// Improves the offer, so it is the top order at 100
+	engine.AddOrder(&matcher.Order{ID: "sell-2", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 10, Timestamp: 2})
+	engine.AddOrder(&matcher.Order{ID: "sell-3", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 10, Timestamp: 3})
+
+	engine.AddOrder(&matcher.Order{ID: "buy-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 8, Timestamp: 4})
+	filled := filledBy(*matches)
+	if filled["sell-2"] != 4 || filled["sell-3"] != 4 {
+		t.Fatalf("Expected top order capped at 4 then FIFO, got %v", filled)
+	}
+
+	// Once the rest of the level is gone the top order takes the remainder
+	engine.AddOrder(&matcher.Order{ID: "buy-2", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 12, Timestamp: 5})
+	filled = filledBy(*matches)
+	if filled["sell-2"] != 10 || filled["sell-3"] != 10 {
+		t.Errorf("Expected both orders filled, got %v", filled)
+	}
+}
+
+// TestMatchEngine_AlgorithmProperties feeds random order flow through
+// every algorithm and checks invariants after each order
+func TestMatchEngine_AlgorithmProperties(t *testing.T) {
+	configs := []matcher.AlgorithmConfig{
+		{Algorithm: matcher.AlgorithmFIFO},
+		{Algorithm: matcher.AlgorithmProRata},
+		{Algorithm: matcher.AlgorithmProRata, LotSize: 1},
+		{Algorithm: matcher.AlgorithmTopOrder, TopOrderMax: 3},
+	}
+
+	for _, config := range configs {
+		config := config
+		for seed := int64(1); seed <= 5; seed++ {
+			t.Run(fmt.Sprintf("%s/lot=%v/seed=%d", config.Algorithm, config.LotSize, seed), func(t *testing.T) {
+				checkAlgorithmProperties(t, config, seed)
+			})
+		}
+	}
+}
+
+func checkAlgorithmProperties(t *testing.T, config matcher.AlgorithmConfig, seed int64) {
+	const tolerance = 1e-9
+	engine, matches := algorithmEngine(t, config)
+	rnd := rand.New(rand.NewSource(seed))
+
+	orders := make(map[string]*matcher.Order)
+	added := make(map[string]int) // Order ID -> position in the flow
+
+	for i := 0; i < 400; i++ {
+		order := &matcher.Order{
+			ID:        fmt.Sprintf("o-%d", i),
+			UserID:    fmt.Sprintf("user-%d", rnd.Intn(8)),
+			Symbol:    "AAPL",
+			Side:      []string{"BUY", "SELL"}[rnd.Intn(2)],
+			Type:      "LIMIT",
+			Price:     float64(95 + rnd.Intn(11)),
+			Quantity:  float64(1 + rnd.Intn(20)),
+			Timestamp: rnd.Int63n(1000), // Deliberately out of order
+		}
+		switch rnd.Intn(10) {
+		case 0:
+			order.Type = "MARKET"
+			order.Price = 0
+		case 1:
+			order.DisplayQuantity = float64(1 + rnd.Intn(5))
+		case 2:
+			order.Hidden = true
+		}
+		orders[order.ID] = order
+		added[order.ID] = i
+		before := len(*matches)
+
+		engine.AddOrder(order)
+
+		// The book is never left crossed
+		snapshot := engine.Snapshot("AAPL", 1)
+		if len(snapshot.Bids) > 0 && len(snapshot.Asks) > 0 && snapshot.Bids[0].Price >= snapshot.Asks[0].Price {
+			t.Fatalf("Book crossed after %s: bid %.2f ask %.2f", order.ID, snapshot.Bids[0].Price, snapshot.Asks[0].Price)
+		}
+
+		for _, match := range (*matches)[before:] {
+			if match.Quantity <= 0 {
+				t.Fatalf("Non-positive match quantity %v", match.Quantity)
+			}
+			if match.BuyerID == match.SellerID {
+				t.Fatalf("Self-trade between %s and %s", match.BuyOrderID, match.SellOrderID)
+			}
+			// Every match involves the incoming order, at the resting price
+			buy, sell := orders[match.BuyOrderID], orders[match.SellOrderID]
+			resting := buy
+			if match.BuyOrderID == order.ID {
+				resting = sell
+			} else if match.SellOrderID != order.ID {
+				t.Fatalf("Match %s/%s does not involve incoming %s", match.BuyOrderID, match.SellOrderID, order.ID)
+			}
+			if added[resting.ID] >= added[order.ID] {
+				t.Fatalf("Resting order %s arrived after %s", resting.ID, order.ID)
+			}
+			if resting.Type == "LIMIT" && match.Price != resting.Price {
+				t.Fatalf("Expected resting price %.2f, got %.2f", resting.Price, match.Price)
+			}
+			if match.AggressorSide != order.Side {
+				t.Fatalf("Expected aggressor %s, got %s", order.Side, match.AggressorSide)
+			}
+		}
+	}
+
+	// Quantity is conserved: both sides of every match add up to each
+	// order's fills, and no order fills beyond its size
+	matched := make(map[string]float64)
+	for _, match := range *matches {
+		matched[match.BuyOrderID] += match.Quantity
+		matched[match.SellOrderID] += match.Quantity
+	}
+	var bought, sold float64
+	for id, order := range orders {
+		if math.Abs(matched[id]-order.FilledQty) > tolerance {
+			t.Fatalf("%s: matched %v but filled %v", id, matched[id], order.FilledQty)
+		}
+		if order.FilledQty > order.Quantity+tolerance {
+			t.Fatalf("%s overfilled: %v of %v", id, order.FilledQty, order.Quantity)
+		}
+		if order.Side == "BUY" {
+			bought += order.FilledQty
+		} else {
+			sold += order.FilledQty
+		}
+	}
+	if math.Abs(bought-sold) > tolerance {
+		t.Fatalf("Bought %v but sold %v", bought, sold)
+	}
+}
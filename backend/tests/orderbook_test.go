@@ -1,6 +1,9 @@
 package tests
 
 import (
+	"fmt"
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
@@ -102,3 +105,132 @@ func TestOrderBook_PriceTimePriority(t *testing.T) {
 		t.Errorf("Expected best bid 151.00 (highest price), got %f", book.GetBestBid())
 	}
 }
+
+func TestOrderBook_AggregatedLevels(t *testing.T) {
+	book := matcher.NewOrderBook("AAPL")
+
+	book.AddOrder(&matcher.Order{ID: "b1", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 1})
+	book.AddOrder(&matcher.Order{ID: "b2", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 5, Timestamp: 2})
+	book.AddOrder(&matcher.Order{ID: "b3", Side: "BUY", Type: "LIMIT", Price: 149, Quantity: 7, Timestamp: 3})
+	book.AddOrder(&matcher.Order{ID: "s1", Side: "SELL", Type: "LIMIT", Price: 151, Quantity: 3, Timestamp: 4})
+
+	bids, asks := book.GetLevels(0)
+	if len(bids) != 2 || bids[0].Price != 150 || bids[0].Quantity != 15 || bids[0].Orders != 2 {
+		t.Errorf("Unexpected bid levels: %+v", bids)
+	}
+	if len(asks) != 1 || asks[0].Quantity != 3 {
+		t.Errorf("Unexpected ask levels: %+v", asks)
+	}
+
+	if bids, _ := book.GetLevels(1); len(bids) != 1 {
+		t.Errorf("Expected depth limit of 1 level, got %d", len(bids))
+	}
+
+	// Partial fill reduces the level, full fill removes the order
+	b1, _ := book.GetOrder("b1")
+	book.Fill(b1, 4)
+	if bids, _ := book.GetLevels(1); bids[0].Quantity != 11 {
+		t.Errorf("Expected 11 at 150 after partial fill, got %f", bids[0].Quantity)
+	}
+	book.Fill(b1, 6)
+	if _, ok := book.GetOrder("b1"); ok {
+		t.Error("Expected filled order to leave the book")
+	}
+}
+
+func TestOrderBook_FIFOWithinLevel(t *testing.T) {
+	book := matcher.NewOrderBook("AAPL")
+
+	book.AddOrder(&matcher.Order{ID: "late", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 1, Timestamp: 20})
+	book.AddOrder(&matcher.Order{ID: "early", Side: "SELL", Type: "LIMIT", Price: 100, Quantity: 1, Timestamp: 10})
+	book.AddOrder(&matcher.Order{ID: "market", Side: "SELL", Type: "MARKET", Quantity: 1, Timestamp: 30})
+
+	var ids []string
+	for _, o := range book.Orders("SELL") {
+		ids = append(ids, o.ID)
+	}
+	want := []string{"market", "early", "late"}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("Expected priority %v, got %v", want, ids)
+	}
+}
+
+func TestOrderBook_RandomOperationsStayConsistent(t *testing.T) {
+	book := matcher.NewOrderBook("AAPL")
+	rnd := rand.New(rand.NewSource(42))
+	resting := make(map[string]*matcher.Order)
+
+	for i := 0; i < 5000; i++ {
+		if len(resting) > 0 && rnd.Intn(3) == 0 {
+			for id := range resting {
+				if !book.RemoveOrder(id) {
+					t.Fatalf("Expected %s to be removable", id)
+				}
+				delete(resting, id)
+				break
+			}
+			continue
+		}
+
+		order := &matcher.Order{
+			ID:        fmt.Sprintf("o-%d", i),
+			Side:      "BUY",
+			Type:      "LIMIT",
+			Price:     float64(90+rnd.Intn(20)) + 0.5,
+			Quantity:  1,
+			Timestamp: int64(i),
+		}
+		book.AddOrder(order)
+		resting[order.ID] = order
+	}
+
+	// Compare against a naive sort of the remaining orders
+	expected := make([]*matcher.Order, 0, len(resting))
+	for _, o := range resting {
+		expected = append(expected, o)
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		if expected[i].Price != expected[j].Price {
+			return expected[i].Price > expected[j].Price
+		}
+		return expected[i].Timestamp < expected[j].Timestamp
+	})
+
+	got := book.Orders("BUY")
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d orders, got %d", len(expected), len(got))
+	}
+	for i := range got {
+		if got[i].ID != expected[i].ID {
+			t.Fatalf("Order %d: expected %s, got %s", i, expected[i].ID, got[i].ID)
+		}
+	}
+	if bids, _ := book.GetDepth(); bids != len(resting) {
+		t.Errorf("Expected depth %d, got %d", len(resting), bids)
+	}
+}
+
+func TestOrderBook_ChangesAreSequenced(t *testing.T) {
+	book := matcher.NewOrderBook("AAPL")
+
+	if book.Changes() != nil {
+		t.Fatal("Expected no changes on an empty book")
+	}
+
+	book.AddOrder(&matcher.Order{ID: "b1", Side: "BUY", Type: "LIMIT", Price: 100, Quantity: 10, Timestamp: 1})
+	book.AddOrder(&matcher.Order{ID: "b2", Side: "BUY", Type: "LIMIT", Price: 99, Quantity: 5, Timestamp: 2})
+	first := book.Changes()
+	if first.Sequence != 1 || len(first.Bids) != 2 || first.Bids[0].Price != 100 {
+		t.Errorf("Unexpected first update: %+v", first)
+	}
+
+	book.RemoveOrder("b2")
+	second := book.Changes()
+	if second.Sequence != 2 || len(second.Bids) != 1 || second.Bids[0].Quantity != 0 {
+		t.Errorf("Expected removal of 99 at sequence 2, got %+v", second)
+	}
+
+	if snapshot := book.Snapshot(10); snapshot.Sequence != 2 || len(snapshot.Bids) != 1 {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+}
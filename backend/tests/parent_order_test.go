@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	instrumentService "github.com/bricksocoolxd/bengi-investment-system/module/instrument/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/order/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/order/service"
+)
+
+func TestSliceWeights_TWAPIsUniform(t *testing.T) {
+	start := time.Date(2026, 1, 5, 14, 0, 0, 0, time.UTC)
+	weights := service.SliceWeights(model.ParentStrategyTWAP, start, start.Add(time.Hour), 4, nil)
+
+	if len(weights) != 4 {
+		t.Fatalf("Expected 4 weights, got %d", len(weights))
+	}
+	for i, w := range weights {
+		if math.Abs(w-0.25) > 1e-12 {
+			t.Errorf("Slice %d: expected weight 0.25, got %v", i, w)
+		}
+	}
+}
+
+func TestSliceWeights_VWAPFollowsCurve(t *testing.T) {
+	curve := make([]float64, 24*60)
+	// 14:00-14:30 trades three times the volume of 14:30-15:00
+	for m := 14 * 60; m < 14*60+30; m++ {
+		curve[m] = 300
+	}
+	for m := 14*60 + 30; m < 15*60; m++ {
+		curve[m] = 100
+	}
+
+	start := time.Date(2026, 1, 5, 14, 0, 0, 0, time.UTC)
+	weights := service.SliceWeights(model.ParentStrategyVWAP, start, start.Add(time.Hour), 2, curve)
+
+	if math.Abs(weights[0]-0.75) > 1e-12 || math.Abs(weights[1]-0.25) > 1e-12 {
+		t.Errorf("Expected weights [0.75 0.25], got %v", weights)
+	}
+}
+
+func TestSliceWeights_VWAPWithoutVolumeFallsBackToUniform(t *testing.T) {
+	start := time.Date(2026, 1, 5, 2, 0, 0, 0, time.UTC)
+	weights := service.SliceWeights(model.ParentStrategyVWAP, start, start.Add(time.Hour), 3, make([]float64, 24*60))
+
+	for i, w := range weights {
+		if math.Abs(w-1.0/3) > 1e-12 {
+			t.Errorf("Slice %d: expected weight 1/3, got %v", i, w)
+		}
+	}
+}
+
+func TestVolumeCurve_SpreadsAndAveragesBuckets(t *testing.T) {
+	day1 := time.Date(2026, 1, 5, 14, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	candles := []instrumentService.Candle{
+		{Time: day1.Unix(), Volume: 500},
+		{Time: day2.Unix(), Volume: 1500},
+	}
+
+	curve := service.VolumeCurve(candles, 5*time.Minute)
+
+	// (500/5 + 1500/5) / 2 days
+	for m := 14 * 60; m < 14*60+5; m++ {
+		if curve[m] != 200 {
+			t.Errorf("Minute %d: expected 200, got %v", m, curve[m])
+		}
+	}
+	if curve[14*60+5] != 0 || curve[14*60-1] != 0 {
+		t.Error("Expected no volume outside the candle's bucket")
+	}
+}
+
+func TestSliceQuantity_CatchesUpOnShortfall(t *testing.T) {
+	weights := []float64{0.25, 0.25, 0.25, 0.25}
+
+	if got := service.SliceQuantity(100, weights, 0); math.Abs(got-25) > 1e-9 {
+		t.Errorf("Expected first slice 25, got %v", got)
+	}
+	// Nothing filled from the first slice: the rest share all 100
+	if got := service.SliceQuantity(100, weights, 1); math.Abs(got-100.0/3) > 1e-9 {
+		t.Errorf("Expected catch-up slice 33.33, got %v", got)
+	}
+	if got := service.SliceQuantity(40, weights, 3); got != 40 {
+		t.Errorf("Expected last slice to take the remainder 40, got %v", got)
+	}
+}
+
+func TestSlippageBps_SignBySide(t *testing.T) {
+	if got := service.SlippageBps(model.OrderSideBuy, 101, 100); math.Abs(got-100) > 1e-9 {
+		t.Errorf("Buy above benchmark: expected +100 bps, got %v", got)
+	}
+	if got := service.SlippageBps(model.OrderSideSell, 101, 100); math.Abs(got+100) > 1e-9 {
+		t.Errorf("Sell above benchmark: expected -100 bps, got %v", got)
+	}
+	if got := service.SlippageBps(model.OrderSideBuy, 0, 100); got != 0 {
+		t.Errorf("Expected 0 bps without fills, got %v", got)
+	}
+}
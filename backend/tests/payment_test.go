@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/payment"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPayment_SignAndVerify(t *testing.T) {
+	body := []byte(`{"reference":"stub_dep_1","outcome":"SUCCEEDED"}`)
+	signature := payment.Sign("secret", body)
+
+	if err := payment.Verify("secret", body, signature); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+	if err := payment.Verify("other", body, signature); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for the wrong secret, got %v", err)
+	}
+	if err := payment.Verify("secret", append(body, ' '), signature); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a changed body, got %v", err)
+	}
+	if err := payment.Verify("secret", body, "not-hex"); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a malformed signature, got %v", err)
+	}
+	if err := payment.Verify("", body, payment.Sign("", body)); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Errorf("Expected every signature refused without a secret, got %v", err)
+	}
+}
+
+func TestPayment_StubProviderReferences(t *testing.T) {
+	ctx := context.Background()
+	stub := payment.NewStubProvider()
+	req := &payment.Request{TransactionID: primitive.NewObjectID(), Currency: "USD", Amount: 100}
+
+	deposit, _ := stub.CreateDeposit(ctx, req)
+	payout, _ := stub.CreatePayout(ctx, req)
+	if !strings.HasSuffix(deposit, req.TransactionID.Hex()) || deposit == payout {
+		t.Errorf("Expected distinct references for the transaction, got %q and %q", deposit, payout)
+	}
+
+	if err := stub.CancelPayout(ctx, payout); err != nil {
+		t.Errorf("Expected the payout to cancel, got %v", err)
+	}
+	if err := stub.CancelPayout(ctx, deposit); !errors.Is(err, payment.ErrUnknownReference) {
+		t.Errorf("Expected ErrUnknownReference for a deposit, got %v", err)
+	}
+}
+
+func TestPayment_NoProviderRefuses(t *testing.T) {
+	ctx := context.Background()
+	var provider payment.Provider = payment.NoProvider{}
+	req := &payment.Request{TransactionID: primitive.NewObjectID(), Currency: "USD", Amount: 100}
+
+	if _, err := provider.CreateDeposit(ctx, req); !errors.Is(err, payment.ErrNoProvider) {
+		t.Errorf("Expected deposits refused, got %v", err)
+	}
+	if _, err := provider.CreatePayout(ctx, req); !errors.Is(err, payment.ErrNoProvider) {
+		t.Errorf("Expected payouts refused, got %v", err)
+	}
+}
+
+func TestWithdrawalLimits_NeedsReview(t *testing.T) {
+	limits := service.WithdrawalLimits{Single: 10000, Daily: 25000}
+
+	cases := []struct {
+		amount, today float64
+		want          bool
+	}{
+		{5000, 0, false},
+		{10000, 0, false},
+		{10000.01, 0, true},
+		{9000, 16000, false},
+		{9000, 16000.01, true},
+	}
+	for _, tc := range cases {
+		if got := limits.NeedsReview(tc.amount, tc.today); got != tc.want {
+			t.Errorf("%v after %v today: expected %v, got %v", tc.amount, tc.today, tc.want, got)
+		}
+	}
+
+	if (service.WithdrawalLimits{}).NeedsReview(1e9, 1e9) {
+		t.Error("Expected zero limits not to require review")
+	}
+}
+
+func TestWithdrawalJournals_HoldThenPayOrRelease(t *testing.T) {
+	id := primitive.NewObjectID()
+	hold := service.WithdrawalHoldJournal(id, "USD", 250, "")
+	release := service.WithdrawalReleaseJournal(id, "USD", 250, "")
+	payout := service.WithdrawalPayoutJournal(id, "USD", 250, "")
+
+	for _, entry := range []*model.JournalEntry{hold, release, payout} {
+		if !entry.Balanced() {
+			t.Fatalf("Expected a balanced %s entry, got %+v", entry.Type, entry.Lines)
+		}
+	}
+
+	// Cash leaves when held; a release returns it and a payout leaves it gone
+	if got := hold.CashDelta(id); got != -250 {
+		t.Errorf("Expected the hold to take 250, got %v", got)
+	}
+	if got := release.CashDelta(id); got != 250 {
+		t.Errorf("Expected the release to return 250, got %v", got)
+	}
+	if got := payout.CashDelta(id); got != 0 {
+		t.Errorf("Expected the payout not to touch cash, got %v", got)
+	}
+}
+
+func TestTransaction_IsTerminal(t *testing.T) {
+	open := []model.TransactionStatus{model.TransactionStatusPending, model.TransactionStatusPendingReview}
+	for _, status := range open {
+		if (&model.Transaction{Status: status}).IsTerminal() {
+			t.Errorf("Expected %s to be open", status)
+		}
+	}
+
+	ended := []model.TransactionStatus{
+		model.TransactionStatusCompleted,
+		model.TransactionStatusFailed,
+		model.TransactionStatusCancelled,
+		model.TransactionStatusRejected,
+	}
+	for _, status := range ended {
+		if !(&model.Transaction{Status: status}).IsTerminal() {
+			t.Errorf("Expected %s to be terminal", status)
+		}
+	}
+}
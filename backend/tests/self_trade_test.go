@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/trade/matcher"
+)
+
+// selfTradeEngine records matches and cancellations from a fresh engine
+func selfTradeEngine(t *testing.T) (*matcher.Engine, *[]*matcher.Match, map[string]*matcher.Cancellation) {
+	t.Helper()
+	matches := make([]*matcher.Match, 0)
+	cancels := make(map[string]*matcher.Cancellation)
+
+	engine := matcher.NewEngine(func(match *matcher.Match) error {
+		matches = append(matches, match)
+		return nil
+	})
+	engine.SetCancelHandler(func(cancel *matcher.Cancellation) {
+		cancels[cancel.OrderID] = cancel
+	})
+	engine.Start()
+	t.Cleanup(engine.Stop)
+	return engine, &matches, cancels
+}
+
+func TestSelfTrade_Modes(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      matcher.SelfTradeMode
+		cancelled map[string]float64 // Order ID -> cancelled quantity
+		bids      int
+		asks      int
+	}{
+		{"default cancels newest", "", map[string]float64{"sell-1": 4}, 1, 0},
+		{"cancel newest", matcher.SelfTradeCancelNewest, map[string]float64{"sell-1": 4}, 1, 0},
+		{"cancel oldest", matcher.SelfTradeCancelOldest, map[string]float64{"buy-1": 10}, 0, 1},
+		{"cancel both", matcher.SelfTradeCancelBoth, map[string]float64{"buy-1": 10, "sell-1": 4}, 0, 0},
+		{"decrement and cancel", matcher.SelfTradeDecrementAndCancel, map[string]float64{"buy-1": 4, "sell-1": 4}, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, matches, cancels := selfTradeEngine(t)
+
+			engine.AddOrder(&matcher.Order{ID: "buy-1", UserID: "user-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 1})
+			engine.AddOrder(&matcher.Order{ID: "sell-1", UserID: "user-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 4, Timestamp: 2, SelfTradeMode: tt.mode})
+
+			if len(*matches) != 0 {
+				t.Fatalf("Expected no self-trade, got %d matches", len(*matches))
+			}
+			if len(cancels) != len(tt.cancelled) {
+				t.Fatalf("Expected %d cancellations, got %v", len(tt.cancelled), cancels)
+			}
+			for id, qty := range tt.cancelled {
+				cancel, ok := cancels[id]
+				if !ok || cancel.Quantity != qty || cancel.Reason != matcher.CancelReasonSelfTrade {
+					t.Errorf("Expected %s cancelled by %.0f, got %+v", id, qty, cancel)
+				}
+			}
+
+			stats := engine.GetOrderBookStats("AAPL")
+			if stats["bidDepth"] != tt.bids || stats["askDepth"] != tt.asks {
+				t.Errorf("Expected depth %d/%d, got %v/%v", tt.bids, tt.asks, stats["bidDepth"], stats["askDepth"])
+			}
+		})
+	}
+}
+
+func TestSelfTrade_DecrementLeavesRemainder(t *testing.T) {
+	engine, _, cancels := selfTradeEngine(t)
+
+	engine.AddOrder(&matcher.Order{ID: "buy-1", UserID: "user-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 10, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "sell-1", UserID: "user-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 4, Timestamp: 2, SelfTradeMode: matcher.SelfTradeDecrementAndCancel})
+
+	if cancel := cancels["buy-1"]; cancel == nil || cancel.Remaining != 6 {
+		t.Fatalf("Expected buy-1 to keep 6 working, got %+v", cancel)
+	}
+	if cancel := cancels["sell-1"]; cancel == nil || cancel.Remaining != 0 {
+		t.Fatalf("Expected sell-1 to leave the book, got %+v", cancel)
+	}
+
+	snapshot := engine.Snapshot("AAPL", 0)
+	if len(snapshot.Bids) != 1 || snapshot.Bids[0].Quantity != 6 {
+		t.Errorf("Expected 6 bid at 150, got %v", snapshot.Bids)
+	}
+}
+
+func TestSelfTrade_OtherUsersStillMatch(t *testing.T) {
+	engine, matches, cancels := selfTradeEngine(t)
+
+	engine.AddOrder(&matcher.Order{ID: "buy-1", UserID: "user-1", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 151, Quantity: 5, Timestamp: 1})
+	engine.AddOrder(&matcher.Order{ID: "buy-2", UserID: "user-2", Symbol: "AAPL", Side: "BUY", Type: "LIMIT", Price: 150, Quantity: 5, Timestamp: 2})
+	// user-1 sells into their own best bid: the sell is cancelled, so it
+	// never reaches user-2's bid either
+	engine.AddOrder(&matcher.Order{ID: "sell-1", UserID: "user-1", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 5, Timestamp: 3})
+	// A different seller trades against user-1's bid first
+	engine.AddOrder(&matcher.Order{ID: "sell-2", UserID: "user-3", Symbol: "AAPL", Side: "SELL", Type: "LIMIT", Price: 150, Quantity: 8, Timestamp: 4})
+
+	if _, ok := cancels["sell-1"]; !ok || len(cancels) != 1 {
+		t.Fatalf("Expected only sell-1 cancelled, got %v", cancels)
+	}
+	if len(*matches) != 2 || (*matches)[0].BuyOrderID != "buy-1" || (*matches)[1].BuyOrderID != "buy-2" {
+		t.Fatalf("Expected sell-2 to fill buy-1 then buy-2, got %+v", *matches)
+	}
+}
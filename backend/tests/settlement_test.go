@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	accountModel "github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	instrumentModel "github.com/bricksocoolxd/bengi-investment-system/module/instrument/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/calendar"
+	orderModel "github.com/bricksocoolxd/bengi-investment-system/module/order/model"
+)
+
+func TestCalendar_SettlementDateSkipsClosedDays(t *testing.T) {
+	ny := newYork(t)
+	cal := calendar.Default()
+
+	cases := []struct {
+		name string
+		at   time.Time
+		days int
+		want time.Time
+	}{
+		{"T+1 midweek", time.Date(2026, 3, 10, 11, 0, 0, 0, ny), 1, time.Date(2026, 3, 11, 0, 0, 0, 0, ny)},
+		{"T+1 over a weekend", time.Date(2026, 3, 13, 11, 0, 0, 0, ny), 1, time.Date(2026, 3, 16, 0, 0, 0, 0, ny)},
+		{"T+1 over a holiday", time.Date(2026, 7, 2, 11, 0, 0, 0, ny), 1, time.Date(2026, 7, 6, 0, 0, 0, 0, ny)},
+		{"T+2 over a weekend", time.Date(2026, 3, 12, 11, 0, 0, 0, ny), 2, time.Date(2026, 3, 16, 0, 0, 0, 0, ny)},
+		{"traded on a Saturday", time.Date(2026, 3, 14, 11, 0, 0, 0, ny), 1, time.Date(2026, 3, 17, 0, 0, 0, 0, ny)},
+	}
+	for _, tc := range cases {
+		if got := cal.SettlementDate("NASDAQ", tc.at, tc.days); !got.Equal(tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCalendar_SettlementDateT0AndAlwaysOpen(t *testing.T) {
+	cal := calendar.Default()
+	saturday := time.Date(2026, 3, 14, 15, 4, 0, 0, time.UTC)
+
+	if got := cal.SettlementDate(calendar.ExchangeCrypto, saturday, 0); !got.Equal(saturday) {
+		t.Errorf("Expected T+0 to settle at the trade, got %v", got)
+	}
+
+	got := cal.SettlementDate(calendar.ExchangeCrypto, saturday, 1)
+	if got.Weekday() != time.Sunday || !got.After(saturday) {
+		t.Errorf("Expected crypto T+1 to settle on Sunday, got %v", got)
+	}
+
+	// Unknown venues count weekdays
+	got = cal.SettlementDate("NOWHERE", saturday, 1)
+	if want := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected %v for an unknown exchange, got %v", want, got)
+	}
+}
+
+func TestInstrument_SettlementDays(t *testing.T) {
+	cases := map[instrumentModel.InstrumentType]int{
+		instrumentModel.InstrumentTypeStock:  1,
+		instrumentModel.InstrumentTypeETF:    1,
+		instrumentModel.InstrumentTypeCrypto: 0,
+		instrumentModel.InstrumentTypeForex:  2,
+		"Unknown":                            1,
+	}
+	for instrumentType, want := range cases {
+		instrument := &instrumentModel.Instrument{Type: instrumentType}
+		if got := instrument.SettlementDays(); got != want {
+			t.Errorf("%s: expected T+%d, got T+%d", instrumentType, want, got)
+		}
+	}
+}
+
+func TestAccount_SettledCash(t *testing.T) {
+	cases := []struct {
+		name               string
+		balance, unsettled float64
+		want               float64
+	}{
+		{"all settled", 1000, 0, 1000},
+		{"part unsettled", 1000, 400, 600},
+		{"unsettled spent on a buy", 300, 400, 0},
+	}
+	for _, tc := range cases {
+		account := &accountModel.Account{Balance: tc.balance, UnsettledCash: tc.unsettled}
+		if got := account.SettledCash(); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestAccount_RestrictionLimitsBuyingPower(t *testing.T) {
+	now := time.Now()
+	account := &accountModel.Account{Balance: 1000, UnsettledCash: 400}
+
+	if got := account.BuyingPower(now); got != 1000 {
+		t.Errorf("Expected unrestricted buying power of 1000, got %v", got)
+	}
+
+	until := now.Add(accountModel.GoodFaithRestrictionTime)
+	account.RestrictedUntil = &until
+	if got := account.BuyingPower(now); got != 600 {
+		t.Errorf("Expected restricted buying power of 600, got %v", got)
+	}
+	if account.IsRestricted(until.Add(time.Second)) {
+		t.Error("Expected the restriction to lapse")
+	}
+}
+
+func TestAccount_RecentViolations(t *testing.T) {
+	now := time.Now()
+	account := &accountModel.Account{GoodFaithViolations: []time.Time{
+		now.Add(-400 * 24 * time.Hour),
+		now.Add(-200 * 24 * time.Hour),
+		now.Add(-time.Hour),
+	}}
+	if got := account.RecentViolations(now); got != 2 {
+		t.Errorf("Expected 2 violations within a year, got %d", got)
+	}
+}
+
+func TestAccount_IsCashAccount(t *testing.T) {
+	cases := []struct {
+		name    string
+		account accountModel.Account
+		want    bool
+	}{
+		{"live", accountModel.Account{}, true},
+		{"live unleveraged", accountModel.Account{Type: accountModel.AccountTypeLive, Leverage: 1}, true},
+		{"margin", accountModel.Account{Type: accountModel.AccountTypeLive, Leverage: 2}, false},
+		{"demo", accountModel.Account{Type: accountModel.AccountTypeDemo, Leverage: 1}, false},
+	}
+	for _, c := range cases {
+		if got := c.account.IsCashAccount(); got != c.want {
+			t.Errorf("%s: expected cash account %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestAccount_ReservedCash(t *testing.T) {
+	now := time.Now()
+	account := &accountModel.Account{Balance: 1000, UnsettledCash: 300, ReservedCash: 500}
+
+	if got := account.BuyingPower(now); got != 500 {
+		t.Errorf("Expected held cash out of buying power, got %v", got)
+	}
+	if got := account.FreeCash(); got != 200 {
+		t.Errorf("Expected 200 free to withdraw, got %v", got)
+	}
+
+	until := now.Add(accountModel.GoodFaithRestrictionTime)
+	account.RestrictedUntil = &until
+	if got := account.BuyingPower(now); got != 200 {
+		t.Errorf("Expected restricted buying power of settled cash less holds, got %v", got)
+	}
+}
+
+func TestOrder_ReservedFor(t *testing.T) {
+	order := &orderModel.Order{Quantity: 10, FilledQty: 2, CancelledQty: 3, ReservedCash: 500}
+
+	if got := order.ReservedFor(1); got != 100 {
+		t.Errorf("Expected a fifth of the hold for 1 of 5 open, got %v", got)
+	}
+	if got := order.ReservedFor(5); got != 500 {
+		t.Errorf("Expected the whole hold once the order closes, got %v", got)
+	}
+	if got := (&orderModel.Order{Quantity: 10}).ReservedFor(5); got != 0 {
+		t.Errorf("Expected nothing held for a sell, got %v", got)
+	}
+}
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/statement"
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/pdf"
+)
+
+func sampleStatement() *statement.Statement {
+	start := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	s := &statement.Statement{
+		AccountID:      "acc-1",
+		AccountType:    "LIVE",
+		Currency:       "USD",
+		PeriodStart:    start,
+		PeriodEnd:      start.AddDate(0, 1, 0),
+		GeneratedAt:    start.AddDate(0, 1, 0),
+		OpeningBalance: 1000,
+		ClosingBalance: 1497.4,
+		Transactions: []statement.Transaction{
+			{Date: start.Add(time.Hour), Type: "DEPOSIT", Status: "COMPLETED", Description: "Deposit", Amount: 500, Change: 500, Balance: 1500},
+			{Date: start.Add(2 * time.Hour), Type: "TRADE", Status: "COMPLETED", Description: "BUY AAPL", Amount: 150, Change: -150, Balance: 1350},
+			{Date: start.Add(2 * time.Hour), Type: "FEE", Status: "COMPLETED", Description: "Fees: BUY AAPL", Amount: 0.15, Change: -0.15, Balance: 1349.85},
+			{Date: start.Add(3 * time.Hour), Type: "TRADE", Status: "COMPLETED", Description: "SELL AAPL", Amount: 160, Change: 160, Balance: 1509.85},
+			{Date: start.Add(4 * time.Hour), Type: "DIVIDEND", Status: "COMPLETED", Description: "AAPL dividend", Amount: 2.55, Change: 2.55, Balance: 1512.4},
+			{Date: start.Add(5 * time.Hour), Type: "TRANSFER", Status: "COMPLETED", Description: "Transfer out", Amount: 20, Change: -20, Balance: 1492.4},
+			{Date: start.Add(6 * time.Hour), Type: "INTEREST", Status: "COMPLETED", Description: "Interest", Amount: 5, Change: 5, Balance: 1497.4},
+			{Date: start.Add(7 * time.Hour), Type: "WITHDRAW", Status: "PENDING_REVIEW", Description: "Withdrawal (held)", Amount: 900},
+		},
+		Trades: []statement.Trade{
+			{Date: start.Add(2 * time.Hour), Symbol: "AAPL", Side: "BUY", Quantity: 1, Price: 150, Total: 150, Commission: 0.15, Currency: "USD"},
+			{Date: start.Add(3 * time.Hour), Symbol: "AAPL", Side: "SELL", Quantity: 1, Price: 160, Total: 160, Commission: 0.16, RegulatoryFees: 0.01, Currency: "USD"},
+		},
+		Positions: []statement.Position{{Symbol: "MSFT", Currency: "USD", Quantity: 2, AvgCost: 300}},
+	}
+	s.Positions[0].MarkAt(310, 1)
+	s.Summary = statement.Summarize(s.Transactions)
+	return s
+}
+
+func TestStatement_Summarize(t *testing.T) {
+	summary := sampleStatement().Summary
+
+	checks := map[string][2]float64{
+		"deposits":      {summary.Deposits, 500},
+		"withdrawals":   {summary.Withdrawals, 0}, // The held withdrawal hasn't completed
+		"transfers out": {summary.TransfersOut, 20},
+		"trading":       {summary.TradingNet, 10},
+		"fees":          {summary.Fees, 0.15},
+		"dividends":     {summary.Dividends, 2.55},
+		"interest":      {summary.Interest, 5},
+	}
+	for name, check := range checks {
+		if math.Abs(check[0]-check[1]) > 1e-9 {
+			t.Errorf("Expected %s of %v, got %v", name, check[1], check[0])
+		}
+	}
+}
+
+func TestStatement_BuildPositions(t *testing.T) {
+	trades := []statement.Trade{
+		{Symbol: "TSLA", Side: "BUY", Quantity: 10, Price: 100, Currency: "USD"},
+		{Symbol: "AAPL", Side: "BUY", Quantity: 5, Price: 150, Currency: "USD"},
+		{Symbol: "TSLA", Side: "BUY", Quantity: 10, Price: 200, Currency: "USD"},
+		{Symbol: "TSLA", Side: "SELL", Quantity: 5, Price: 250, Currency: "USD"},
+		{Symbol: "BTC", Side: "BUY", Quantity: 1, Price: 30000, Currency: "USD"},
+		{Symbol: "BTC", Side: "SELL", Quantity: 1, Price: 31000, Currency: "USD"},
+	}
+
+	positions := statement.BuildPositions(trades)
+	if len(positions) != 2 || positions[0].Symbol != "AAPL" || positions[1].Symbol != "TSLA" {
+		t.Fatalf("Expected open AAPL and TSLA positions in order, got %+v", positions)
+	}
+	tsla := positions[1]
+	if tsla.Quantity != 15 || tsla.AvgCost != 150 {
+		t.Errorf("Expected 15 TSLA at an average 150, got %v at %v", tsla.Quantity, tsla.AvgCost)
+	}
+
+	tsla.MarkAt(160, 0.9)
+	if tsla.MarketValue != 2400 || tsla.UnrealizedPnL != 150 || math.Abs(tsla.Value-2160) > 1e-9 {
+		t.Errorf("Expected value 2400, P&L 150 and 2160 converted, got %+v", tsla)
+	}
+}
+
+func TestStatement_CSV(t *testing.T) {
+	s := sampleStatement()
+	content, err := statement.CSV(s)
+	if err != nil {
+		t.Fatalf("Failed to render CSV: %v", err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV: %v", err)
+	}
+
+	values := map[string]string{}
+	sections := map[string]int{}
+	for i, row := range rows {
+		if len(row) == 2 {
+			values[row[0]] = row[1]
+		}
+		if len(row) == 1 && row[0] != "" {
+			sections[row[0]] = i
+		}
+	}
+	if values["Period end"] != "2026-09-30" || values["Opening balance"] != "1000.00" || values["Fees"] != "-0.15" {
+		t.Errorf("Unexpected header or summary: %v", values)
+	}
+	if values["Positions value"] != "620.00" || values["Total value"] != "2117.40" {
+		t.Errorf("Expected positions valued into the total, got %v", values)
+	}
+
+	for name, count := range map[string]int{"Transactions": 8, "Trades": 2, "Positions at 2026-09-30": 1} {
+		start, ok := sections[name]
+		if !ok {
+			t.Errorf("Missing section %q", name)
+			continue
+		}
+		got := 0
+		for _, row := range rows[start+2:] {
+			if len(row) == 1 {
+				break
+			}
+			got++
+		}
+		if got != count {
+			t.Errorf("Expected %d rows under %q, got %d", count, name, got)
+		}
+	}
+}
+
+func TestStatement_PDF(t *testing.T) {
+	s := sampleStatement()
+	for i := 0; i < 150; i++ {
+		s.Transactions = append(s.Transactions, statement.Transaction{
+			Date: s.PeriodStart.Add(time.Duration(i) * time.Minute), Type: "TRADE", Status: "COMPLETED",
+			Description: fmt.Sprintf("BUY (lot %d)", i), Amount: 1, Change: -1,
+		})
+	}
+
+	content := statement.PDF(s)
+	pages := checkPDF(t, content)
+	if pages < 2 {
+		t.Errorf("Expected a long statement to run over pages, got %d", pages)
+	}
+
+	text := pdfText(t, content)
+	for _, want := range []string{"Account Statement", `BUY \(lot 149\)`, fmt.Sprintf("Page %d of %d", pages, pages), "MSFT"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the PDF to contain %q", want)
+		}
+	}
+}
+
+func TestPDF_Document(t *testing.T) {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.SetTitle("Test")
+	doc.Text(40, 40, pdf.Helvetica, 12, `Price (USD) \ 100€ ✓`)
+	doc.AddPage()
+	doc.Line(40, 40, 200, 40, 1)
+
+	content := doc.Bytes()
+	if pages := checkPDF(t, content); pages != 2 {
+		t.Errorf("Expected 2 pages, got %d", pages)
+	}
+	if text := pdfText(t, content); !strings.Contains(text, `(Price \(USD\) \\ 100\200 ?) Tj`) {
+		t.Errorf("Expected escaped WinAnsi text, got %q", text)
+	}
+	if pdf.CourierWidth("abcd", 10) != 24 {
+		t.Errorf("Expected Courier glyphs 0.6 em wide")
+	}
+
+	if pages := checkPDF(t, pdf.New(pdf.A4Width, pdf.A4Height).Bytes()); pages != 1 {
+		t.Errorf("Expected an empty document to get a blank page, got %d", pages)
+	}
+}
+
+// checkPDF checks the header, trailer and that every xref offset points
+// at its object, and returns the page count
+func checkPDF(t *testing.T, content []byte) int {
+	t.Helper()
+	if !bytes.HasPrefix(content, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(content, []byte("%%EOF\n")) {
+		t.Fatal("Expected a PDF header and trailer")
+	}
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(content)
+	if startxref == nil {
+		t.Fatal("Missing startxref")
+	}
+	offset, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(content[offset:], []byte("xref\n")) {
+		t.Fatal("Expected startxref to point at the xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(content[offset:], -1)
+	for i, entry := range entries {
+		at, _ := strconv.Atoi(string(entry[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(content[at:], []byte(want)) {
+			t.Fatalf("Expected xref entry %d to point at its object", i+1)
+		}
+	}
+
+	count := regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`).FindSubmatch(content)
+	if count == nil {
+		t.Fatal("Missing page tree")
+	}
+	pages, _ := strconv.Atoi(string(count[1]))
+	return pages
+}
+
+// pdfText inflates and joins every content stream
+func pdfText(t *testing.T, content []byte) string {
+	t.Helper()
+	var text strings.Builder
+	for _, match := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(content, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			t.Fatalf("Failed to inflate stream: %v", err)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Failed to inflate stream: %v", err)
+		}
+		text.Write(data)
+	}
+	return text.String()
+}
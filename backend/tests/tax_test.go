@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+	"time"
+
+	portfolioModel "github.com/bricksocoolxd/bengi-investment-system/module/portfolio/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/portfolio/tax"
+)
+
+func taxDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 15, 0, 0, 0, time.UTC)
+}
+
+func taxDisposal(lot tax.Lot, quantity, proceeds, basis float64, disposed time.Time) tax.Disposal {
+	return tax.Disposal{
+		LotID:     lot.ID,
+		Symbol:    lot.Symbol,
+		Currency:  "USD",
+		Quantity:  quantity,
+		Acquired:  lot.Acquired,
+		Disposed:  disposed,
+		Proceeds:  proceeds,
+		CostBasis: basis,
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestTax_Terms(t *testing.T) {
+	acquired := taxDay(2025, time.March, 1)
+	if term := tax.TermOf(acquired, taxDay(2026, time.March, 1)); term != tax.ShortTerm {
+		t.Errorf("Expected a sale on the anniversary to be short-term, got %s", term)
+	}
+	if term := tax.TermOf(acquired, taxDay(2026, time.March, 2)); term != tax.LongTerm {
+		t.Errorf("Expected a sale after the anniversary to be long-term, got %s", term)
+	}
+
+	lot := tax.Lot{ID: "a", Symbol: "AAPL", Quantity: 10, Acquired: taxDay(2025, time.January, 2)}
+	rows := tax.Report([]tax.Lot{lot}, []tax.Disposal{taxDisposal(lot, 10, 1200, 1000, taxDay(2026, time.March, 10))})
+	if len(rows) != 1 || rows[0].Term != tax.LongTerm || !near(rows[0].Gain, 200) || rows[0].Code != "" {
+		t.Errorf("Expected one long-term 200 gain, got %+v", rows)
+	}
+}
+
+func TestTax_WashSaleCarriesIntoReplacement(t *testing.T) {
+	sold := tax.Lot{ID: "a", Symbol: "AAPL", Quantity: 10, Acquired: taxDay(2026, time.January, 1)}
+	replacement := tax.Lot{ID: "b", Symbol: "AAPL", Quantity: 10, Acquired: taxDay(2026, time.February, 15)}
+	other := tax.Lot{ID: "c", Symbol: "MSFT", Quantity: 10, Acquired: taxDay(2026, time.February, 10)}
+
+	rows := tax.Report([]tax.Lot{sold, replacement, other}, []tax.Disposal{
+		taxDisposal(replacement, 10, 900, 850, taxDay(2026, time.June, 1)),
+		taxDisposal(sold, 10, 800, 1000, taxDay(2026, time.February, 1)),
+	})
+	if len(rows) != 2 {
+		t.Fatalf("Expected two rows, got %+v", rows)
+	}
+
+	loss := rows[0]
+	if loss.LotID != "a" || loss.Code != tax.CodeWashSale || !near(loss.Adjustment, 200) || !near(loss.Gain, 0) {
+		t.Errorf("Expected the whole 200 loss disallowed, got %+v", loss)
+	}
+
+	later := rows[1]
+	if !near(later.CostBasis, 1050) || !near(later.Gain, -150) {
+		t.Errorf("Expected the disallowed loss in the replacement's basis, got %+v", later)
+	}
+	if want := replacement.Acquired.Add(-31 * 24 * time.Hour); !later.Acquired.Equal(want) {
+		t.Errorf("Expected the sold shares' holding period carried in, acquired %v, got %v", want, later.Acquired)
+	}
+}
+
+func TestTax_PartialWashSale(t *testing.T) {
+	sold := tax.Lot{ID: "a", Symbol: "TSLA", Quantity: 10, Acquired: taxDay(2026, time.January, 5)}
+	replacement := tax.Lot{ID: "b", Symbol: "TSLA", Quantity: 4, Acquired: taxDay(2026, time.March, 20)}
+
+	rows := tax.Report([]tax.Lot{sold, replacement}, []tax.Disposal{
+		taxDisposal(sold, 10, 800, 1000, taxDay(2026, time.March, 1)),
+		taxDisposal(replacement, 4, 400, 360, taxDay(2026, time.May, 1)),
+	})
+	if !near(rows[0].Adjustment, 80) || !near(rows[0].Gain, -120) {
+		t.Errorf("Expected 4 of 10 shares' loss disallowed, got %+v", rows[0])
+	}
+	if !near(rows[1].CostBasis, 440) || !near(rows[1].Gain, -40) {
+		t.Errorf("Expected 80 carried into the replacement, got %+v", rows[1])
+	}
+}
+
+func TestTax_WashSaleWindowAndReplacementUse(t *testing.T) {
+	sold := tax.Lot{ID: "a", Symbol: "NVDA", Quantity: 10, Acquired: taxDay(2026, time.January, 2)}
+	soldFirst := tax.Lot{ID: "b", Symbol: "NVDA", Quantity: 5, Acquired: taxDay(2026, time.April, 20)}
+	held := tax.Lot{ID: "c", Symbol: "NVDA", Quantity: 5, Acquired: taxDay(2026, time.April, 25)}
+	tooLate := tax.Lot{ID: "d", Symbol: "NVDA", Quantity: 10, Acquired: taxDay(2026, time.June, 20)}
+
+	rows := tax.Report([]tax.Lot{sold, soldFirst, held, tooLate}, []tax.Disposal{
+		taxDisposal(soldFirst, 5, 600, 500, taxDay(2026, time.May, 5)),
+		taxDisposal(sold, 10, 700, 1000, taxDay(2026, time.May, 15)),
+	})
+	// Only the lot still held at the sale and bought inside the window
+	// replaces: 5 of the 10 shares
+	loss := rows[1]
+	if !near(loss.Adjustment, 150) || !near(loss.Gain, -150) {
+		t.Errorf("Expected half the loss disallowed, got %+v", loss)
+	}
+
+	// A replacement share absorbs one wash sale only
+	first := tax.Lot{ID: "e", Symbol: "AMD", Quantity: 10, Acquired: taxDay(2025, time.November, 3)}
+	second := tax.Lot{ID: "f", Symbol: "AMD", Quantity: 10, Acquired: taxDay(2025, time.November, 4)}
+	replacement := tax.Lot{ID: "g", Symbol: "AMD", Quantity: 10, Acquired: taxDay(2026, time.February, 10)}
+	rows = tax.Report([]tax.Lot{first, second, replacement}, []tax.Disposal{
+		taxDisposal(first, 10, 900, 1000, taxDay(2026, time.February, 1)),
+		taxDisposal(second, 10, 900, 1000, taxDay(2026, time.February, 2)),
+	})
+	if rows[0].Code != tax.CodeWashSale || rows[1].Code != "" || !near(rows[1].Gain, -100) {
+		t.Errorf("Expected only the first loss washed, got %+v", rows)
+	}
+
+	// The lot sold can't be its own replacement
+	alone := tax.Lot{ID: "h", Symbol: "META", Quantity: 10, Acquired: taxDay(2026, time.January, 1)}
+	rows = tax.Report([]tax.Lot{alone}, []tax.Disposal{taxDisposal(alone, 10, 900, 1000, taxDay(2026, time.January, 10))})
+	if rows[0].Code != "" || !near(rows[0].Gain, -100) {
+		t.Errorf("Expected no wash sale without another purchase, got %+v", rows[0])
+	}
+}
+
+func TestTax_YearTotalsAndCSV(t *testing.T) {
+	old := tax.Lot{ID: "a", Symbol: "AAPL", Quantity: 10, Acquired: taxDay(2024, time.June, 1)}
+	recent := tax.Lot{ID: "b", Symbol: "AAPL", Quantity: 5, Acquired: taxDay(2026, time.January, 10)}
+	sold := tax.Lot{ID: "c", Symbol: "MSFT", Quantity: 2, Acquired: taxDay(2025, time.December, 1)}
+
+	rows := tax.Report([]tax.Lot{old, recent, sold}, []tax.Disposal{
+		taxDisposal(sold, 2, 500, 600, taxDay(2025, time.December, 20)),
+		taxDisposal(recent, 5, 550, 500, taxDay(2026, time.March, 3)),
+		taxDisposal(old, 10, 1500, 1000, taxDay(2026, time.February, 1)),
+	})
+	rows = tax.InYear(rows, 2026)
+	if len(rows) != 2 {
+		t.Fatalf("Expected the 2026 disposals only, got %+v", rows)
+	}
+
+	totals := tax.Summarize(rows)
+	if len(totals) != 2 || totals[0].Term != tax.ShortTerm || !near(totals[0].Gain, 50) || !near(totals[1].Gain, 500) {
+		t.Errorf("Expected short-term 50 then long-term 500, got %+v", totals)
+	}
+
+	content, err := tax.CSV(rows)
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV: %v", err)
+	}
+	if len(records) != 3 || records[0][1] != "Description of property" {
+		t.Fatalf("Expected a header and two rows, got %v", records)
+	}
+	want := []string{"I", "5 sh AAPL", "01/10/2026", "03/03/2026", "550.00", "500.00", "", "", "50.00", "USD"}
+	for i, field := range want {
+		if records[1][i] != field {
+			t.Errorf("Short-term row field %d: expected %q, got %q", i, field, records[1][i])
+		}
+	}
+	if records[2][0] != "II" || records[2][8] != "500.00" {
+		t.Errorf("Expected the long-term row in Part II, got %v", records[2])
+	}
+}
+
+func TestTax_LotCostBasisIncludesFees(t *testing.T) {
+	lot := &portfolioModel.PositionLot{Quantity: 10, CostPerUnit: 100, Fees: 2}
+	if basis := lot.CostBasis(4); !near(basis, 400.8) {
+		t.Errorf("Expected 400 plus 4/10 of the fees, got %v", basis)
+	}
+}
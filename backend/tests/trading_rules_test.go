@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/instrument/model"
+)
+
+func TestTradingRules_Violations(t *testing.T) {
+	stock := &model.Instrument{Symbol: "AAPL", Type: model.InstrumentTypeStock}
+	rules := stock.EffectiveTradingRules()
+
+	cases := []struct {
+		name      string
+		quantity  float64
+		price     float64
+		lastPrice float64
+		want      string
+	}{
+		{"valid limit", 10, 150.01, 150, ""},
+		{"off tick", 10, 150.005, 150, model.RuleCodeTickSize},
+		{"below min", 0.00001, 150, 150, model.RuleCodeMinQuantity},
+		{"fractional precision", 1.23456, 150, 150, model.RuleCodePrecision},
+		{"above max", 2000000, 150, 150, model.RuleCodeMaxQuantity},
+		{"below notional", 0.001, 150, 150, model.RuleCodeMinNotional},
+		{"outside band", 10, 200, 150, model.RuleCodePriceBand},
+		{"no reference price", 10, 200, 0, ""},
+	}
+
+	for _, tc := range cases {
+		violation := rules.ValidateOrder(tc.quantity, tc.price, 0, tc.lastPrice)
+		got := ""
+		if violation != nil {
+			got = violation.Code
+		}
+		if got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestTradingRules_InstrumentOverride(t *testing.T) {
+	instrument := &model.Instrument{
+		Symbol:       "BRK.A",
+		Type:         model.InstrumentTypeStock,
+		TradingRules: &model.TradingRules{LotSize: 1, MinNotional: 1000},
+	}
+	rules := instrument.EffectiveTradingRules()
+
+	if rules.TickSize != 0.01 {
+		t.Errorf("Expected default tick size 0.01, got %f", rules.TickSize)
+	}
+	if v := rules.ValidateOrder(1.5, 100, 0, 0); v == nil || v.Code != model.RuleCodeLotSize {
+		t.Errorf("Expected lot size violation, got %v", v)
+	}
+	if v := rules.ValidateOrder(5, 100, 0, 0); v == nil || v.Code != model.RuleCodeMinNotional {
+		t.Errorf("Expected min notional violation, got %v", v)
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/model"
+	"github.com/bricksocoolxd/bengi-investment-system/module/account/service"
+	"github.com/bricksocoolxd/bengi-investment-system/module/market/fx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFX_StaticProviderCrossesThroughUSD(t *testing.T) {
+	rates := fx.NewStaticProvider(map[string]float64{"EUR": 0.5, "THB": 35})
+
+	cases := []struct {
+		base, quote string
+		want        float64
+	}{
+		{"USD", "USD", 1},
+		{"USD", "THB", 35},
+		{"THB", "USD", 1.0 / 35},
+		{"EUR", "THB", 70},
+		{"eur", "usd", 2},
+	}
+	for _, tc := range cases {
+		got, err := rates.Rate(tc.base, tc.quote)
+		if err != nil {
+			t.Fatalf("%s/%s: unexpected error %v", tc.base, tc.quote, err)
+		}
+		if math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%s/%s: expected %v, got %v", tc.base, tc.quote, tc.want, got)
+		}
+	}
+
+	if _, err := rates.Rate("USD", "XYZ"); !errors.Is(err, fx.ErrRateUnavailable) {
+		t.Errorf("Expected ErrRateUnavailable for an unknown currency, got %v", err)
+	}
+}
+
+func TestFX_ConvertRoundsToCents(t *testing.T) {
+	rates := fx.NewStaticProvider(map[string]float64{"THB": 35.5})
+
+	converted, rate, err := fx.Convert(rates, 10.01, "USD", "THB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 35.5 || converted != 355.36 {
+		t.Errorf("Expected 355.36 at 35.5, got %v at %v", converted, rate)
+	}
+}
+
+func TestTransferJournal_SameCurrency(t *testing.T) {
+	from, to := primitive.NewObjectID(), primitive.NewObjectID()
+	entry := service.TransferJournal(from, "USD", 100, to, "USD", 100, "")
+
+	if !entry.Balanced() {
+		t.Fatalf("Expected a balanced entry, got %+v", entry.Lines)
+	}
+	if len(entry.Lines) != 2 {
+		t.Errorf("Expected no FX lines for a same-currency transfer, got %d lines", len(entry.Lines))
+	}
+	if entry.CashDelta(from) != -100 || entry.CashDelta(to) != 100 {
+		t.Errorf("Expected -100/+100, got %v/%v", entry.CashDelta(from), entry.CashDelta(to))
+	}
+}
+
+func TestTransferJournal_CrossCurrencyBalancesPerCurrency(t *testing.T) {
+	from, to := primitive.NewObjectID(), primitive.NewObjectID()
+	entry := service.TransferJournal(from, "USD", 100, to, "THB", 3550, "")
+
+	if !entry.Balanced() {
+		t.Fatalf("Expected a balanced entry, got %+v", entry.Lines)
+	}
+	if entry.CashDelta(from) != -100 || entry.CashDelta(to) != 3550 {
+		t.Errorf("Expected -100 USD/+3550 THB, got %v/%v", entry.CashDelta(from), entry.CashDelta(to))
+	}
+
+	// Without the FX conversion legs the currencies can't balance
+	unbalanced := &model.JournalEntry{Lines: []model.JournalLine{
+		{Ledger: model.LedgerUserCash, AccountID: &from, Currency: "USD", Debit: 100},
+		{Ledger: model.LedgerUserCash, AccountID: &to, Currency: "THB", Credit: 100},
+	}}
+	if unbalanced.Balanced() {
+		t.Error("Expected lines in different currencies not to offset each other")
+	}
+}
+
+func TestTransfer_RefusesDemoToLive(t *testing.T) {
+	demo := &model.Account{Type: model.AccountTypeDemo}
+	live := &model.Account{Type: model.AccountTypeLive}
+	legacy := &model.Account{} // Live accounts created before types were kept
+
+	if err := service.CheckSameType(demo, live); !errors.Is(err, service.ErrTransferAcrossTypes) {
+		t.Errorf("Expected a demo to live transfer to be refused, got %v", err)
+	}
+	if err := service.CheckSameType(legacy, demo); !errors.Is(err, service.ErrTransferAcrossTypes) {
+		t.Errorf("Expected a live to demo transfer to be refused, got %v", err)
+	}
+	if err := service.CheckSameType(legacy, live); err != nil {
+		t.Errorf("Expected live accounts to transfer between each other, got %v", err)
+	}
+}
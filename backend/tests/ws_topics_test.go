@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/bricksocoolxd/bengi-investment-system/pkg/ws"
+)
+
+func TestTopics_UserTopics(t *testing.T) {
+	tests := []struct {
+		topic string
+		user  string
+	}{
+		{ws.TopicOrder("user-1"), "user-1"},
+		{ws.TopicTrade("user-1"), "user-1"},
+		{ws.TopicPortfolio("user-1"), "user-1"},
+		{ws.TopicTrades("AAPL"), ""},
+		{ws.TopicBook("A"), ""},
+		{ws.TopicPrice("AB"), ""},
+	}
+
+	for _, tt := range tests {
+		if !ws.ValidateTopic(tt.topic) {
+			t.Errorf("Expected %q to be valid", tt.topic)
+		}
+		if got := ws.IsUserTopic(tt.topic); got != (tt.user != "") {
+			t.Errorf("IsUserTopic(%q) = %v", tt.topic, got)
+		}
+		if got := ws.GetUserFromTopic(tt.topic); got != tt.user {
+			t.Errorf("GetUserFromTopic(%q) = %q, want %q", tt.topic, got, tt.user)
+		}
+	}
+}